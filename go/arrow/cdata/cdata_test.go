@@ -25,6 +25,7 @@ package cdata
 
 import (
 	"io"
+	"io/ioutil"
 	"runtime"
 	"testing"
 	"time"
@@ -33,8 +34,10 @@ import (
 	"github.com/apache/arrow/go/v8/arrow"
 	"github.com/apache/arrow/go/v8/arrow/array"
 	"github.com/apache/arrow/go/v8/arrow/decimal128"
+	"github.com/apache/arrow/go/v8/arrow/ipc"
 	"github.com/apache/arrow/go/v8/arrow/memory"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSchemaExport(t *testing.T) {
@@ -621,3 +624,67 @@ func TestRecordReaderStream(t *testing.T) {
 		assert.Equal(t, "baz", rec.Column(1).(*array.String).Value(2))
 	}
 }
+
+func TestExportFileRecord(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "b", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+	}, nil)
+
+	bldr := array.NewRecordBuilder(mem, schema)
+	defer bldr.Release()
+
+	recs := make([]arrow.Record, 3)
+	for i := range recs {
+		bldr.Field(0).(*array.Int32Builder).AppendValues([]int32{int32(i), 0, 2}, []bool{true, false, true})
+		lb := bldr.Field(1).(*array.ListBuilder)
+		lb.Append(true)
+		lb.ValueBuilder().(*array.StringBuilder).AppendValues([]string{"foo", "bar"}, nil)
+		lb.AppendNull()
+		lb.Append(true)
+		recs[i] = bldr.NewRecord()
+		defer recs[i].Release()
+	}
+
+	f, err := ioutil.TempFile(t.TempDir(), "go-arrow-cdata-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	w, err := ipc.NewFileWriter(f, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	for _, rec := range recs {
+		require.NoError(t, w.Write(rec))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	var (
+		carr CArrowArray
+		csc  CArrowSchema
+		size = mem.CurrentAlloc()
+	)
+	require.NoError(t, ExportFileRecord(r, 1, &carr, &csc))
+	assert.Greater(t, mem.CurrentAlloc(), size)
+
+	rec, err := ImportCRecordBatch(&carr, &csc)
+	require.NoError(t, err)
+	assert.True(t, isReleased(&carr))
+	assert.True(t, schemaIsReleased(&csc))
+	assert.Truef(t, array.RecordEqual(recs[1], rec), "got=%v\nwant=%v", rec, recs[1])
+
+	// the buffers read from the file are released once the importer
+	// releases the exported array, after the imported record is collected.
+	rec.Release()
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return mem.CurrentAlloc() == size
+	}, 1*time.Second, 10*time.Millisecond)
+
+	assert.Error(t, ExportFileRecord(r, 3, &carr, &csc))
+}
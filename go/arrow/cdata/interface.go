@@ -25,6 +25,7 @@ import (
 	"github.com/apache/arrow/go/v8/arrow"
 	"github.com/apache/arrow/go/v8/arrow/array"
 	"github.com/apache/arrow/go/v8/arrow/arrio"
+	"github.com/apache/arrow/go/v8/arrow/ipc"
 	"github.com/apache/arrow/go/v8/arrow/memory"
 	"golang.org/x/xerrors"
 )
@@ -217,6 +218,23 @@ func ExportArrowRecordBatch(rb arrow.Record, out *CArrowArray, outSchema *CArrow
 	exportArray(arr, out, nil)
 }
 
+// ExportFileRecord reads the i-th record of the Arrow file r and populates
+// the passed in CArrowArray (and optionally the schema too) with it, as for
+// ExportArrowRecordBatch. The buffers of the record are shared without any
+// copy, and are held until the release callback of out is called. See the
+// documentation of ExportArrowRecordBatch for the choice of the allocator
+// the file reader should be opened with.
+func ExportFileRecord(r *ipc.FileReader, i int, out *CArrowArray, outSchema *CArrowSchema) error {
+	rec, err := r.RecordAt(i)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	ExportArrowRecordBatch(rec, out, outSchema)
+	return nil
+}
+
 // ExportArrowArray populates the CArrowArray that is passed in with the pointers to the memory
 // being used by the arrow.Array passed in, in order to share with zero-copy across the C
 // Data Interface. See the documentation for ExportArrowRecordBatch for details on how to ensure
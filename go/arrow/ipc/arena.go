@@ -0,0 +1,202 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/internal/debug"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+)
+
+const (
+	arenaAlign   = 64
+	arenaMinSlab = 4 << 10
+)
+
+// BumpAllocator is a memory.Allocator that carves allocations out of a few
+// large slabs instead of allocating each buffer separately.
+// Individual calls to Free are no-ops: the memory is given back all at once
+// by Reset.
+//
+// Reset only drops the allocator's references to its slabs; slabs are never
+// reused, so buffers that are still referenced elsewhere stay valid and are
+// reclaimed by the garbage collector once unreachable.
+//
+// BumpAllocator is safe to use from multiple goroutines.
+type BumpAllocator struct {
+	mu    sync.Mutex
+	mem   memory.Allocator // allocator for the slabs
+	size  int              // size of the next slab
+	slab  []byte           // current slab
+	off   int              // offset of the first free byte in slab
+	last  int              // offset of the last allocation in slab
+	slabs int              // number of slabs allocated since the last Reset
+}
+
+// NewBumpAllocator returns an allocator whose first slab holds size bytes.
+// Subsequent slabs double in size.
+func NewBumpAllocator(size int) *BumpAllocator {
+	if size < arenaMinSlab {
+		size = arenaMinSlab
+	}
+	return &BumpAllocator{
+		mem:  memory.NewGoAllocator(),
+		size: size,
+		last: -1,
+	}
+}
+
+// Allocate returns a 64-byte aligned slice of size bytes.
+func (a *BumpAllocator) Allocate(size int) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.alloc(size)
+}
+
+func (a *BumpAllocator) alloc(size int) []byte {
+	n := int(paddedLength(int64(size), arenaAlign))
+	if a.off+n > len(a.slab) {
+		slab := a.size
+		for slab < n {
+			slab *= 2
+		}
+		a.slab = a.mem.Allocate(slab)
+		a.off = 0
+		a.size = 2 * slab
+		a.slabs++
+	}
+	a.last = a.off
+	a.off += n
+	return a.slab[a.last : a.last+size : a.last+size]
+}
+
+// Reallocate resizes b to size bytes. The most recent allocation is grown
+// in place when the current slab has room for it.
+func (a *BumpAllocator) Reallocate(size int, b []byte) []byte {
+	if size == len(b) {
+		return b
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.isLast(b) {
+		if end := a.last + int(paddedLength(int64(size), arenaAlign)); end <= len(a.slab) {
+			if size > len(b) {
+				// memory beyond len(b) may have been written through
+				// a previous, larger, view of this allocation.
+				buf := a.slab[a.last+len(b) : a.last+size]
+				for i := range buf {
+					buf[i] = 0
+				}
+			}
+			if end > a.off {
+				a.off = end
+			}
+			return a.slab[a.last : a.last+size : a.last+size]
+		}
+	}
+
+	buf := a.alloc(size)
+	copy(buf, b)
+	return buf
+}
+
+func (a *BumpAllocator) isLast(b []byte) bool {
+	if a.last < 0 || len(b) == 0 || a.last >= len(a.slab) {
+		return false
+	}
+	return &a.slab[a.last] == &b[0]
+}
+
+// Free is a no-op. Memory is released by Reset.
+func (a *BumpAllocator) Free(b []byte) {}
+
+// Reset releases all the slabs of the allocator.
+func (a *BumpAllocator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.slab = nil
+	a.off = 0
+	a.last = -1
+	a.slabs = 0
+}
+
+// arenaSize returns the number of bytes needed to hold the decoded buffers
+// of a record batch, as declared by its metadata and the uncompressed
+// length prefixes of its compressed buffers. The result is capped by limit,
+// if > 0.
+func arenaSize(md *flatbuf.RecordBatch, body []byte, limit int64) int {
+	var (
+		size       int64
+		buf        flatbuf.Buffer
+		compressed = md.Compression(nil) != nil
+	)
+	for i := 0; i < md.BuffersLength(); i++ {
+		md.Buffers(&buf, i)
+		n := buf.Length()
+		if off := buf.Offset(); compressed && n >= 8 && off >= 0 && off+8 <= int64(len(body)) {
+			n = int64(binary.LittleEndian.Uint64(body[off:]))
+			if n == -1 {
+				n = buf.Length() - 8
+			}
+		}
+		if n > 0 {
+			size += paddedLength(n, arenaAlign)
+		}
+		if limit > 0 && size > limit {
+			return int(limit)
+		}
+	}
+	return int(size)
+}
+
+// arenaRecord is a record whose buffers were allocated from its own arena.
+// The arena is reset when the record is released.
+type arenaRecord struct {
+	arrow.Record
+	refCount int64
+	arena    *BumpAllocator
+}
+
+func newArenaRecord(rec arrow.Record, arena *BumpAllocator) *arenaRecord {
+	return &arenaRecord{Record: rec, refCount: 1, arena: arena}
+}
+
+func (rec *arenaRecord) Retain() {
+	atomic.AddInt64(&rec.refCount, 1)
+}
+
+func (rec *arenaRecord) Release() {
+	debug.Assert(atomic.LoadInt64(&rec.refCount) > 0, "too many releases")
+
+	if atomic.AddInt64(&rec.refCount, -1) == 0 {
+		rec.Record.Release()
+		rec.Record = nil
+		rec.arena.Reset()
+	}
+}
+
+var (
+	_ memory.Allocator = (*BumpAllocator)(nil)
+	_ arrow.Record     = (*arenaRecord)(nil)
+)
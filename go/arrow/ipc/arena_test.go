@@ -0,0 +1,179 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBumpAllocator(t *testing.T) {
+	a := NewBumpAllocator(8 << 10)
+
+	var bufs [][]byte
+	for _, size := range []int{1, 10, 64, 100, 3000} {
+		buf := a.Allocate(size)
+		require.Len(t, buf, size)
+		assert.Equal(t, size, cap(buf))
+		assert.Zero(t, uintptr(unsafe.Pointer(&buf[0]))%arenaAlign, "size=%d", size)
+		for i := range buf {
+			buf[i] = 0xFF
+		}
+		bufs = append(bufs, buf)
+	}
+	assert.Equal(t, 1, a.slabs)
+
+	// allocations do not overlap.
+	for _, buf := range bufs {
+		for _, v := range buf {
+			require.Equal(t, byte(0xFF), v)
+		}
+	}
+
+	// the last allocation grows in place, with zeroed new bytes.
+	last := bufs[len(bufs)-1]
+	grown := a.Reallocate(4000, last)
+	assert.Same(t, &last[0], &grown[0])
+	assert.Equal(t, make([]byte, 1000), grown[3000:])
+
+	// other allocations are copied.
+	moved := a.Reallocate(200, bufs[0])
+	assert.NotSame(t, &bufs[0][0], &moved[0])
+	assert.Equal(t, byte(0xFF), moved[0])
+	assert.Equal(t, make([]byte, 199), moved[1:])
+
+	// large allocations get a new slab.
+	big := a.Allocate(64 << 10)
+	assert.Len(t, big, 64<<10)
+	assert.Equal(t, 2, a.slabs)
+
+	a.Reset()
+	assert.Equal(t, 0, a.slabs)
+	assert.Nil(t, a.slab)
+}
+
+func TestFileReaderPerRecordArena(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 100, 0, 250)
+	defer releaseRecords(recs)
+
+	for _, codec := range []CompressionType{-1, CompressionLZ4Frame, CompressionZstd} {
+		t.Run(codec.String(), func(t *testing.T) {
+			raw := writeTestFile(t, recs, WithCompression(codec))
+
+			alloc := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer alloc.AssertSize(t, 0)
+
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(alloc), WithPerRecordArena())
+			require.NoError(t, err)
+			defer r.Close()
+
+			for i := range recs {
+				rec, err := r.RecordAt(i)
+				require.NoError(t, err)
+				assert.True(t, array.RecordEqual(recs[i], rec))
+				assert.Zero(t, alloc.CurrentAlloc(), "record buffers must come from the arena")
+
+				arena := rec.(*arenaRecord).arena
+				assert.NotNil(t, arena.slab)
+
+				rec.Retain()
+				rec.Release()
+				assert.NotNil(t, arena.slab)
+				rec.Release()
+				assert.Nil(t, arena.slab)
+			}
+		})
+	}
+}
+
+func BenchmarkFileReaderRecordAt(b *testing.B) {
+	recs := []arrow.Record{makeWideRecord(memory.NewGoAllocator(), 64, 1000)}
+	defer releaseRecords(recs)
+
+	// buffers of uncompressed files are sliced out of the record body:
+	// only decompressed buffers are allocated.
+	raw := writeTestFile(b, recs, WithCompression(CompressionLZ4Frame))
+
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"arena", []Option{WithPerRecordArena()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			r, err := NewFileReader(bytes.NewReader(raw), bc.opts...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer r.Close()
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rec, err := r.RecordAt(0)
+				if err != nil {
+					b.Fatal(err)
+				}
+				rec.Release()
+			}
+			b.StopTimer()
+
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gc/op")
+		})
+	}
+}
+
+// makeWideRecord returns a record with ncols nullable int64 columns.
+func makeWideRecord(mem memory.Allocator, ncols, nrows int) arrow.Record {
+	fields := make([]arrow.Field, ncols)
+	for i := range fields {
+		fields[i] = arrow.Field{Name: "f" + strconv.Itoa(i), Type: arrow.PrimitiveTypes.Int64, Nullable: true}
+	}
+
+	b := array.NewRecordBuilder(mem, arrow.NewSchema(fields, nil))
+	defer b.Release()
+
+	for i := range fields {
+		fb := b.Field(i).(*array.Int64Builder)
+		for j := 0; j < nrows; j++ {
+			if (i+j)%5 == 0 {
+				fb.AppendNull()
+				continue
+			}
+			fb.Append(int64(i * j))
+		}
+	}
+	return b.NewRecord()
+}
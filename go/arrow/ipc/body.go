@@ -0,0 +1,178 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/bitutil"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"golang.org/x/xerrors"
+)
+
+// RecordBodyBytes returns the body of the i-th record batch, decrypted and
+// decompressed, along with its length, so that it can be shipped to another
+// process, through shared memory for instance.
+//
+// The body of an uncompressed record batch is returned as stored: each
+// buffer sits at the offset declared by the record batch metadata. The
+// buffers of a compressed record batch are decompressed and laid out as in
+// an uncompressed body, in order and aligned on 8 bytes: the metadata
+// describing them is returned by RecordBodyMetadata.
+func (f *FileReader) RecordBodyBytes(i int) ([]byte, int64, error) {
+	blk, md, err := f.recordBodyMeta(i)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r, err := f.recordBody(context.Background(), i, blk)
+	if err != nil {
+		return nil, 0, err
+	}
+	body := make([]byte, blk.Body)
+	if _, err := r.ReadAt(body, 0); err != nil {
+		return nil, 0, xerrors.Errorf("arrow/ipc: could not read body of record %d: %w", i, err)
+	}
+	if f.decrypt != nil {
+		if body, err = f.decryptBody(i, body); err != nil {
+			return nil, 0, err
+		}
+	}
+	if md.Compression(nil) == nil {
+		return body, int64(len(body)), nil
+	}
+
+	codec, err := bodyDecompressor(md, f.opts)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("arrow/ipc: could not decompress record %d: %w", i, err)
+	}
+	defer codec.Close()
+
+	var (
+		src   = ipcSource{meta: md, r: bytes.NewReader(body), codec: codec, mem: f.mem}
+		bufs  = make([][]byte, md.BuffersLength())
+		sizes = make([]int64, len(bufs))
+	)
+	err = func() (err error) {
+		defer func() {
+			if e := recover(); e != nil {
+				err = xerrors.Errorf("arrow/ipc: could not decompress buffer of record %d: %v", i, e)
+			}
+		}()
+		for j := range bufs {
+			buf := src.buffer(j, f.mem)
+			bufs[j] = append([]byte(nil), buf.Bytes()...)
+			sizes[j] = int64(len(bufs[j]))
+			buf.Release()
+		}
+		return nil
+	}()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	layout, n := uncompressedLayout(sizes)
+	out := make([]byte, n)
+	for j, buf := range bufs {
+		copy(out[layout[j].Offset:], buf)
+	}
+	return out, n, nil
+}
+
+// RecordBodyMetadata returns the flatbuffer Message describing the body
+// returned by RecordBodyBytes for the i-th record batch. Together with the
+// schema, it allows a receiver to reconstruct the arrays of the record batch
+// from its body.
+//
+// The metadata of an uncompressed record batch is returned as stored. That
+// of a compressed record batch is rewritten to describe its decompressed
+// buffers: only their uncompressed size prefixes are read.
+func (f *FileReader) RecordBodyMetadata(i int) ([]byte, error) {
+	blk, md, err := f.recordBodyMeta(i)
+	if err != nil {
+		return nil, err
+	}
+	if md.Compression(nil) == nil {
+		meta, err := blk.readMeta(blk.section())
+		if err != nil {
+			return nil, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+		}
+		defer meta.Release()
+		return append([]byte(nil), meta.Bytes()...), nil
+	}
+
+	sizes := make([]int64, md.BuffersLength())
+	err = f.sizePrefixes(i, blk, md, func(j int, buf flatbuf.Buffer, n int64) {
+		if n == -1 {
+			n = buf.Length() - int64(arrow.Int64SizeBytes)
+		}
+		sizes[j] = n
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		node   flatbuf.FieldNode
+		fields = make([]fieldMetadata, md.NodesLength())
+	)
+	for j := range fields {
+		md.Nodes(&node, j)
+		fields[j] = fieldMetadata{Len: node.Length(), Nulls: node.NullCount()}
+	}
+
+	layout, n := uncompressedLayout(sizes)
+	meta := writeRecordMessage(f.mem, md.Length(), n, fields, layout, -1)
+	defer meta.Release()
+	return append([]byte(nil), meta.Bytes()...), nil
+}
+
+// recordBodyMeta reads and checks the block and the metadata of the i-th
+// record.
+func (f *FileReader) recordBodyMeta(i int) (fileBlock, *flatbuf.RecordBatch, error) {
+	if i < 0 || i >= f.NumRecords() {
+		return fileBlock{}, nil, xerrors.Errorf("arrow/ipc: record index %d out of bounds [0, %d)", i, f.NumRecords())
+	}
+	blk, md, compressed, err := f.recordHeader(i)
+	if err != nil {
+		return blk, nil, err
+	}
+	if compressed && md.Compression(nil) == nil {
+		return blk, nil, xerrors.Errorf("arrow/ipc: record %d is compressed with the legacy compression metadata", i)
+	}
+	if err := f.checkBlock(blk, "record", i); err != nil {
+		return blk, nil, err
+	}
+	return blk, md, nil
+}
+
+// uncompressedLayout lays out buffers of the given sizes as in the body of
+// an uncompressed record batch, returning their location and the length of
+// the body.
+func uncompressedLayout(sizes []int64) ([]bufferMetadata, int64) {
+	var (
+		layout = make([]bufferMetadata, len(sizes))
+		offset int64
+	)
+	for j, size := range sizes {
+		layout[j] = bufferMetadata{Offset: offset, Len: size}
+		offset += bitutil.CeilByte64(size)
+	}
+	return layout, offset
+}
@@ -0,0 +1,149 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/bitutil"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderRecordBodyBytes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 100)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"uncompressed", nil},
+		{"lz4", []Option{WithLZ4()}},
+		{"zstd", []Option{WithZstd()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewFileReader(bytes.NewReader(writeTestFile(t, recs, tc.opts...)), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			schema, err := r.SchemaBytes()
+			require.NoError(t, err)
+
+			for i := range recs {
+				body, n, err := r.RecordBodyBytes(i)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(body)), n)
+
+				meta, err := r.RecordBodyMetadata(i)
+				require.NoError(t, err)
+
+				msg := flatbuf.GetRootAsMessage(meta, 0)
+				assert.Equal(t, n, msg.BodyLength())
+				var md flatbuf.RecordBatch
+				initFB(&md, msg.Header)
+				assert.Nil(t, md.Compression(nil), "body metadata should not be compressed")
+				decoded, err := r.RecordDecodedSize(i)
+				require.NoError(t, err)
+				var (
+					buf  flatbuf.Buffer
+					size int64
+				)
+				for j := 0; j < md.BuffersLength(); j++ {
+					md.Buffers(&buf, j)
+					require.LessOrEqual(t, buf.Offset()+buf.Length(), n)
+					size += buf.Length()
+				}
+				assert.Equal(t, decoded, size)
+
+				// the receiver sees a stream made of the schema and the
+				// record batch message.
+				stream := append([]byte(nil), schema...)
+				prefix := make([]byte, 8)
+				padded := bitutil.CeilByte64(int64(len(meta)))
+				binary.LittleEndian.PutUint32(prefix, kIPCContToken)
+				binary.LittleEndian.PutUint32(prefix[4:], uint32(padded))
+				stream = append(stream, prefix...)
+				stream = append(stream, meta...)
+				stream = append(stream, make([]byte, padded-int64(len(meta)))...)
+				stream = append(stream, body...)
+
+				sr, err := NewReader(bytes.NewReader(stream), WithAllocator(mem))
+				require.NoError(t, err)
+				require.True(t, sr.Next(), "stream error: %v", sr.Err())
+				assert.Truef(t, array.RecordEqual(recs[i], sr.Record()), "records[%d] differ", i)
+				sr.Release()
+			}
+		})
+	}
+
+	t.Run("encrypted", func(t *testing.T) {
+		raw := writeTestFile(t, recs, WithZstd())
+		xor := func(_ int, body []byte) ([]byte, error) {
+			out := make([]byte, len(body))
+			for i, b := range body {
+				out[i] = b ^ 0x5a
+			}
+			return out, nil
+		}
+
+		plain, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+		require.NoError(t, err)
+		defer plain.Close()
+		want, _, err := plain.RecordBodyBytes(0)
+		require.NoError(t, err)
+
+		// encrypt the body of the first record in place.
+		blk, err := plain.block(0)
+		require.NoError(t, err)
+		enc := append([]byte(nil), raw...)
+		body, _ := xor(0, enc[blk.Offset+int64(blk.Meta):blk.Offset+int64(blk.Meta)+blk.Body])
+		copy(enc[blk.Offset+int64(blk.Meta):], body)
+
+		r, err := NewFileReader(bytes.NewReader(enc), WithAllocator(mem), WithBodyDecryptor(func(i int, body []byte) ([]byte, error) {
+			if i != 0 {
+				return body, nil
+			}
+			return xor(i, body)
+		}))
+		require.NoError(t, err)
+		defer r.Close()
+
+		got, _, err := r.RecordBodyBytes(0)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("out-of-bounds", func(t *testing.T) {
+		r, err := NewFileReader(bytes.NewReader(writeTestFile(t, recs)), WithAllocator(mem))
+		require.NoError(t, err)
+		defer r.Close()
+
+		_, _, err = r.RecordBodyBytes(len(recs))
+		assert.Error(t, err)
+		_, err = r.RecordBodyMetadata(-1)
+		assert.Error(t, err)
+	})
+}
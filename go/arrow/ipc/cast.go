@@ -0,0 +1,206 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"math"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// castedSchema returns schema with the types of the top-level fields listed
+// in casts replaced by their target types.
+func castedSchema(schema *arrow.Schema, casts map[string]arrow.DataType) (*arrow.Schema, error) {
+	if len(casts) == 0 {
+		return schema, nil
+	}
+
+	fields := make([]arrow.Field, len(schema.Fields()))
+	copy(fields, schema.Fields())
+	for name, dt := range casts {
+		idx := schema.FieldIndices(name)
+		if len(idx) == 0 {
+			return nil, xerrors.Errorf("arrow/ipc: no field %q to cast", name)
+		}
+		for _, i := range idx {
+			if !canCast(fields[i].Type, dt) {
+				return nil, xerrors.Errorf("arrow/ipc: cannot cast field %q from %v to %v", name, fields[i].Type, dt)
+			}
+			fields[i].Type = dt
+		}
+	}
+
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md), nil
+}
+
+// canCast reports whether arrays of type from can be cast to type to without
+// losing information, except for timestamps cast to a coarser unit, whose
+// values are checked when cast.
+func canCast(from, to arrow.DataType) bool {
+	if arrow.TypeEqual(from, to) {
+		return true
+	}
+	switch from := from.(type) {
+	case *arrow.BinaryType:
+		return to.ID() == arrow.STRING
+	case *arrow.StringType:
+		return to.ID() == arrow.BINARY
+	case *arrow.TimestampType:
+		to, ok := to.(*arrow.TimestampType)
+		return ok && to.TimeZone == from.TimeZone
+	}
+
+	fsigned, fok := intTypes[from.ID()]
+	tsigned, tok := intTypes[to.ID()]
+	if !fok || !tok {
+		return false
+	}
+	fbits := from.(arrow.FixedWidthDataType).BitWidth()
+	tbits := to.(arrow.FixedWidthDataType).BitWidth()
+	if fsigned && !tsigned {
+		// negative values would be lost.
+		return false
+	}
+	return tbits > fbits
+}
+
+// intTypes maps integer types to whether they are signed.
+var intTypes = map[arrow.Type]bool{
+	arrow.INT8: true, arrow.INT16: true, arrow.INT32: true, arrow.INT64: true,
+	arrow.UINT8: false, arrow.UINT16: false, arrow.UINT32: false, arrow.UINT64: false,
+}
+
+// castArray returns arr cast to type dt, which must be allowed by canCast.
+func castArray(mem memory.Allocator, arr arrow.Array, dt arrow.DataType) (arrow.Array, error) {
+	if arrow.TypeEqual(arr.DataType(), dt) {
+		arr.Retain()
+		return arr, nil
+	}
+
+	switch src := arr.(type) {
+	case *array.Binary, *array.String:
+		data := arr.Data()
+		out := array.NewData(dt, data.Len(), data.Buffers(), nil, data.NullN(), data.Offset())
+		defer out.Release()
+		return array.MakeFromData(out), nil
+	case *array.Timestamp:
+		return castTimestamps(mem, src, dt.(*arrow.TimestampType))
+	default:
+		return castInts(mem, arr, dt), nil
+	}
+}
+
+func castTimestamps(mem memory.Allocator, arr *array.Timestamp, dt *arrow.TimestampType) (arrow.Array, error) {
+	var (
+		from   = arr.DataType().(*arrow.TimestampType).Unit
+		factor = int64(1)
+	)
+	for u := from; u < dt.Unit; u++ {
+		factor *= 1000
+	}
+	for u := dt.Unit; u < from; u++ {
+		factor *= 1000
+	}
+
+	bldr := array.NewTimestampBuilder(mem, dt)
+	defer bldr.Release()
+	bldr.Reserve(arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			bldr.AppendNull()
+			continue
+		}
+		v := int64(arr.Value(i))
+		switch {
+		case dt.Unit > from:
+			if v > math.MaxInt64/factor || v < math.MinInt64/factor {
+				return nil, xerrors.Errorf("timestamp %d%s at index %d overflows unit %s", v, from, i, dt.Unit)
+			}
+			v *= factor
+		case dt.Unit < from:
+			if v%factor != 0 {
+				return nil, xerrors.Errorf("timestamp %d%s at index %d cannot be cast to unit %s without losing precision", v, from, i, dt.Unit)
+			}
+			v /= factor
+		}
+		bldr.UnsafeAppend(arrow.Timestamp(v))
+	}
+	return bldr.NewArray(), nil
+}
+
+func castInts(mem memory.Allocator, arr arrow.Array, dt arrow.DataType) arrow.Array {
+	bldr := array.NewBuilder(mem, dt)
+	defer bldr.Release()
+	bldr.Reserve(arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			bldr.AppendNull()
+			continue
+		}
+		switch b := bldr.(type) {
+		case *array.Int16Builder:
+			b.UnsafeAppend(int16(intAt(arr, i)))
+		case *array.Int32Builder:
+			b.UnsafeAppend(int32(intAt(arr, i)))
+		case *array.Int64Builder:
+			b.UnsafeAppend(intAt(arr, i))
+		case *array.Uint16Builder:
+			b.UnsafeAppend(uint16(uintAt(arr, i)))
+		case *array.Uint32Builder:
+			b.UnsafeAppend(uint32(uintAt(arr, i)))
+		case *array.Uint64Builder:
+			b.UnsafeAppend(uintAt(arr, i))
+		}
+	}
+	return bldr.NewArray()
+}
+
+func intAt(arr arrow.Array, i int) int64 {
+	switch arr := arr.(type) {
+	case *array.Int8:
+		return int64(arr.Value(i))
+	case *array.Int16:
+		return int64(arr.Value(i))
+	case *array.Int32:
+		return int64(arr.Value(i))
+	case *array.Int64:
+		return arr.Value(i)
+	default:
+		return int64(uintAt(arr, i))
+	}
+}
+
+func uintAt(arr arrow.Array, i int) uint64 {
+	switch arr := arr.(type) {
+	case *array.Uint8:
+		return uint64(arr.Value(i))
+	case *array.Uint16:
+		return uint64(arr.Value(i))
+	case *array.Uint32:
+		return uint64(arr.Value(i))
+	case *array.Uint64:
+		return arr.Value(i)
+	default:
+		panic(xerrors.Errorf("arrow/ipc: unexpected integer array %T", arr))
+	}
+}
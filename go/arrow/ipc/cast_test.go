@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// columnFromJSON decodes the JSON array vs into an array of type dt.
+// Timestamps are given as integers in the unit of dt.
+func columnFromJSON(t *testing.T, mem memory.Allocator, dt arrow.DataType, vs string) arrow.Array {
+	t.Helper()
+
+	ts, ok := dt.(*arrow.TimestampType)
+	if !ok {
+		arr, _, err := array.FromJSON(mem, dt, strings.NewReader(vs))
+		require.NoError(t, err)
+		return arr
+	}
+
+	var values []*int64
+	require.NoError(t, json.Unmarshal([]byte(vs), &values))
+
+	b := array.NewTimestampBuilder(mem, ts)
+	defer b.Release()
+	for _, v := range values {
+		if v == nil {
+			b.AppendNull()
+			continue
+		}
+		b.Append(arrow.Timestamp(*v))
+	}
+	return b.NewArray()
+}
+
+// writeColumnFile writes a file with a single record holding a column "col"
+// of type dt, with the values decoded from the JSON array vs.
+func writeColumnFile(t *testing.T, mem memory.Allocator, dt arrow.DataType, vs string) []byte {
+	t.Helper()
+
+	arr := columnFromJSON(t, mem, dt, vs)
+	defer arr.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "col", Type: dt, Nullable: true}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+	defer rec.Release()
+	return writeTestFile(t, []arrow.Record{rec})
+}
+
+func TestFieldCast(t *testing.T) {
+	var (
+		ms  = &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}
+		us  = &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+		ns  = &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}
+		sec = &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}
+	)
+
+	for _, tc := range []struct {
+		name string
+		src  arrow.DataType
+		dst  arrow.DataType
+		vs   string
+		want string
+		err  string
+	}{
+		{
+			name: "int32-int64",
+			src:  arrow.PrimitiveTypes.Int32, dst: arrow.PrimitiveTypes.Int64,
+			vs: `[1, null, -2147483648]`, want: `[1, null, -2147483648]`,
+		},
+		{
+			name: "uint16-int32",
+			src:  arrow.PrimitiveTypes.Uint16, dst: arrow.PrimitiveTypes.Int32,
+			vs: `[0, 65535, null]`, want: `[0, 65535, null]`,
+		},
+		{
+			name: "uint8-uint64",
+			src:  arrow.PrimitiveTypes.Uint8, dst: arrow.PrimitiveTypes.Uint64,
+			vs: `[255, null]`, want: `[255, null]`,
+		},
+		{
+			name: "string-binary",
+			src:  arrow.BinaryTypes.String, dst: arrow.BinaryTypes.Binary,
+			vs: `["a", null, "bcd"]`, want: `["YQ==", null, "YmNk"]`,
+		},
+		{
+			name: "binary-string",
+			src:  arrow.BinaryTypes.Binary, dst: arrow.BinaryTypes.String,
+			vs: `["YQ==", null, ""]`, want: `["a", null, ""]`,
+		},
+		{
+			name: "timestamp-ms-us",
+			src:  ms, dst: us,
+			vs: `[1, null, -2]`, want: `[1000, null, -2000]`,
+		},
+		{
+			name: "timestamp-ns-ms",
+			src:  ns, dst: ms,
+			vs: `[3000000, null, -1000000]`, want: `[3, null, -1]`,
+		},
+		{
+			name: "timestamp-lossy",
+			src:  ns, dst: ms,
+			vs:  `[3000000, 3000001]`,
+			err: `could not cast field "col": timestamp 3000001ns at index 1 cannot be cast to unit ms without losing precision`,
+		},
+		{
+			name: "timestamp-overflow",
+			src:  sec, dst: ns,
+			vs:  `[1, 9223372037]`,
+			err: `could not cast field "col": timestamp 9223372037s at index 1 overflows unit ns`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			raw := writeColumnFile(t, mem, tc.src, tc.vs)
+
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithFieldCast("col", tc.dst))
+			require.NoError(t, err)
+			defer r.Close()
+
+			assert.True(t, arrow.TypeEqual(tc.dst, r.Schema().Field(0).Type))
+
+			rec, err := r.RecordAt(0)
+			if tc.err != "" {
+				assert.Nil(t, rec)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			defer rec.Release()
+
+			assert.True(t, rec.Schema().Equal(r.Schema()))
+
+			want := columnFromJSON(t, mem, tc.dst, tc.want)
+			defer want.Release()
+			assert.Truef(t, array.ArrayEqual(want, rec.Column(0)), "got=%v, want=%v", rec.Column(0), want)
+		})
+	}
+}
+
+func TestFieldCastInvalid(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	raw := writeColumnFile(t, mem, arrow.PrimitiveTypes.Int64, `[1]`)
+
+	for _, tc := range []struct {
+		opt  Option
+		want string
+	}{
+		{WithFieldCast("missing", arrow.PrimitiveTypes.Int64), `no field "missing" to cast`},
+		{WithFieldCast("col", arrow.PrimitiveTypes.Int32), `cannot cast field "col" from int64 to int32`},
+		{WithFieldCast("col", arrow.PrimitiveTypes.Uint64), `cannot cast field "col" from int64 to uint64`},
+		{WithFieldCast("col", arrow.BinaryTypes.String), `cannot cast field "col" from int64 to utf8`},
+	} {
+		_, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), tc.opt)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+)
+
+// coalesceMaxSpan bounds the number of bytes fetched by a coalesced read.
+const coalesceMaxSpan = 4 << 20
+
+// coalescer serves the reads of record blocks from spans of the file, each
+// fetched with a single ReadAt. A span covers the block being read and the
+// blocks following it in the file, as long as they are separated by gaps
+// smaller than the threshold. The last span fetched is kept in memory.
+type coalescer struct {
+	r      io.ReaderAt
+	gap    int64
+	blocks []fileBlock // record blocks, sorted by offset
+
+	mu  sync.Mutex
+	buf []byte // last span fetched
+	off int64  // offset of buf in the file
+}
+
+func newCoalescer(r io.ReaderAt, gap int64, blocks []fileBlock) *coalescer {
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Offset < blocks[j].Offset })
+	return &coalescer{r: r, gap: gap, blocks: blocks}
+}
+
+func (c *coalescer) ReadAt(p []byte, off int64) (int, error) {
+	return c.ReadAtContext(context.Background(), p, off)
+}
+
+func (c *coalescer) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	end := off + int64(len(p))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if off >= c.off && end <= c.off+int64(len(c.buf)) {
+		return copy(p, c.buf[off-c.off:]), nil
+	}
+
+	r := &ctxReaderAt{ctx: ctx, r: c.r}
+
+	// first block ending after off.
+	i := sort.Search(len(c.blocks), func(i int) bool {
+		blk := c.blocks[i]
+		return blk.Offset+int64(blk.Meta)+blk.Body > off
+	})
+	if i == len(c.blocks) || c.blocks[i].Offset > off {
+		return r.ReadAt(p, off)
+	}
+
+	for _, blk := range c.blocks[i:] {
+		if blk.Offset-end >= c.gap && blk.Offset > off {
+			break
+		}
+		blkEnd := blk.Offset + int64(blk.Meta) + blk.Body
+		if blkEnd-off > coalesceMaxSpan && blk.Offset > off {
+			break
+		}
+		if blkEnd > end {
+			end = blkEnd
+		}
+	}
+
+	buf := make([]byte, end-off)
+	if _, err := r.ReadAt(buf, off); err != nil {
+		// let the caller see the error of its own read.
+		return r.ReadAt(p, off)
+	}
+	c.buf, c.off = buf, off
+	return copy(p, buf), nil
+}
@@ -0,0 +1,75 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderCoalesceReads(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 1, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, tc := range []struct {
+		name  string
+		opts  []Option
+		reads int
+	}{
+		{"none", nil, 2 * len(recs)},
+		{"block", []Option{WithCoalesceReads(0)}, len(recs)},
+		{"gap", []Option{WithCoalesceReads(1)}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := &readAtRecorder{Reader: bytes.NewReader(raw)}
+			r, err := NewFileReader(rr, append(tc.opts, WithAllocator(mem))...)
+			require.NoError(t, err)
+			defer r.Close()
+
+			rr.reads = nil
+			for i := range recs {
+				rec, err := r.RecordAt(i)
+				require.NoError(t, err)
+				assert.True(t, array.RecordEqual(recs[i], rec), "record %d", i)
+				rec.Release()
+			}
+			assert.Len(t, rr.reads, tc.reads)
+		})
+	}
+
+	// a span failing to read falls back to the read of the caller, which
+	// succeeds for records before the truncation and fails after it.
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithCoalesceReads(1))
+	require.NoError(t, err)
+	defer r.Close()
+	r.coalesce.r = bytes.NewReader(raw[:r.RecordBlocks()[2].Offset])
+
+	rec, err := r.RecordAt(1)
+	require.NoError(t, err)
+	rec.Release()
+	_, err = r.RecordAt(3)
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+	"io"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"golang.org/x/xerrors"
+)
+
+// ColumnReader reads a single column of a file, one record batch at a time.
+type ColumnReader struct {
+	f   *FileReader
+	col int
+	i   int // index of the next record
+}
+
+// ColumnReader returns a reader over the col-th column of the records of the
+// file, as described by Schema.
+func (f *FileReader) ColumnReader(col int) *ColumnReader {
+	return &ColumnReader{f: f, col: col}
+}
+
+// Next returns the chunk of the column held by the next record of the file,
+// or io.EOF once every record has been read. Only the buffers of the column
+// are decoded: the other columns of the record are skipped.
+//
+// The returned array must be released by the caller.
+func (r *ColumnReader) Next() (arrow.Array, error) {
+	return r.NextContext(context.Background())
+}
+
+// NextContext is like Next but reads the record with the given context.
+func (r *ColumnReader) NextContext(ctx context.Context) (arrow.Array, error) {
+	f := r.f
+	if n := len(f.recSchema.Fields()); r.col < 0 || r.col >= n {
+		return nil, xerrors.Errorf("arrow/ipc: column index %d out of bounds [0, %d)", r.col, n)
+	}
+	if r.i >= f.NumRecords() {
+		return nil, io.EOF
+	}
+
+	// the column outlives its record: it cannot be allocated from the
+	// arena of the record.
+	opts := f.opts
+	opts.arena = false
+	rec, err := f.recordAt(ctx, r.i, opts, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rec.Release()
+
+	var arr arrow.Array
+	func() {
+		defer func() {
+			if e := recover(); e != nil {
+				switch e := e.(type) {
+				case error:
+					err = xerrors.Errorf("arrow/ipc: could not read column %d of record %d: %w", r.col, r.i, e)
+				default:
+					err = xerrors.Errorf("arrow/ipc: could not read column %d of record %d: %v", r.col, r.i, e)
+				}
+			}
+		}()
+		arr = rec.Column(r.col)
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	arr.Retain()
+	r.i++
+	return arr, nil
+}
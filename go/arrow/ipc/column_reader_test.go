@@ -0,0 +1,191 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestFileReaderColumnReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5, 7)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	sum := func(arr *array.Int64) (n int64) {
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsValid(i) {
+				n += arr.Value(i)
+			}
+		}
+		return n
+	}
+
+	var want int64
+	for i := 0; i < r.NumRecords(); i++ {
+		rec, err := r.RecordAt(i)
+		require.NoError(t, err)
+		want += sum(rec.Column(0).(*array.Int64))
+		rec.Release()
+	}
+
+	var (
+		got    int64
+		chunks int
+		cr     = r.ColumnReader(0)
+	)
+	for {
+		arr, err := cr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, recs[chunks].Column(0).Len(), arr.Len())
+		got += sum(arr.(*array.Int64))
+		arr.Release()
+		chunks++
+	}
+	assert.Equal(t, r.NumRecords(), chunks)
+	assert.Equal(t, want, got)
+
+	_, err = cr.Next()
+	assert.Equal(t, io.EOF, err)
+
+	t.Run("strings", func(t *testing.T) {
+		cr := r.ColumnReader(1)
+		for i := range recs {
+			arr, err := cr.Next()
+			require.NoError(t, err)
+			assert.True(t, array.ArrayEqual(recs[i].Column(1), arr), "record %d", i)
+			arr.Release()
+		}
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		for _, col := range []int{-1, 2} {
+			_, err := r.ColumnReader(col).Next()
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("target schema", func(t *testing.T) {
+		target := arrow.NewSchema([]arrow.Field{
+			{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "missing", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		}, nil)
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithTargetSchema(target, true))
+		require.NoError(t, err)
+		defer r.Close()
+
+		cr := r.ColumnReader(0)
+		for i := range recs {
+			arr, err := cr.Next()
+			require.NoError(t, err)
+			assert.True(t, array.ArrayEqual(recs[i].Column(1), arr), "record %d", i)
+			arr.Release()
+		}
+	})
+	t.Run("arena", func(t *testing.T) {
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithPerRecordArena())
+		require.NoError(t, err)
+		defer r.Close()
+
+		// chunks outlive their record, and are not allocated from its arena.
+		cr := r.ColumnReader(1)
+		for i := range recs {
+			arr, err := cr.Next()
+			require.NoError(t, err)
+			assert.True(t, array.ArrayEqual(recs[i].Column(1), arr), "record %d", i)
+			arr.Release()
+		}
+	})
+
+	t.Run("accounting", func(t *testing.T) {
+		var hooked []int
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithLeakCheck(),
+			WithRecordPostHook(func(i int, rec arrow.Record, n int64, err error) {
+				assert.NotNil(t, rec)
+				assert.NoError(t, err)
+				hooked = append(hooked, i)
+			}))
+		require.NoError(t, err)
+
+		cr := r.ColumnReader(1)
+		var held arrow.Array
+		for i := range recs {
+			arr, err := cr.Next()
+			require.NoError(t, err)
+			if i == 0 {
+				held = arr
+				continue
+			}
+			arr.Release()
+		}
+		assert.Equal(t, []int{0, 1, 2}, hooked)
+		assert.EqualValues(t, len(recs), r.Stats().Records)
+
+		// the unreleased chunk is reported by the leak check.
+		err = r.Close()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "0 records and")
+		held.Release()
+	})
+}
+
+func TestColumnReaderErrorChain(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 100)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs, WithZstd())
+
+	errBroken := xerrors.New("broken codec")
+	reg := NewDecompressorRegistry()
+	reg.Register(CompressionZstd, func(io.Reader) Decompressor { return brokenDecompressor{errBroken} })
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(memory.NewGoAllocator()), WithDecompressorRegistry(reg))
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, err = r.ColumnReader(0).Next()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not read column 0 of record 0")
+	assert.True(t, xerrors.Is(err, errBroken), "error chain lost: %v", err)
+}
+
+// brokenDecompressor is a decompressor failing with err.
+type brokenDecompressor struct{ err error }
+
+func (d brokenDecompressor) Read([]byte) (int, error) { return 0, d.err }
+func (brokenDecompressor) Reset(io.Reader)            {}
+func (brokenDecompressor) Close()                     {}
@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"golang.org/x/xerrors"
+)
+
+// defaultSpillThreshold is the size above which a decompressed file is
+// written to disk by NewFileReaderFromCompressed.
+const defaultSpillThreshold = 64 << 20
+
+// NewFileReaderFromCompressed opens an Arrow file which was compressed as a
+// whole, as is common when files are transported, with the given codec:
+// CompressionGzip, CompressionZstd or CompressionLZ4Frame. Decompressors
+// registered with RegisterDecompressor take precedence over the built-in
+// ones.
+//
+// As whole-file compression prevents random access, r is fully decompressed
+// before the file is opened. The decompressed file is kept in memory, unless
+// its size exceeds the threshold set with WithSpillThreshold: it is then
+// written to a temporary file, removed when the reader is closed.
+func NewFileReaderFromCompressed(r io.Reader, codec CompressionType, opts ...Option) (*FileReader, error) {
+	cfg := newConfig(opts...)
+
+	src, err := wholeFileDecompressor(r, codec)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	var buf bytes.Buffer
+	if cfg.spill >= 0 {
+		_, err := io.CopyN(&buf, src, cfg.spill+1)
+		if err == io.EOF {
+			return NewFileReaderFromBytes(buf.Bytes(), opts...)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("arrow/ipc: could not decompress %v file: %w", codec, err)
+		}
+	}
+
+	spill, err := ioutil.TempFile("", "go-arrow-spill-")
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not create spill file: %w", err)
+	}
+
+	_, err = io.Copy(spill, io.MultiReader(&buf, src))
+	if err != nil {
+		removeSpill(spill)
+		return nil, xerrors.Errorf("arrow/ipc: could not decompress %v file: %w", codec, err)
+	}
+
+	f, err := NewFileReader(spill, opts...)
+	if err != nil {
+		removeSpill(spill)
+		return nil, err
+	}
+	f.spill = spill
+	return f, nil
+}
+
+func removeSpill(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// wholeFileDecompressor returns a reader decompressing r with codec.
+func wholeFileDecompressor(r io.Reader, codec CompressionType) (io.ReadCloser, error) {
+	if factory, ok := decompressors.lookup(codec); ok {
+		dec := factory(nil)
+		dec.Reset(r)
+		return decompressorCloser{dec}, nil
+	}
+
+	switch codec {
+	case CompressionGzip:
+		dec, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, xerrors.Errorf("arrow/ipc: could not decompress %v file: %w", codec, err)
+		}
+		return dec, nil
+	case CompressionZstd:
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, xerrors.Errorf("arrow/ipc: could not decompress %v file: %w", codec, err)
+		}
+		return dec.IOReadCloser(), nil
+	case CompressionLZ4Frame:
+		return ioutil.NopCloser(&stickyEOFReader{r: lz4.NewReader(r)}), nil
+	}
+	return nil, xerrors.Errorf("arrow/ipc: unsupported whole-file compression %v", codec)
+}
+
+// stickyEOFReader keeps returning io.EOF once its reader did: the LZ4
+// reader fails when read again after the end of the frame.
+type stickyEOFReader struct {
+	r   io.Reader
+	eof bool
+}
+
+func (r *stickyEOFReader) Read(p []byte) (int, error) {
+	if r.eof {
+		return 0, io.EOF
+	}
+	n, err := r.r.Read(p)
+	r.eof = err == io.EOF
+	return n, err
+}
+
+type decompressorCloser struct {
+	Decompressor
+}
+
+func (d decompressorCloser) Close() error {
+	d.Decompressor.Close()
+	return nil
+}
@@ -0,0 +1,129 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compressWholeFile(t *testing.T, raw []byte, codec CompressionType) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	switch codec {
+	case CompressionGzip:
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(raw)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	case CompressionZstd:
+		w, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(raw)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	case CompressionLZ4Frame:
+		w := lz4.NewWriter(&buf)
+		_, err := w.Write(raw)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	default:
+		t.Fatalf("unexpected codec %v", codec)
+	}
+	return buf.Bytes()
+}
+
+func TestNewFileReaderFromCompressed(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, codec := range []CompressionType{CompressionGzip, CompressionZstd, CompressionLZ4Frame} {
+		for _, tc := range []struct {
+			name      string
+			threshold int64
+			spilled   bool
+		}{
+			{"memory", int64(len(raw)), false},
+			{"spill", int64(len(raw)) - 1, true},
+			{"always-spill", -1, true},
+		} {
+			t.Run(codec.String()+"/"+tc.name, func(t *testing.T) {
+				data := compressWholeFile(t, raw, codec)
+				r, err := NewFileReaderFromCompressed(bytes.NewReader(data), codec,
+					WithAllocator(mem), WithSpillThreshold(tc.threshold))
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.spilled, r.spill != nil)
+				var spill string
+				if r.spill != nil {
+					spill = r.spill.Name()
+					fi, err := os.Stat(spill)
+					require.NoError(t, err)
+					assert.Equal(t, int64(len(raw)), fi.Size())
+				}
+
+				require.Equal(t, len(recs), r.NumRecords())
+				for i := range recs {
+					rec, err := r.RecordAt(i)
+					require.NoError(t, err)
+					assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+					rec.Release()
+				}
+
+				require.NoError(t, r.Close())
+				if spill != "" {
+					_, err := os.Stat(spill)
+					assert.True(t, os.IsNotExist(err), "spill file should be removed")
+				}
+			})
+		}
+	}
+
+	t.Run("corrupt", func(t *testing.T) {
+		data := compressWholeFile(t, raw, CompressionGzip)
+		data = data[:len(data)/2]
+		_, err := NewFileReaderFromCompressed(bytes.NewReader(data), CompressionGzip, WithAllocator(mem))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not decompress GZIP file")
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		data := compressWholeFile(t, raw, CompressionZstd)
+		_, err := NewFileReaderFromCompressed(bytes.NewReader(data), CompressionGzip, WithAllocator(mem))
+		require.Error(t, err)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := NewFileReaderFromCompressed(bytes.NewReader(raw), CompressionType(42), WithAllocator(mem))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported whole-file compression")
+	})
+}
@@ -17,14 +17,44 @@
 package ipc
 
 import (
+	"fmt"
 	"io"
+	"math/bits"
+	"strings"
+	"sync"
 
 	"github.com/apache/arrow/go/v8/arrow/internal/debug"
 	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
 	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v4"
+	"golang.org/x/xerrors"
 )
 
+// CompressionType represents the codec used to compress the body buffers
+// of record batches.
+type CompressionType flatbuf.CompressionType
+
+const (
+	CompressionLZ4Frame = CompressionType(flatbuf.CompressionTypeLZ4_FRAME)
+	CompressionZstd     = CompressionType(flatbuf.CompressionTypeZSTD)
+
+	// CompressionGzip is only supported for whole-file compression, see
+	// NewFileReaderFromCompressed: it is not a codec of body buffers, and
+	// the writers reject it. Its value lies outside of the codecs of the
+	// Arrow format.
+	CompressionGzip = CompressionType(-2)
+)
+
+func (c CompressionType) String() string {
+	if c == CompressionGzip {
+		return "GZIP"
+	}
+	if v, ok := flatbuf.EnumNamesCompressionType[flatbuf.CompressionType(c)]; ok {
+		return v
+	}
+	return fmt.Sprintf("CompressionType(%d)", int8(c))
+}
+
 type compressor interface {
 	MaxCompressedLen(n int) int
 	Reset(io.Writer)
@@ -62,31 +92,123 @@ func (zstdCompressor) Type() flatbuf.CompressionType {
 	return flatbuf.CompressionTypeZSTD
 }
 
-func getCompressor(codec flatbuf.CompressionType) compressor {
+// checkCompression returns an error if the writers cannot compress the body
+// buffers of record batches with codec. A codec of -1 means no compression.
+func checkCompression(codec flatbuf.CompressionType) error {
+	switch codec {
+	case -1, flatbuf.CompressionTypeLZ4_FRAME, flatbuf.CompressionTypeZSTD:
+		return nil
+	case flatbuf.CompressionType(CompressionGzip):
+		return xerrors.Errorf("arrow/ipc: %v compression is only supported for whole files, not for body buffers", CompressionGzip)
+	}
+	return xerrors.Errorf("arrow/ipc: unsupported compression codec %v", CompressionType(codec))
+}
+
+func getCompressor(codec flatbuf.CompressionType, zstdDict []byte) (compressor, error) {
 	switch codec {
 	case flatbuf.CompressionTypeLZ4_FRAME:
 		w := lz4.NewWriter(nil)
 		// options here chosen in order to match the C++ implementation
 		w.Apply(lz4.ChecksumOption(false), lz4.BlockSizeOption(lz4.Block64Kb))
-		return &lz4Compressor{w}
+		return &lz4Compressor{w}, nil
 	case flatbuf.CompressionTypeZSTD:
-		enc, err := zstd.NewWriter(nil)
+		var opts []zstd.EOption
+		if zstdDict != nil {
+			opts = append(opts, zstd.WithEncoderDict(zstdDict))
+		}
+		enc, err := zstd.NewWriter(nil, opts...)
 		if err != nil {
-			panic(err)
+			return nil, xerrors.Errorf("arrow/ipc: could not create zstd compressor: %w", err)
 		}
-		return zstdCompressor{enc}
+		return zstdCompressor{enc}, nil
 	}
-	return nil
+	return nil, checkCompression(codec)
 }
 
-type decompressor interface {
+// Decompressor decompresses the data of a buffer of a record batch.
+type Decompressor interface {
 	io.Reader
-	Reset(io.Reader)
+	// Reset discards the state of the decompressor and prepares it to
+	// decompress the data read from r.
+	Reset(r io.Reader)
 	Close()
 }
 
+// bufferDecompressor is implemented by decompressors able to decode a whole
+// buffer at once, appending the decoded bytes to dst.
+type bufferDecompressor interface {
+	Decompressor
+	DecodeAll(src, dst []byte) ([]byte, error)
+}
+
+// DecompressorRegistry maps compression codecs to functions creating their
+// decompressor. It is safe for concurrent use.
+type DecompressorRegistry struct {
+	mu        sync.RWMutex
+	factories map[CompressionType]func(io.Reader) Decompressor
+}
+
+// NewDecompressorRegistry returns a new empty registry.
+func NewDecompressorRegistry() *DecompressorRegistry {
+	return &DecompressorRegistry{
+		factories: make(map[CompressionType]func(io.Reader) Decompressor),
+	}
+}
+
+// Register registers the function creating a decompressor for the given
+// codec, replacing any previously registered one. The function is called
+// with a nil reader; the data to decompress is then fed through Reset.
+func (reg *DecompressorRegistry) Register(codec CompressionType, factory func(io.Reader) Decompressor) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.factories[codec] = factory
+}
+
+// Unregister removes the function registered for the given codec, if any.
+func (reg *DecompressorRegistry) Unregister(codec CompressionType) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	delete(reg.factories, codec)
+}
+
+func (reg *DecompressorRegistry) lookup(codec CompressionType) (func(io.Reader) Decompressor, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	factory, ok := reg.factories[codec]
+	return factory, ok
+}
+
+// decompressors is the registry consulted by all readers.
+var decompressors = NewDecompressorRegistry()
+
+// RegisterDecompressor registers, for all readers, the function creating a
+// decompressor for the given codec. Registered decompressors take precedence
+// over the built-in LZ4 and ZSTD ones.
+func RegisterDecompressor(codec CompressionType, factory func(io.Reader) Decompressor) {
+	decompressors.Register(codec, factory)
+}
+
+// UnregisterDecompressor removes, for all readers, the decompressor
+// registered for the given codec by RegisterDecompressor.
+func UnregisterDecompressor(codec CompressionType) {
+	decompressors.Unregister(codec)
+}
+
 type zstdDecompressor struct {
 	*zstd.Decoder
+	hasDict bool // whether a dictionary was supplied to the decoder
+}
+
+// DecodeAll decodes src, appending the result to dst. It reports a clear
+// error for frames compressed with a dictionary when none was supplied.
+func (z *zstdDecompressor) DecodeAll(src, dst []byte) ([]byte, error) {
+	if !z.hasDict {
+		var hdr zstd.Header
+		if err := hdr.Decode(src); err == nil && hdr.DictionaryID != 0 {
+			return nil, xerrors.Errorf("arrow/ipc: zstd buffer was compressed with dictionary %d but no dictionary was supplied (see WithZstdDictionary)", hdr.DictionaryID)
+		}
+	}
+	return z.Decoder.DecodeAll(src, dst)
 }
 
 func (z *zstdDecompressor) Reset(r io.Reader) {
@@ -105,16 +227,101 @@ type lz4Decompressor struct {
 
 func (z *lz4Decompressor) Close() {}
 
-func getDecompressor(codec flatbuf.CompressionType) decompressor {
+// getDecompressor returns the decompressor for codec, looking it up in the
+// reader's registry, then in the global one, before the built-in ones.
+func getDecompressor(codec CompressionType, opts recordOptions) (Decompressor, error) {
+	for _, reg := range []*DecompressorRegistry{opts.registry, decompressors} {
+		if reg == nil {
+			continue
+		}
+		if factory, ok := reg.lookup(codec); ok {
+			return factory(nil), nil
+		}
+	}
+
 	switch codec {
-	case flatbuf.CompressionTypeLZ4_FRAME:
-		return &lz4Decompressor{lz4.NewReader(nil)}
-	case flatbuf.CompressionTypeZSTD:
-		dec, err := zstd.NewReader(nil)
+	case CompressionLZ4Frame:
+		return &lz4Decompressor{lz4.NewReader(nil)}, nil
+	case CompressionZstd:
+		var zopts []zstd.DOption
+		if opts.zstdDict != nil {
+			zopts = append(zopts, zstd.WithDecoderDicts(opts.zstdDict))
+		}
+		dec, err := zstd.NewReader(nil, zopts...)
 		if err != nil {
-			panic(err)
+			return nil, xerrors.Errorf("arrow/ipc: could not create zstd decoder: %w", err)
+		}
+		return &zstdDecompressor{Decoder: dec, hasDict: opts.zstdDict != nil}, nil
+	}
+	return nil, xerrors.Errorf("arrow/ipc: unsupported compression codec %v", codec)
+}
+
+// bodyDecompressor returns the decompressor for the body of the record
+// batch md, as declared by its body compression metadata, or nil if the body
+// is not compressed.
+func bodyDecompressor(md *flatbuf.RecordBatch, opts recordOptions) (Decompressor, error) {
+	c := md.Compression(nil)
+	if c == nil {
+		if opts.legacy {
+			return getDecompressor(opts.legacyCodec, opts)
 		}
-		return &zstdDecompressor{dec}
+		return nil, nil
+	}
+	return getDecompressor(CompressionType(c.Codec()), opts)
+}
+
+// legacyCompressionKey is the custom metadata key of a message declaring the
+// codec of its body, as written by Arrow 0.17 before body compression was
+// part of the format. Early Feather V2 files were compressed this way; the
+// buffers carry the same uncompressed length prefix as in the format.
+const legacyCompressionKey = "ARROW:experimental_compression"
+
+// legacyCompression returns the codec declared by the legacy compression
+// metadata of msg, reporting whether there is one.
+func legacyCompression(msg *flatbuf.Message) (CompressionType, bool, error) {
+	var kv flatbuf.KeyValue
+	for i := 0; i < msg.CustomMetadataLength(); i++ {
+		if !msg.CustomMetadata(&kv, i) || string(kv.Key()) != legacyCompressionKey {
+			continue
+		}
+		switch name := string(kv.Value()); strings.ToLower(name) {
+		case "lz4":
+			return CompressionLZ4Frame, true, nil
+		case "zstd":
+			return CompressionZstd, true, nil
+		default:
+			return 0, false, xerrors.Errorf("arrow/ipc: unsupported legacy compression codec %q", name)
+		}
+	}
+	return 0, false, nil
+}
+
+// scratchPools holds reusable scratch buffers for compressed data, bucketed
+// by power-of-two capacity.
+var scratchPools [64]sync.Pool
+
+func scratchClass(n int) int { return bits.Len(uint(n - 1)) }
+
+// getScratch returns a scratch buffer of length n from the pool.
+func getScratch(n int) *[]byte {
+	if n <= 0 {
+		return new([]byte)
+	}
+	class := scratchClass(n)
+	if v := scratchPools[class].Get(); v != nil {
+		b := v.(*[]byte)
+		*b = (*b)[:n]
+		return b
+	}
+	b := make([]byte, n, 1<<class)
+	return &b
+}
+
+// putScratch returns a scratch buffer obtained from getScratch to the pool.
+// The content of b must not be referenced after this call.
+func putScratch(b *[]byte) {
+	if cap(*b) == 0 {
+		return
 	}
-	return nil
+	scratchPools[scratchClass(cap(*b))].Put(b)
 }
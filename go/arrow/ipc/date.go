@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"golang.org/x/xerrors"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// DateColumnToTimes returns the values of the col-th column of rec, of type
+// date32 (days since the UNIX epoch) or date64 (milliseconds since the UNIX
+// epoch), as times in UTC, along with a mask telling which values are
+// present. Null values are left as the zero time, with their mask set to
+// false.
+func DateColumnToTimes(rec arrow.Record, col int) ([]time.Time, []bool, error) {
+	if col < 0 || col >= int(rec.NumCols()) {
+		return nil, nil, xerrors.Errorf("arrow/ipc: column index %d out of bounds [0, %d)", col, rec.NumCols())
+	}
+
+	var (
+		arr     = rec.Column(col)
+		times   = make([]time.Time, arr.Len())
+		present = make([]bool, arr.Len())
+	)
+	switch arr := arr.(type) {
+	case *array.Date32:
+		for i, v := range arr.Date32Values() {
+			if arr.IsValid(i) {
+				times[i] = time.Unix(int64(v)*secondsPerDay, 0).UTC()
+				present[i] = true
+			}
+		}
+	case *array.Date64:
+		for i, v := range arr.Date64Values() {
+			if arr.IsValid(i) {
+				ms := int64(v)
+				times[i] = time.Unix(ms/1e3, ms%1e3*int64(time.Millisecond)).UTC()
+				present[i] = true
+			}
+		}
+	default:
+		return nil, nil, xerrors.Errorf("arrow/ipc: column %q of type %v is not a date column", rec.ColumnName(col), arr.DataType())
+	}
+	return times, present, nil
+}
@@ -0,0 +1,103 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateColumnToTimes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "d32", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "d64", Type: arrow.FixedWidthTypes.Date64, Nullable: true},
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+
+	var (
+		day   = int64(24 * time.Hour / time.Millisecond)
+		valid = []bool{true, true, false, true, true}
+		want  = []time.Time{
+			time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC),
+			{},
+			time.Date(2022, 3, 14, 0, 0, 0, 0, time.UTC),
+			time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Date32Builder).AppendValues([]arrow.Date32{0, -1, 0, 19065, -25567}, valid)
+	b.Field(1).(*array.Date64Builder).AppendValues([]arrow.Date64{0, arrow.Date64(-day), 0, arrow.Date64(19065 * day), arrow.Date64(-25567 * day)}, valid)
+	b.Field(2).(*array.Int32Builder).AppendValues([]int32{1, 2, 3, 4, 5}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	raw := writeTestFile(t, []arrow.Record{rec})
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	slice := got.NewSlice(1, 4)
+	defer slice.Release()
+
+	for _, col := range []int{0, 1} {
+		times, present, err := DateColumnToTimes(got, col)
+		require.NoError(t, err)
+		assert.Equal(t, valid, present)
+		assert.Equal(t, want, times)
+		for i, ts := range times {
+			assert.Equalf(t, time.UTC, ts.Location(), "row %d", i)
+		}
+
+		times, present, err = DateColumnToTimes(slice, col)
+		require.NoError(t, err)
+		assert.Equal(t, valid[1:4], present)
+		assert.Equal(t, want[1:4], times)
+	}
+
+	// date64 values that are not whole days keep their time of day.
+	b.Field(0).AppendNull()
+	b.Field(1).(*array.Date64Builder).Append(arrow.Date64(-1))
+	b.Field(2).(*array.Int32Builder).Append(0)
+	ms := b.NewRecord()
+	defer ms.Release()
+	times, present, err := DateColumnToTimes(ms, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, present)
+	assert.Equal(t, time.Date(1969, 12, 31, 23, 59, 59, int(999*time.Millisecond), time.UTC), times[0])
+
+	for _, col := range []int{-1, 2, 3} {
+		_, _, err := DateColumnToTimes(got, col)
+		assert.Errorf(t, err, "column %d", col)
+	}
+}
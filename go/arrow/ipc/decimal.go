@@ -0,0 +1,108 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"math/big"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/decimal128"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+const maxDecimal128Precision = 38
+
+// rescaledSchema returns schema with the types of the top-level fields
+// listed in rescale replaced by their target decimal types.
+func rescaledSchema(schema *arrow.Schema, rescale map[string]*arrow.Decimal128Type) (*arrow.Schema, error) {
+	if len(rescale) == 0 {
+		return schema, nil
+	}
+
+	fields := make([]arrow.Field, len(schema.Fields()))
+	copy(fields, schema.Fields())
+	for name, dt := range rescale {
+		if dt.Precision < 1 || dt.Precision > maxDecimal128Precision || dt.Scale < 0 || dt.Scale > dt.Precision {
+			return nil, xerrors.Errorf("arrow/ipc: invalid decimal rescale of field %q (precision=%d, scale=%d)", name, dt.Precision, dt.Scale)
+		}
+		idx := schema.FieldIndices(name)
+		if len(idx) == 0 {
+			return nil, xerrors.Errorf("arrow/ipc: no field %q to rescale", name)
+		}
+		for _, i := range idx {
+			if _, ok := fields[i].Type.(*arrow.Decimal128Type); !ok {
+				return nil, xerrors.Errorf("arrow/ipc: field %q to rescale is not a decimal (type=%v)", name, fields[i].Type)
+			}
+			fields[i].Type = dt
+		}
+	}
+
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md), nil
+}
+
+// rescaleDecimal returns a copy of arr with its values converted to the
+// precision and scale of dt. Values that would overflow the precision of dt,
+// or lose digits to fit its scale, are reported as errors.
+func rescaleDecimal(mem memory.Allocator, arr *array.Decimal128, dt *arrow.Decimal128Type) (arrow.Array, error) {
+	src := arr.DataType().(*arrow.Decimal128Type)
+
+	var (
+		delta = int64(dt.Scale - src.Scale)
+		mul   = new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(delta)), nil)
+		max   = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(dt.Precision)), nil)
+		rem   = new(big.Int)
+	)
+
+	bldr := array.NewDecimal128Builder(mem, dt)
+	defer bldr.Release()
+	bldr.Reserve(arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			bldr.AppendNull()
+			continue
+		}
+		v := arr.Value(i).BigInt()
+		switch {
+		case delta > 0:
+			v.Mul(v, mul)
+		case delta < 0:
+			v.QuoRem(v, mul, rem)
+			if rem.Sign() != 0 {
+				return nil, xerrors.Errorf("value %s at index %d cannot be represented with scale %d without rounding",
+					formatDecimal(arr.Value(i).BigInt().String(), src.Scale), i, dt.Scale)
+			}
+		}
+		if new(big.Int).Abs(v).Cmp(max) >= 0 {
+			return nil, xerrors.Errorf("value %s at index %d overflows decimal precision %d",
+				formatDecimal(arr.Value(i).BigInt().String(), src.Scale), i, dt.Precision)
+		}
+		bldr.UnsafeAppend(decimal128.FromBigInt(v))
+	}
+
+	return bldr.NewArray(), nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
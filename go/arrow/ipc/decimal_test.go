@@ -0,0 +1,171 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/decimal128"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDecimalFile writes a file with a single record holding a "dec"
+// column of type dt, with the given unscaled values (nil means null), and
+// an "i32" column.
+func writeDecimalFile(t *testing.T, mem memory.Allocator, dt *arrow.Decimal128Type, vs []*int64) []byte {
+	t.Helper()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "dec", Type: dt, Nullable: true},
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+
+	for i, v := range vs {
+		if v == nil {
+			b.Field(0).AppendNull()
+		} else {
+			b.Field(0).(*array.Decimal128Builder).Append(decimal128.FromI64(*v))
+		}
+		b.Field(1).(*array.Int32Builder).Append(int32(i))
+	}
+
+	rec := b.NewRecord()
+	defer rec.Release()
+	return writeTestFile(t, []arrow.Record{rec})
+}
+
+func decimals(vs ...int64) []*int64 {
+	out := make([]*int64, len(vs))
+	for i := range vs {
+		out[i] = &vs[i]
+	}
+	return out
+}
+
+func TestDecimalRescale(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		src       arrow.Decimal128Type
+		vs        []*int64
+		precision int32
+		scale     int32
+		want      []*int64
+		err       string
+	}{
+		{
+			name:      "scale-up",
+			src:       arrow.Decimal128Type{Precision: 5, Scale: 2},
+			vs:        append(decimals(123, -45600), nil),
+			precision: 10, scale: 4,
+			want: append(decimals(12300, -4560000), nil),
+		},
+		{
+			name:      "scale-down",
+			src:       arrow.Decimal128Type{Precision: 6, Scale: 3},
+			vs:        decimals(1200, -4500, 0),
+			precision: 6, scale: 1,
+			want: decimals(12, -45, 0),
+		},
+		{
+			name:      "scale-down-rounding",
+			src:       arrow.Decimal128Type{Precision: 5, Scale: 2},
+			vs:        decimals(120, 125),
+			precision: 5, scale: 1,
+			err: `could not rescale field "dec": value 1.25 at index 1 cannot be represented with scale 1 without rounding`,
+		},
+		{
+			name:      "overflow",
+			src:       arrow.Decimal128Type{Precision: 5, Scale: 2},
+			vs:        decimals(1, -99999),
+			precision: 5, scale: 3,
+			err: `could not rescale field "dec": value -999.99 at index 1 overflows decimal precision 5`,
+		},
+		{
+			name:      "same-scale-lower-precision",
+			src:       arrow.Decimal128Type{Precision: 10, Scale: 2},
+			vs:        decimals(999, 1000),
+			precision: 3, scale: 2,
+			err: `value 10.00 at index 1 overflows decimal precision 3`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			src := tc.src
+			raw := writeDecimalFile(t, mem, &src, tc.vs)
+
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithDecimalRescale("dec", tc.precision, tc.scale))
+			require.NoError(t, err)
+			defer r.Close()
+
+			dt := &arrow.Decimal128Type{Precision: tc.precision, Scale: tc.scale}
+			assert.True(t, arrow.TypeEqual(dt, r.Schema().Field(0).Type))
+
+			rec, err := r.RecordAt(0)
+			if tc.err != "" {
+				assert.Nil(t, rec)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			defer rec.Release()
+
+			assert.True(t, rec.Schema().Equal(r.Schema()))
+			arr := rec.Column(0).(*array.Decimal128)
+			require.Equal(t, len(tc.want), arr.Len())
+			for i, v := range tc.want {
+				if v == nil {
+					assert.True(t, arr.IsNull(i), "index %d", i)
+					continue
+				}
+				assert.Equal(t, decimal128.FromI64(*v), arr.Value(i), "index %d", i)
+			}
+			assert.Equal(t, int64(len(tc.want)), rec.NumRows())
+		})
+	}
+}
+
+func TestDecimalRescaleInvalid(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	raw := writeDecimalFile(t, mem, &arrow.Decimal128Type{Precision: 5, Scale: 2}, decimals(1))
+
+	for _, tc := range []struct {
+		opt  Option
+		want string
+	}{
+		{WithDecimalRescale("missing", 5, 2), `no field "missing" to rescale`},
+		{WithDecimalRescale("i32", 5, 2), `field "i32" to rescale is not a decimal`},
+		{WithDecimalRescale("dec", 39, 2), `invalid decimal rescale of field "dec"`},
+		{WithDecimalRescale("dec", 5, 6), `invalid decimal rescale of field "dec"`},
+	} {
+		_, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), tc.opt)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
@@ -24,9 +24,15 @@ import (
 type dictMap map[int64]arrow.Array
 type dictTypeMap map[int64]arrow.Field
 
+// dictMemo maps dictionary IDs to dictionary arrays and back.
+// It is not safe for concurrent writes.
 type dictMemo struct {
 	dict2id map[arrow.Array]int64
 	id2dict dictMap // map of dictionary ID to dictionary array
+
+	// whether dictionary-encoded fields are decoded as placeholders, with the
+	// type of their values, rather than rejected.
+	placeholders bool
 }
 
 func newMemo() dictMemo {
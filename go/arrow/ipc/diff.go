@@ -0,0 +1,146 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v8/arrow"
+)
+
+// SchemaDiffKind is the kind of a difference between two schemas.
+type SchemaDiffKind int8
+
+const (
+	// FieldAdded reports a field only present in the second schema.
+	FieldAdded SchemaDiffKind = iota
+	// FieldRemoved reports a field only present in the first schema.
+	FieldRemoved
+	// FieldTypeChanged reports a field whose type differs. Struct fields
+	// are compared child by child instead.
+	FieldTypeChanged
+	// FieldNullabilityChanged reports a field whose nullability differs.
+	FieldNullabilityChanged
+	// MetadataChanged reports differing field metadata or, with an empty
+	// path, differing schema metadata.
+	MetadataChanged
+)
+
+func (k SchemaDiffKind) String() string {
+	switch k {
+	case FieldAdded:
+		return "added"
+	case FieldRemoved:
+		return "removed"
+	case FieldTypeChanged:
+		return "type changed"
+	case FieldNullabilityChanged:
+		return "nullability changed"
+	case MetadataChanged:
+		return "metadata changed"
+	}
+	return fmt.Sprintf("SchemaDiffKind(%d)", int8(k))
+}
+
+// SchemaDiff is a difference between two schemas.
+type SchemaDiff struct {
+	Kind SchemaDiffKind
+	// Path is the dot-separated path of the field, made of the names of
+	// its enclosing struct fields and its own name. It is empty for the
+	// schema metadata.
+	Path string
+	// Old and New are the field in the first and second schema. Old is
+	// the zero Field for added fields, New for removed ones. Both are zero
+	// for the schema metadata.
+	Old, New arrow.Field
+}
+
+func (d SchemaDiff) String() string {
+	switch {
+	case d.Path == "":
+		return "schema metadata changed"
+	case d.Kind == FieldTypeChanged:
+		return fmt.Sprintf("%s: type changed from %v to %v", d.Path, d.Old.Type, d.New.Type)
+	case d.Kind == FieldNullabilityChanged:
+		return fmt.Sprintf("%s: nullability changed from %v to %v", d.Path, d.Old.Nullable, d.New.Nullable)
+	}
+	return fmt.Sprintf("%s: %v", d.Path, d.Kind)
+}
+
+// DiffSchemas returns the differences between the schemas a and b, such as
+// the schemas of two files returned by FileReader.Schema.
+//
+// Fields are matched by name, at the top level and within struct fields,
+// whose children are compared recursively. Field order is not compared.
+// Differences are reported for the fields of a in their order, followed by
+// the fields only present in b, and the schema metadata last. Metadata is
+// compared regardless of the order of its keys.
+func DiffSchemas(a, b *arrow.Schema) []SchemaDiff {
+	var diffs []SchemaDiff
+	diffs = diffFields(diffs, "", a.Fields(), b.Fields())
+	if !a.Metadata().Equal(b.Metadata()) {
+		diffs = append(diffs, SchemaDiff{Kind: MetadataChanged})
+	}
+	return diffs
+}
+
+func diffFields(diffs []SchemaDiff, prefix string, a, b []arrow.Field) []SchemaDiff {
+	// fields sharing a name are represented by the first of them.
+	index := func(fields []arrow.Field) map[string]int {
+		m := make(map[string]int, len(fields))
+		for i := len(fields) - 1; i >= 0; i-- {
+			m[fields[i].Name] = i
+		}
+		return m
+	}
+	ia, ib := index(a), index(b)
+
+	for i, old := range a {
+		if ia[old.Name] != i {
+			continue
+		}
+		path := prefix + old.Name
+		j, ok := ib[old.Name]
+		if !ok {
+			diffs = append(diffs, SchemaDiff{Kind: FieldRemoved, Path: path, Old: old})
+			continue
+		}
+
+		cur := b[j]
+		st1, ok1 := old.Type.(*arrow.StructType)
+		st2, ok2 := cur.Type.(*arrow.StructType)
+		switch {
+		case ok1 && ok2:
+			diffs = diffFields(diffs, path+".", st1.Fields(), st2.Fields())
+		case !arrow.TypeEqual(old.Type, cur.Type):
+			diffs = append(diffs, SchemaDiff{Kind: FieldTypeChanged, Path: path, Old: old, New: cur})
+		}
+		if old.Nullable != cur.Nullable {
+			diffs = append(diffs, SchemaDiff{Kind: FieldNullabilityChanged, Path: path, Old: old, New: cur})
+		}
+		if !old.Metadata.Equal(cur.Metadata) {
+			diffs = append(diffs, SchemaDiff{Kind: MetadataChanged, Path: path, Old: old, New: cur})
+		}
+	}
+
+	for _, cur := range b {
+		if _, ok := ia[cur.Name]; !ok {
+			diffs = append(diffs, SchemaDiff{Kind: FieldAdded, Path: prefix + cur.Name, New: cur})
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	md := func(kvs ...string) arrow.Metadata {
+		var keys, values []string
+		for i := 0; i < len(kvs); i += 2 {
+			keys = append(keys, kvs[i])
+			values = append(values, kvs[i+1])
+		}
+		return arrow.NewMetadata(keys, values)
+	}
+	schema := func(meta arrow.Metadata, fields ...arrow.Field) *arrow.Schema {
+		return arrow.NewSchema(fields, &meta)
+	}
+
+	var (
+		i64    = arrow.Field{Name: "i64", Type: arrow.PrimitiveTypes.Int64}
+		str    = arrow.Field{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true}
+		nested = func(fields ...arrow.Field) arrow.Field {
+			return arrow.Field{Name: "s", Type: arrow.StructOf(fields...), Nullable: true}
+		}
+		with = func(f arrow.Field, fn func(*arrow.Field)) arrow.Field {
+			fn(&f)
+			return f
+		}
+		i32   = with(i64, func(f *arrow.Field) { f.Type = arrow.PrimitiveTypes.Int32 })
+		added = with(i32, func(f *arrow.Field) { f.Name = "new" })
+	)
+
+	base := schema(md("k1", "v1", "k2", "v2"), i64, str, nested(i64, nested(str)))
+
+	for _, tc := range []struct {
+		name  string
+		other *arrow.Schema
+		want  []SchemaDiff
+	}{
+		{
+			name:  "same",
+			other: schema(md("k2", "v2", "k1", "v1"), str, i64, nested(i64, nested(str))),
+		},
+		{
+			name:  "added",
+			other: schema(md("k1", "v1", "k2", "v2"), i64, str, nested(i64, nested(str)), added),
+			want:  []SchemaDiff{{Kind: FieldAdded, Path: "new", New: added}},
+		},
+		{
+			name:  "removed",
+			other: schema(md("k1", "v1", "k2", "v2"), i64, nested(i64, nested(str))),
+			want:  []SchemaDiff{{Kind: FieldRemoved, Path: "str", Old: str}},
+		},
+		{
+			name:  "retyped",
+			other: schema(md("k1", "v1", "k2", "v2"), i32, str, nested(i64, nested(str))),
+			want:  []SchemaDiff{{Kind: FieldTypeChanged, Path: "i64", Old: i64, New: i32}},
+		},
+		{
+			name:  "struct-to-list",
+			other: schema(md("k1", "v1", "k2", "v2"), i64, str, with(nested(), func(f *arrow.Field) { f.Type = arrow.ListOf(arrow.PrimitiveTypes.Int64) })),
+			want: []SchemaDiff{{
+				Kind: FieldTypeChanged, Path: "s",
+				Old: nested(i64, nested(str)),
+				New: with(nested(), func(f *arrow.Field) { f.Type = arrow.ListOf(arrow.PrimitiveTypes.Int64) }),
+			}},
+		},
+		{
+			name: "nullability",
+			other: schema(md("k1", "v1", "k2", "v2"),
+				with(i64, func(f *arrow.Field) { f.Nullable = true }), str, nested(i64, nested(str))),
+			want: []SchemaDiff{{
+				Kind: FieldNullabilityChanged, Path: "i64",
+				Old: i64, New: with(i64, func(f *arrow.Field) { f.Nullable = true }),
+			}},
+		},
+		{
+			name: "metadata",
+			other: schema(md("k1", "v1"),
+				i64, with(str, func(f *arrow.Field) { f.Metadata = md("k", "v") }), nested(i64, nested(str))),
+			want: []SchemaDiff{
+				{Kind: MetadataChanged, Path: "str", Old: str, New: with(str, func(f *arrow.Field) { f.Metadata = md("k", "v") })},
+				{Kind: MetadataChanged},
+			},
+		},
+		{
+			name:  "nested",
+			other: schema(md("k1", "v1", "k2", "v2"), i64, str, nested(i32, nested(), str)),
+			want: []SchemaDiff{
+				{Kind: FieldTypeChanged, Path: "s.i64", Old: i64, New: i32},
+				{Kind: FieldRemoved, Path: "s.s.str", Old: str},
+				{Kind: FieldAdded, Path: "s.str", New: str},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, DiffSchemas(base, tc.other))
+		})
+	}
+}
+
+func TestSchemaDiffString(t *testing.T) {
+	var (
+		i64 = arrow.Field{Name: "i64", Type: arrow.PrimitiveTypes.Int64}
+		i32 = arrow.Field{Name: "i64", Type: arrow.PrimitiveTypes.Int32, Nullable: true}
+	)
+	for _, tc := range []struct {
+		diff SchemaDiff
+		want string
+	}{
+		{SchemaDiff{Kind: FieldAdded, Path: "a.b", New: i64}, "a.b: added"},
+		{SchemaDiff{Kind: FieldRemoved, Path: "a", Old: i64}, "a: removed"},
+		{SchemaDiff{Kind: FieldTypeChanged, Path: "i64", Old: i64, New: i32}, "i64: type changed from int64 to int32"},
+		{SchemaDiff{Kind: FieldNullabilityChanged, Path: "i64", Old: i64, New: i32}, "i64: nullability changed from false to true"},
+		{SchemaDiff{Kind: MetadataChanged, Path: "i64", Old: i64, New: i64}, "i64: metadata changed"},
+		{SchemaDiff{Kind: MetadataChanged}, "schema metadata changed"},
+	} {
+		assert.Equal(t, tc.want, tc.diff.String())
+	}
+}
@@ -0,0 +1,69 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+// FooterDigestKey is the key of the footer custom metadata holding the
+// hex-encoded digest of the bytes of an Arrow file preceding its footer,
+// checked by readers configured with WithFooterDigest.
+const FooterDigestKey = "arrow/ipc:digest"
+
+// FileDigest streams all the bytes of r through h, without decoding them,
+// and returns the resulting digest. Applied to a whole Arrow file, it gives
+// a content hash suitable for integrity manifests.
+func FileDigest(r io.Reader, h hash.Hash) ([]byte, error) {
+	h.Reset()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not compute digest: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// verifyDigest checks the digest stored in the footer custom metadata
+// under FooterDigestKey against the digest, computed with h, of the bytes
+// of the file preceding the footer.
+func (f *FileReader) verifyDigest(h hash.Hash) error {
+	md, err := metadataFromFB(f.footer.data)
+	if err != nil {
+		return err
+	}
+	i := md.FindKey(FooterDigestKey)
+	if i < 0 {
+		return xerrors.Errorf("arrow/ipc: no digest in footer metadata")
+	}
+	want, err := hex.DecodeString(md.Values()[i])
+	if err != nil {
+		return xerrors.Errorf("arrow/ipc: invalid digest in footer metadata: %w", err)
+	}
+
+	got, err := FileDigest(io.NewSectionReader(f.r, 0, f.footer.start), h)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(got, want) {
+		return xerrors.Errorf("arrow/ipc: file digest mismatch (got=%x, want=%x)", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withFooterMetadata returns a copy of the file raw whose footer holds the
+// custom metadata md.
+func withFooterMetadata(t *testing.T, raw []byte, md arrow.Metadata) []byte {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	defer r.Close()
+
+	blks := make([]fileBlock, r.NumRecords())
+	for i := range blks {
+		blks[i], err = r.block(i)
+		require.NoError(t, err)
+	}
+
+	var (
+		b    = flatbuffers.NewBuilder(1024)
+		memo = newMemo()
+	)
+	schemaFB := schemaToFB(b, r.Schema(), &memo)
+	dictsFB := fileBlocksToFB(b, nil, flatbuf.FooterStartDictionariesVector)
+	recsFB := fileBlocksToFB(b, blks, flatbuf.FooterStartRecordBatchesVector)
+	metaFB := metadataToFB(b, md, flatbuf.FooterStartCustomMetadataVector)
+	flatbuf.FooterStart(b)
+	flatbuf.FooterAddVersion(b, flatbuf.MetadataVersion(r.Version()))
+	flatbuf.FooterAddSchema(b, schemaFB)
+	flatbuf.FooterAddDictionaries(b, dictsFB)
+	flatbuf.FooterAddRecordBatches(b, recsFB)
+	flatbuf.FooterAddCustomMetadata(b, metaFB)
+	b.Finish(flatbuf.FooterEnd(b))
+	footer := b.FinishedBytes()
+
+	size := int64(binary.LittleEndian.Uint32(raw[len(raw)-len(Magic)-4:]))
+	start := int64(len(raw)) - size - int64(len(Magic)+4)
+
+	out := append([]byte{}, raw[:start]...)
+	out = append(out, footer...)
+	out = append(out, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(out[len(out)-4:], uint32(len(footer)))
+	return append(out, Magic...)
+}
+
+func TestFileDigest(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 4)
+	defer releaseRecords(recs)
+
+	a := writeTestFile(t, recs)
+	b := writeTestFile(t, recs)
+	require.Equal(t, a, b)
+
+	h := sha256.New()
+	da, err := FileDigest(bytes.NewReader(a), h)
+	require.NoError(t, err)
+	db, err := FileDigest(bytes.NewReader(b), h)
+	require.NoError(t, err)
+	assert.Equal(t, da, db, "identical files should have the same digest")
+
+	want := sha256.Sum256(a)
+	assert.Equal(t, want[:], da)
+
+	for _, i := range []int{0, len(a) / 2, len(a) - 1} {
+		c := append([]byte{}, a...)
+		c[i] ^= 0x01
+		dc, err := FileDigest(bytes.NewReader(c), h)
+		require.NoError(t, err)
+		assert.NotEqualf(t, da, dc, "change of byte %d not detected", i)
+	}
+}
+
+func TestFooterDigest(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 4)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	// the digest covers the bytes preceding the footer, left untouched by
+	// withFooterMetadata.
+	size := int64(binary.LittleEndian.Uint32(raw[len(raw)-len(Magic)-4:]))
+	start := int64(len(raw)) - size - int64(len(Magic)+4)
+	sum := sha256.Sum256(raw[:start])
+
+	valid := withFooterMetadata(t, raw, arrow.NewMetadata(
+		[]string{"k", FooterDigestKey},
+		[]string{"v", hex.EncodeToString(sum[:])},
+	))
+
+	r, err := NewFileReader(bytes.NewReader(valid))
+	require.NoError(t, err)
+	blks := r.RecordBlocks()
+	r.Close()
+
+	corrupted := append([]byte{}, valid...)
+	corrupted[blks[1].Offset+int64(blks[1].MetaDataLength)] ^= 0x01
+
+	for _, tc := range []struct {
+		name string
+		raw  []byte
+		want string
+	}{
+		{name: "valid", raw: valid},
+		{name: "corrupted", raw: corrupted, want: "file digest mismatch"},
+		{name: "missing", raw: raw, want: "no digest in footer metadata"},
+		{
+			name: "invalid",
+			raw:  withFooterMetadata(t, raw, arrow.NewMetadata([]string{FooterDigestKey}, []string{"xyz"})),
+			want: "invalid digest in footer metadata",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewFileReader(bytes.NewReader(tc.raw), WithAllocator(mem), WithFooterDigest(sha256.New))
+			if tc.want != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.want)
+				return
+			}
+			require.NoError(t, err)
+			r.Close()
+		})
+	}
+
+	// the digest is only checked on request.
+	r, err = NewFileReader(bytes.NewReader(corrupted), WithAllocator(mem))
+	require.NoError(t, err)
+	r.Close()
+}
@@ -0,0 +1,148 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"github.com/apache/arrow/go/v8/arrow"
+	"golang.org/x/xerrors"
+)
+
+// resolveExtensions replaces the types of the fields of schema declaring an
+// extension type not registered with arrow.RegisterExtensionType by the
+// extension type returned by resolve. Such fields were read with their
+// storage type, and still carry the extension keys in their metadata.
+// Fields for which resolve returns a nil type are left as they are.
+func resolveExtensions(schema *arrow.Schema, resolve func(string, arrow.DataType, string) (arrow.ExtensionType, error)) (*arrow.Schema, error) {
+	if resolve == nil {
+		return schema, nil
+	}
+
+	fields, changed, err := resolveFields(schema.Fields(), resolve)
+	if err != nil || !changed {
+		return schema, err
+	}
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md), nil
+}
+
+// resolveFields resolves the extension types of fields, reporting whether
+// any of them changed. The input slice is never modified.
+func resolveFields(fields []arrow.Field, resolve func(string, arrow.DataType, string) (arrow.ExtensionType, error)) ([]arrow.Field, bool, error) {
+	var out []arrow.Field
+	for i, field := range fields {
+		f, changed, err := resolveField(field, resolve)
+		if err != nil {
+			return nil, false, err
+		}
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = append([]arrow.Field(nil), fields...)
+		}
+		out[i] = f
+	}
+	if out == nil {
+		return fields, false, nil
+	}
+	return out, true, nil
+}
+
+func resolveField(field arrow.Field, resolve func(string, arrow.DataType, string) (arrow.ExtensionType, error)) (arrow.Field, bool, error) {
+	var (
+		changed bool
+		err     error
+	)
+
+	switch dt := field.Type.(type) {
+	case *arrow.StructType:
+		var kids []arrow.Field
+		kids, changed, err = resolveFields(dt.Fields(), resolve)
+		if changed {
+			field.Type = arrow.StructOf(kids...)
+		}
+	case *arrow.ListType:
+		var elem arrow.Field
+		elem, changed, err = resolveField(dt.ElemField(), resolve)
+		if changed {
+			field.Type = arrow.ListOfField(elem)
+		}
+	case *arrow.FixedSizeListType:
+		var elem arrow.Field
+		elem, changed, err = resolveField(dt.ElemField(), resolve)
+		if changed {
+			field.Type = arrow.FixedSizeListOfField(dt.Len(), elem)
+		}
+	case *arrow.MapType:
+		// arrow.MapOf does not keep the metadata of the key and item fields:
+		// only the extension types nested in them are resolved.
+		var key, item arrow.Field
+		key, changed, err = resolveField(dt.KeyField(), resolve)
+		if err == nil {
+			var itemChanged bool
+			item, itemChanged, err = resolveField(dt.ItemField(), resolve)
+			changed = changed || itemChanged
+		}
+		if changed {
+			m := arrow.MapOf(key.Type, item.Type)
+			m.SetItemNullable(dt.ItemField().Nullable)
+			m.KeysSorted = dt.KeysSorted
+			field.Type = m
+		}
+	}
+	if err != nil {
+		return field, false, err
+	}
+
+	name := field.Metadata.FindKey(ExtensionTypeKeyName)
+	if name < 0 {
+		return field, changed, nil
+	}
+
+	var (
+		keys = field.Metadata.Keys()
+		vals = field.Metadata.Values()
+		data = field.Metadata.FindKey(ExtensionMetadataKeyName)
+		meta string
+	)
+	if data >= 0 {
+		meta = vals[data]
+	}
+
+	ext, err := resolve(vals[name], field.Type, meta)
+	switch {
+	case err != nil:
+		return field, false, xerrors.Errorf("arrow/ipc: could not resolve extension type %q of field %q: %w", vals[name], field.Name, err)
+	case ext == nil:
+		return field, changed, nil
+	case !arrow.TypeEqual(ext.StorageType(), field.Type):
+		return field, false, xerrors.Errorf("arrow/ipc: extension type %q of field %q has storage %v, want %v",
+			vals[name], field.Name, ext.StorageType(), field.Type)
+	}
+
+	newkeys := make([]string, 0, len(keys))
+	newvals := make([]string, 0, len(vals))
+	for i := range keys {
+		if i != name && i != data {
+			newkeys = append(newkeys, keys[i])
+			newvals = append(newvals, vals[i])
+		}
+	}
+	field.Type = ext
+	field.Metadata = arrow.NewMetadata(newkeys, newvals)
+	return field, true, nil
+}
@@ -0,0 +1,65 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+	"io"
+
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// recordBody returns a reader holding the body of the i-th record, described
+// by blk, at offset 0: a section of the file after the metadata of the
+// record, or the reader given by WithExternalBodyReader.
+func (f *FileReader) recordBody(ctx context.Context, i int, blk fileBlock) (io.ReaderAt, error) {
+	if f.extBody == nil {
+		return io.NewSectionReader(blk.r, blk.Offset+int64(blk.Meta), blk.Body), nil
+	}
+
+	r, size, err := f.extBody(i)
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not open external body of record %d: %w", i, err)
+	}
+	if size != blk.Body {
+		return nil, xerrors.Errorf("arrow/ipc: external body of record %d has length %d, block declares %d", i, size, blk.Body)
+	}
+	return io.NewSectionReader(&ctxReaderAt{ctx: ctx, r: r}, 0, size), nil
+}
+
+// externalMessage reads the message of the i-th record, described by blk,
+// with its metadata read from the file and its body from the reader given by
+// WithExternalBodyReader.
+func (f *FileReader) externalMessage(ctx context.Context, i int, blk fileBlock) (*Message, error) {
+	meta, err := blk.readMeta(io.NewSectionReader(blk.r, blk.Offset, int64(blk.Meta)))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := f.recordBody(ctx, i, blk)
+	if err != nil {
+		meta.Release()
+		return nil, err
+	}
+	buf := make([]byte, blk.Body)
+	if _, err := r.ReadAt(buf, 0); err != nil && !(err == io.EOF && blk.Body == 0) {
+		meta.Release()
+		return nil, xerrors.Errorf("arrow/ipc: could not read external body of record %d: %w", i, err)
+	}
+	return NewMessage(meta, memory.NewBufferBytes(buf)), nil
+}
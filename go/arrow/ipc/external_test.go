@@ -0,0 +1,173 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// splitBodies splits the Arrow file raw into a file holding the metadata of
+// its records, and the concatenation of their bodies, returning the block of
+// each body in the latter.
+func splitBodies(t *testing.T, raw []byte) (meta, bodies []byte, blks []fileBlock) {
+	t.Helper()
+
+	r, err := NewFileReaderFromBytes(raw)
+	require.NoError(t, err)
+	orig := make([]fileBlock, r.NumRecords())
+	for i := range orig {
+		orig[i], err = r.block(i)
+		require.NoError(t, err)
+	}
+	r.Close()
+
+	size := len(raw) - int(orig[len(orig)-1].Offset+int64(orig[len(orig)-1].Meta)+orig[len(orig)-1].Body)
+	meta = append(meta, raw[:orig[0].Offset]...)
+	moved := make([]fileBlock, len(orig))
+	for i, blk := range orig {
+		moved[i] = fileBlock{Offset: int64(len(meta)), Meta: blk.Meta, Body: blk.Body}
+		meta = append(meta, raw[blk.Offset:blk.Offset+int64(blk.Meta)]...)
+		blks = append(blks, fileBlock{Offset: int64(len(bodies)), Body: blk.Body})
+		bodies = append(bodies, raw[blk.Offset+int64(blk.Meta):blk.Offset+int64(blk.Meta)+blk.Body]...)
+	}
+	meta = append(meta, raw[len(raw)-size:]...)
+	meta = rewriteFooter(t, meta, MetadataV5, func([]fileBlock) []fileBlock { return moved })
+	return meta, bodies, blks
+}
+
+func TestFileReaderExternalBodyReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5, 7)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"plain", nil},
+		{"lz4", []Option{WithLZ4()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := writeTestFile(t, recs, tc.opts...)
+			meta, bodies, blks := splitBodies(t, raw)
+			require.Less(t, len(meta), len(raw))
+
+			external := func(i int) (io.ReaderAt, int64, error) {
+				return io.NewSectionReader(bytes.NewReader(bodies), blks[i].Offset, blks[i].Body), blks[i].Body, nil
+			}
+
+			orig, err := NewFileReaderFromBytes(raw, WithAllocator(mem))
+			require.NoError(t, err)
+			defer orig.Close()
+
+			r, err := NewFileReaderFromBytes(meta, WithAllocator(mem), WithExternalBodyReader(external))
+			require.NoError(t, err)
+			defer r.Close()
+
+			require.NoError(t, r.VerifyBlocks())
+			n, err := r.NumRows()
+			require.NoError(t, err)
+			assert.EqualValues(t, 15, n)
+			for i := range recs {
+				rec, err := r.RecordAt(i)
+				require.NoError(t, err)
+				assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+				rec.Release()
+
+				want, _, err := orig.RecordBodyBytes(i)
+				require.NoError(t, err)
+				got, _, err := r.RecordBodyBytes(i)
+				require.NoError(t, err)
+				assert.Equal(t, want, got, "body of record %d", i)
+
+				want, err = orig.RecordRawBytes(i)
+				require.NoError(t, err)
+				got, err = r.RecordRawBytes(i)
+				require.NoError(t, err)
+				assert.Equal(t, want, got, "message of record %d", i)
+			}
+
+			var col []arrow.Array
+			cr := r.ColumnReader(1)
+			for {
+				arr, err := cr.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				col = append(col, arr)
+			}
+			require.Len(t, col, len(recs))
+			for i, arr := range col {
+				assert.Truef(t, array.ArrayEqual(recs[i].Column(1), arr), "column of records[%d] differs", i)
+				arr.Release()
+			}
+		})
+	}
+
+	raw := writeTestFile(t, recs)
+	meta, bodies, blks := splitBodies(t, raw)
+
+	for _, tc := range []struct {
+		name     string
+		external func(i int) (io.ReaderAt, int64, error)
+		err      string
+	}{
+		{
+			name: "length mismatch",
+			external: func(i int) (io.ReaderAt, int64, error) {
+				return bytes.NewReader(bodies[blks[i].Offset:]), int64(len(bodies)) - blks[i].Offset, nil
+			},
+			err: "external body of record 1 has length",
+		},
+		{
+			name: "short reader",
+			external: func(i int) (io.ReaderAt, int64, error) {
+				return bytes.NewReader(bodies[blks[i].Offset : blks[i].Offset+blks[i].Body-8]), blks[i].Body, nil
+			},
+			err: "could not read external body of record 1",
+		},
+		{
+			name: "error",
+			external: func(i int) (io.ReaderAt, int64, error) {
+				return nil, 0, fmt.Errorf("no sidecar for %d", i)
+			},
+			err: "no sidecar for 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewFileReaderFromBytes(meta, WithAllocator(mem), WithExternalBodyReader(tc.external))
+			require.NoError(t, err)
+			defer r.Close()
+
+			_, err = r.RecordAt(1)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"strconv"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"golang.org/x/xerrors"
+)
+
+// DuplicateFieldPolicy tells readers how to handle top-level fields of a
+// schema sharing the same name.
+type DuplicateFieldPolicy int8
+
+const (
+	// DuplicateFieldAllow keeps duplicate field names as they are.
+	DuplicateFieldAllow DuplicateFieldPolicy = iota
+	// DuplicateFieldError rejects schemas with duplicate field names.
+	DuplicateFieldError
+	// DuplicateFieldSuffix renames the second and later fields sharing a
+	// name to name_1, name_2, and so on, skipping names already in use.
+	DuplicateFieldSuffix
+)
+
+// dedupSchema applies policy to the duplicate top-level field names of
+// schema.
+func dedupSchema(schema *arrow.Schema, policy DuplicateFieldPolicy) (*arrow.Schema, error) {
+	if policy == DuplicateFieldAllow {
+		return schema, nil
+	}
+
+	var (
+		fields = schema.Fields()
+		first  = make(map[string]int, len(fields)) // index of the first field with a given name
+		dups   []int
+	)
+	for i, field := range fields {
+		j, ok := first[field.Name]
+		if !ok {
+			first[field.Name] = i
+			continue
+		}
+		if policy == DuplicateFieldError {
+			return nil, xerrors.Errorf("arrow/ipc: duplicate field name %q (fields %d and %d)", field.Name, j, i)
+		}
+		dups = append(dups, i)
+	}
+	if len(dups) == 0 {
+		return schema, nil
+	}
+
+	fields = append([]arrow.Field(nil), fields...)
+	next := make(map[string]int) // last suffix tried for a given name
+	for _, i := range dups {
+		name := fields[i].Name
+		for {
+			next[name]++
+			renamed := name + "_" + strconv.Itoa(next[name])
+			if _, ok := first[renamed]; !ok {
+				first[renamed] = i
+				fields[i].Name = renamed
+				break
+			}
+		}
+	}
+
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md), nil
+}
+
+// FieldMetadataByPath returns the custom metadata of the field of the file
+// schema at the given path of child indices: path[0] is the index of a
+// top-level field, and each following index selects a child of the field
+// selected so far, such as a field of a struct or the element of a list.
+func (f *FileReader) FieldMetadataByPath(path []int) (arrow.Metadata, error) {
+	if len(path) == 0 {
+		return arrow.Metadata{}, xerrors.Errorf("arrow/ipc: empty field path")
+	}
+
+	fields := f.schema.Fields()
+	for depth, i := range path {
+		if i < 0 || i >= len(fields) {
+			return arrow.Metadata{}, xerrors.Errorf("arrow/ipc: field index %d at depth %d of path %v out of bounds [0, %d)", i, depth, path, len(fields))
+		}
+		field := fields[i]
+		if depth == len(path)-1 {
+			return field.Metadata, nil
+		}
+
+		nested, ok := field.Type.(arrow.NestedType)
+		if !ok {
+			return arrow.Metadata{}, xerrors.Errorf("arrow/ipc: field %q at depth %d of path %v has no children (type=%v)", field.Name, depth, path, field.Type)
+		}
+		fields = nested.Fields()
+	}
+	panic("unreachable")
+}
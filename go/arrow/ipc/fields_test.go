@@ -0,0 +1,199 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateFieldPolicy(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "x", Type: arrow.PrimitiveTypes.Int8},
+		{Name: "x", Type: arrow.PrimitiveTypes.Int16},
+		{Name: "x", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int8Builder).AppendValues([]int8{1, 2}, nil)
+	b.Field(1).(*array.Int16Builder).AppendValues([]int16{3, 4}, nil)
+	b.Field(2).(*array.Int32Builder).AppendValues([]int32{5, 6}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	file := writeTestFile(t, []arrow.Record{rec})
+
+	var stream bytes.Buffer
+	w := NewWriter(&stream, WithSchema(schema), WithAllocator(mem))
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+
+	// read returns the schema and the first record read with opts from
+	// either the file or the stream.
+	read := func(t *testing.T, fromStream bool, opts ...Option) (*arrow.Schema, arrow.Record, error) {
+		opts = append(opts, WithAllocator(mem))
+		if fromStream {
+			r, err := NewReader(bytes.NewReader(stream.Bytes()), opts...)
+			if err != nil {
+				return nil, nil, err
+			}
+			defer r.Release()
+			require.True(t, r.Next())
+			out := r.Record()
+			out.Retain()
+			return r.Schema(), out, nil
+		}
+
+		r, err := NewFileReader(bytes.NewReader(file), opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer r.Close()
+		out, err := r.RecordAt(0)
+		require.NoError(t, err)
+		return r.Schema(), out, nil
+	}
+
+	for _, tc := range []struct {
+		name   string
+		stream bool
+	}{
+		{"file", false},
+		{"stream", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("allow", func(t *testing.T) {
+				got, out, err := read(t, tc.stream, WithDuplicateFieldPolicy(DuplicateFieldAllow))
+				require.NoError(t, err)
+				defer out.Release()
+				assert.True(t, got.Equal(schema), "got=%v, want=%v", got, schema)
+				assert.Equal(t, []int{0, 1, 2}, got.FieldIndices("x"))
+			})
+
+			t.Run("error", func(t *testing.T) {
+				_, _, err := read(t, tc.stream, WithDuplicateFieldPolicy(DuplicateFieldError))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), `duplicate field name "x" (fields 0 and 1)`)
+			})
+
+			t.Run("suffix", func(t *testing.T) {
+				got, out, err := read(t, tc.stream,
+					WithDuplicateFieldPolicy(DuplicateFieldSuffix),
+					WithFieldCast("x_2", arrow.PrimitiveTypes.Int64),
+				)
+				require.NoError(t, err)
+				defer out.Release()
+
+				want := arrow.NewSchema([]arrow.Field{
+					{Name: "x", Type: arrow.PrimitiveTypes.Int8},
+					{Name: "x_1", Type: arrow.PrimitiveTypes.Int16},
+					{Name: "x_2", Type: arrow.PrimitiveTypes.Int64},
+				}, nil)
+				assert.True(t, got.Equal(want), "got=%v, want=%v", got, want)
+				assert.True(t, out.Schema().Equal(want), "got=%v, want=%v", out.Schema(), want)
+				assert.Equal(t, []int64{5, 6}, out.Column(2).(*array.Int64).Int64Values())
+				assert.Equal(t, "x", schema.Field(1).Name, "input schema should not be modified")
+			})
+		})
+	}
+}
+
+func TestDedupSchemaSuffixCollision(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "x", Type: arrow.PrimitiveTypes.Int8},
+		{Name: "x", Type: arrow.PrimitiveTypes.Int16},
+		{Name: "x_1", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "x_1", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	got, err := dedupSchema(schema, DuplicateFieldSuffix)
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range got.Fields() {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"x", "x_2", "x_1", "x_1_1"}, names)
+}
+
+func TestFileReaderFieldMetadataByPath(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	var (
+		leafMD  = arrow.NewMetadata([]string{"unit", "desc"}, []string{"m/s", "wind speed"})
+		innerMD = arrow.NewMetadata([]string{"group"}, []string{"inner"})
+		elemMD  = arrow.NewMetadata([]string{"elem"}, []string{"yes"})
+		topMD   = arrow.NewMetadata([]string{"top"}, []string{"1"})
+
+		inner = arrow.StructOf(
+			arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+			arrow.Field{Name: "speed", Type: arrow.PrimitiveTypes.Float64, Nullable: true, Metadata: leafMD},
+		)
+		outer = arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "inner", Type: inner, Nullable: true, Metadata: innerMD},
+		)
+		list = arrow.ListOfField(arrow.Field{Name: "item", Type: arrow.PrimitiveTypes.Int64, Nullable: true, Metadata: elemMD})
+	)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "outer", Type: outer, Nullable: true, Metadata: topMD},
+		{Name: "list", Type: list, Nullable: true},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	r, err := NewFileReader(bytes.NewReader(writeTestFile(t, []arrow.Record{rec})), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	for _, tc := range []struct {
+		path []int
+		want arrow.Metadata
+	}{
+		{[]int{0}, arrow.Metadata{}},
+		{[]int{1}, topMD},
+		{[]int{1, 0}, arrow.Metadata{}},
+		{[]int{1, 1}, innerMD},
+		{[]int{1, 1, 0}, arrow.Metadata{}},
+		{[]int{1, 1, 1}, leafMD},
+		{[]int{2, 0}, elemMD},
+	} {
+		md, err := r.FieldMetadataByPath(tc.path)
+		require.NoErrorf(t, err, "path %v", tc.path)
+		assert.Truef(t, tc.want.Equal(md), "path %v: got=%v, want=%v", tc.path, md, tc.want)
+	}
+
+	for _, path := range [][]int{nil, {3}, {-1}, {1, 2}, {1, 1, 1, 0}, {0, 0}} {
+		_, err := r.FieldMetadataByPath(path)
+		assert.Errorf(t, err, "path %v", path)
+	}
+}
@@ -18,14 +18,22 @@ package ipc
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"fmt"
 	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/apache/arrow/go/v8/arrow"
 	"github.com/apache/arrow/go/v8/arrow/array"
 	"github.com/apache/arrow/go/v8/arrow/bitutil"
 	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
 	"github.com/apache/arrow/go/v8/arrow/memory"
+	flatbuffers "github.com/google/flatbuffers/go"
 	"golang.org/x/xerrors"
 )
 
@@ -35,36 +43,158 @@ type FileReader struct {
 
 	footer struct {
 		offset int64
+		start  int64 // position of the footer data, where the messages end
 		buffer *memory.Buffer
 		data   *flatbuf.Footer
+		lazy   *lazyFooter // reads the block entries on demand, if not nil
 	}
 
 	fields dictTypeMap
-	memo   dictMemo
+	// memo is meant to hold the dictionaries of the file. It stays empty
+	// until readDictionary is implemented, as readSchema rejects files with
+	// dictionary-encoded fields.
+	memo dictMemo
 
-	schema *arrow.Schema
-	record arrow.Record
+	schema    *arrow.Schema // schema declared by the file
+	recSchema *arrow.Schema // schema of the records, after read-time conversions
+	record    arrow.Record
 
 	irec int   // current record index. used for the arrio.Reader interface
 	err  error // last error
 
 	mem memory.Allocator
+
+	endianness flatbuf.Endianness // endianness declared by the file schema
+	byteSwap   bool               // whether to byte-swap buffers of a non-native file
+	host       flatbuf.Endianness // endianness of the host
+
+	opts recordOptions // settings used to decode record batches
+
+	feather bool // whether Feather V2 quirks are accepted
+	relaxed bool // whether message blocks aligned on 4-byte boundaries are accepted
+	trusted bool // whether the alignment of message blocks is left unchecked
+	lazy    bool // whether records load their columns on first access
+
+	decrypt func(int, []byte) ([]byte, error) // decrypts record batch bodies, if not nil
+
+	order []int // footer index of each record batch, when sorted by offset
+
+	ahead readAhead // records read ahead of Read
+
+	dups DuplicateFieldPolicy // handling of duplicate field names
+
+	// resolves the unregistered extension types of the schema, if not nil
+	resolveExt func(string, arrow.DataType, string) (arrow.ExtensionType, error)
+
+	// transform the metadata of the schema and of its fields, if not nil
+	schemaMeta func(arrow.Metadata) arrow.Metadata
+	fieldMeta  func([]int, arrow.Field) arrow.Metadata
+
+	extBody func(int) (io.ReaderAt, int64, error) // reads the bodies of records, if not nil
+
+	retained *retainedAllocator // tracks the memory of unreleased records, if not nil
+	leaks    *leakTracker       // tracks the unreleased records, if not nil
+
+	filter func(map[string]ColumnStats) bool // selects the records returned by Read, if not nil
+
+	coalesce *coalescer // serves the reads of record blocks, if not nil
+
+	lazyFooter bool // whether the block entries of the footer are read on demand
+
+	postHook func(int, arrow.Record, int64, error) // called after each record is read, if not nil
+	progress func(int, int, int64)                 // called after each record of a bulk read, if not nil
+
+	spill *os.File // temporary file holding the decompressed file, if not nil
+
+	allocReset func() // called by Close once the buffers are released, if not nil
+
+	ring *recordRing // allocates the records returned by RecordAt, if not nil
+
+	stats struct {
+		records   int64 // records decoded, accessed atomically
+		bytesRead int64 // bytes read for their messages, accessed atomically
+	}
+
+	rows struct {
+		mu  sync.Mutex
+		loc *RowLocator // computed on first use
+	}
 }
 
 // NewFileReader opens an Arrow file using the provided reader r.
+//
+// Files written by Arrow releases prior to 0.15, or with the legacy IPC
+// format, frame their messages with a 4-byte length prefix without the
+// continuation marker: the framing of each message is detected as it is
+// read, and such files need no option. Their footer is unchanged.
 func NewFileReader(r ReadAtSeeker, opts ...Option) (*FileReader, error) {
 	var (
 		cfg = newConfig(opts...)
 		err error
 
 		f = FileReader{
-			r:      r,
-			fields: make(dictTypeMap),
-			memo:   newMemo(),
-			mem:    cfg.alloc,
+			r:        r,
+			fields:   make(dictTypeMap),
+			memo:     newMemo(),
+			mem:      cfg.alloc,
+			byteSwap: cfg.byteSwap,
+			host:     cfg.host,
+			opts:     newRecordOptions(cfg),
+			feather:  cfg.feather,
+			relaxed:  cfg.relaxed,
+			trusted:  cfg.trusted,
+			lazy:     cfg.lazy && cfg.target == nil,
+			decrypt:  cfg.decrypt,
+			ahead:    newReadAhead(cfg.readAhead),
+			dups:     cfg.dups,
+			filter:   cfg.filter,
+
+			resolveExt: cfg.resolveExt,
+			schemaMeta: cfg.schemaMeta,
+			fieldMeta:  cfg.fieldMeta,
+			extBody:    cfg.extBody,
+			lazyFooter: cfg.lazyFooter,
+			postHook:   cfg.postHook,
+			progress:   cfg.progress,
+			allocReset: cfg.allocReset,
 		}
 	)
 
+	if cfg.ring > 0 {
+		if cfg.readAhead > 0 {
+			return nil, xerrors.Errorf("arrow/ipc: WithRecordRing cannot be combined with WithReadAhead")
+		}
+		f.ring = newRecordRing(cfg.ring)
+	}
+
+	if cfg.maxRetained > 0 {
+		f.retained = newRetainedAllocator(f.mem, cfg.maxRetained)
+		f.mem = f.retained
+	}
+
+	if cfg.leakCheck {
+		f.leaks = newLeakTracker(f.mem)
+		f.mem = f.leaks.mem
+	}
+
+	if cfg.maxIO > 0 {
+		f.r = newIOLimiter(f.r, cfg.maxIO)
+	}
+
+	if cfg.embedded.end != 0 {
+		start, end := cfg.embedded.start, cfg.embedded.end
+		if start < 0 || end <= start {
+			return nil, xerrors.Errorf("arrow/ipc: invalid embedded file bounds [%d, %d)", start, end)
+		}
+		f.r = io.NewSectionReader(f.r, start, end-start)
+		cfg.footer.offset = end - start
+
+		err = f.readMagic()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if cfg.footer.offset <= 0 {
 		cfg.footer.offset, err = f.r.Seek(0, io.SeekEnd)
 		if err != nil {
@@ -78,6 +208,21 @@ func NewFileReader(r ReadAtSeeker, opts ...Option) (*FileReader, error) {
 		return nil, xerrors.Errorf("arrow/ipc: could not decode footer: %w", err)
 	}
 
+	if v := f.Version(); !supportedVersion(v, cfg.versions) {
+		return nil, xerrors.Errorf("arrow/ipc: unsupported Arrow metadata version %v", v)
+	}
+
+	if cfg.digest != nil {
+		err = f.verifyDigest(cfg.digest())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.sortBlocks {
+		f.sortBlocks()
+	}
+
 	err = f.readSchema()
 	if err != nil {
 		return nil, xerrors.Errorf("arrow/ipc: could not decode schema: %w", err)
@@ -87,9 +232,174 @@ func NewFileReader(r ReadAtSeeker, opts ...Option) (*FileReader, error) {
 		return nil, xerrors.Errorf("arrow/ipc: inconsistent schema for reading (got: %v, want: %v)", f.schema, cfg.schema)
 	}
 
+	if cfg.coalesce >= 0 {
+		blocks := make([]fileBlock, f.NumRecords())
+		for i := range blocks {
+			blocks[i], err = f.block(i)
+			if err != nil {
+				return nil, err
+			}
+		}
+		f.coalesce = newCoalescer(f.r, cfg.coalesce, blocks)
+	}
+
 	return &f, err
 }
 
+// NewFileReaderSize opens an Arrow file of the given size using the provided
+// reader r. Unlike NewFileReader, it does not need r to implement io.Seeker,
+// which makes it suitable for sources only supporting ranged reads.
+func NewFileReaderSize(r io.ReaderAt, size int64, opts ...Option) (*FileReader, error) {
+	if size <= 0 {
+		return nil, xerrors.Errorf("arrow/ipc: invalid file size %d", size)
+	}
+	opts = append([]Option{WithFooterOffset(size)}, opts...)
+	return NewFileReader(&sizedReaderAt{SectionReader: io.NewSectionReader(r, 0, size), r: r}, opts...)
+}
+
+// NewFileReaderFromBytes opens the Arrow file held in data. The footer offset
+// is taken from the length of data, unless set with WithFooterOffset.
+func NewFileReaderFromBytes(data []byte, opts ...Option) (*FileReader, error) {
+	opts = append([]Option{WithFooterOffset(int64(len(data)))}, opts...)
+	return NewFileReader(bytes.NewReader(data), opts...)
+}
+
+// sizedReaderAt adapts an io.ReaderAt of known size to a ReadAtSeeker,
+// without ever seeking the underlying reader. Reads are canceled with
+// their context if the underlying reader implements ReaderAtContext.
+type sizedReaderAt struct {
+	*io.SectionReader
+	r io.ReaderAt
+}
+
+func (r *sizedReaderAt) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	if rc, ok := r.r.(ReaderAtContext); ok {
+		if off >= r.Size() {
+			return 0, io.EOF
+		}
+		if max := r.Size() - off; int64(len(p)) > max {
+			p = p[:max]
+		}
+		return rc.ReadAtContext(ctx, p, off)
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.ReadAt(p, off)
+}
+
+// supportedVersion returns whether the metadata version v can be read,
+// either by default or because it was explicitly allowed.
+func supportedVersion(v MetadataVersion, allowed []MetadataVersion) bool {
+	if v >= minMetadataVersion && v <= currentMetadataVersion {
+		return true
+	}
+	for _, a := range allowed {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
+
+// readMagic checks the Arrow magic bytes at the start of the file.
+func (f *FileReader) readMagic() error {
+	buf := make([]byte, len(Magic))
+	_, err := f.r.ReadAt(buf, 0)
+	if err != nil {
+		return xerrors.Errorf("arrow/ipc: could not read magic bytes: %w", err)
+	}
+	if !bytes.Equal(buf, Magic) {
+		return errNotArrowFile
+	}
+	return nil
+}
+
+// SchemaBytes returns the encapsulated schema message stored at the start of
+// the file, as is: its length prefix, including the continuation marker when
+// present, followed by the flatbuffer Message and its padding.
+// The bytes can be forwarded as the first message of an Arrow stream.
+func (f *FileReader) SchemaBytes() ([]byte, error) {
+	var (
+		pos    = paddedLength(int64(len(Magic)), kArrowIPCAlignment)
+		prefix [8]byte
+		n      int64
+		hdr    = int64(4)
+	)
+	if _, err := f.r.ReadAt(prefix[:], pos); err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not read schema message length: %w", err)
+	}
+	switch v := binary.LittleEndian.Uint32(prefix[:]); v {
+	case kIPCContToken:
+		n, hdr = int64(int32(binary.LittleEndian.Uint32(prefix[4:]))), 8
+	default:
+		// legacy, pre-0.15, prefix without continuation marker.
+		n = int64(int32(v))
+	}
+	if n <= 0 || pos+hdr+n > f.footer.offset {
+		return nil, xerrors.Errorf("arrow/ipc: invalid schema message length %d", n)
+	}
+
+	buf := make([]byte, hdr+n)
+	if _, err := f.r.ReadAt(buf, pos); err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not read schema message: %w", err)
+	}
+	if typ := MessageType(flatbuf.GetRootAsMessage(buf[hdr:], 0).HeaderType()); typ != MessageSchema {
+		return nil, xerrors.Errorf("arrow/ipc: invalid message type at start of file (got=%v, want=%v)", typ, MessageSchema)
+	}
+	return buf, nil
+}
+
+// RecordRawBytes returns the encapsulated message of the i-th record batch,
+// as stored in the file: its length prefix, including the continuation
+// marker when present, its flatbuffer Message and padding, then its body.
+// Nothing is decoded, decompressed nor decrypted. The bytes can be
+// forwarded as a record batch message of an Arrow stream started with the
+// bytes returned by SchemaBytes.
+func (f *FileReader) RecordRawBytes(i int) (raw []byte, err error) {
+	if i < 0 || i >= f.NumRecords() {
+		return nil, xerrors.Errorf("arrow/ipc: record index %d out of bounds [0, %d)", i, f.NumRecords())
+	}
+
+	blk, err := f.block(i)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkBlock(blk, "record", i); err != nil {
+		return nil, err
+	}
+	if blk.Meta < 8 || blk.Body < 0 {
+		return nil, xerrors.Errorf("arrow/ipc: invalid block of record %d (metadata=%d, body=%d)", i, blk.Meta, blk.Body)
+	}
+
+	buf := make([]byte, int64(blk.Meta)+blk.Body)
+	if _, err := f.r.ReadAt(buf[:blk.Meta], blk.Offset); err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+	}
+	body, err := f.recordBody(context.Background(), i, blk)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := body.ReadAt(buf[blk.Meta:], 0); err != nil && !(err == io.EOF && blk.Body == 0) {
+		return nil, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+	}
+
+	prefix, size, err := messageFraming(buf[:blk.Meta])
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			raw, err = nil, xerrors.Errorf("arrow/ipc: invalid metadata of record %d: %v", i, e)
+		}
+	}()
+	if typ := MessageType(flatbuf.GetRootAsMessage(buf[prefix:prefix+size], 0).HeaderType()); typ != MessageRecordBatch {
+		return nil, xerrors.Errorf("arrow/ipc: message %d is not a Record (got=%v)", i, typ)
+	}
+	return buf, nil
+}
+
 func (f *FileReader) readFooter() error {
 	var err error
 
@@ -116,6 +426,20 @@ func (f *FileReader) readFooter() error {
 		return errInconsistentFileMetadata
 	}
 
+	f.footer.start = f.footer.offset - size - eof
+	if f.lazyFooter {
+		buf, f.footer.lazy, err = readLazyFooter(f.r, f.footer.offset-size-eof, size)
+		if err != nil {
+			return err
+		}
+		if err := checkFooter(buf); err != nil {
+			return err
+		}
+		f.footer.buffer = memory.NewBufferBytes(buf)
+		f.footer.data = flatbuf.GetRootAsFooter(buf, 0)
+		return nil
+	}
+
 	buf = make([]byte, size)
 	n, err = f.r.ReadAt(buf, f.footer.offset-size-eof)
 	if err != nil {
@@ -125,31 +449,42 @@ func (f *FileReader) readFooter() error {
 		return xerrors.Errorf("arrow/ipc: could not read %d bytes from footer data", len(buf))
 	}
 
+	if err := checkFooter(buf); err != nil {
+		return err
+	}
+
 	f.footer.buffer = memory.NewBufferBytes(buf)
 	f.footer.data = flatbuf.GetRootAsFooter(buf, 0)
 	return err
 }
 
-func (f *FileReader) readSchema() error {
-	var err error
+func (f *FileReader) readSchema() (err error) {
+	// the schema is decoded from the footer as is: crafted footers may hold
+	// offsets outside of it, for which flatbuffers panics.
+	defer func() {
+		if e := recover(); e != nil {
+			err = xerrors.Errorf("arrow/ipc: invalid schema in footer: %v", e)
+		}
+	}()
+
 	f.fields, err = dictTypesFromFB(f.footer.data.Schema(nil))
 	if err != nil {
 		return xerrors.Errorf("arrow/ipc: could not load dictionary types from file: %w", err)
 	}
 
+	if len(f.fields) != 0 {
+		// FIXME: readDictionary is not implemented yet.
+		return xerrors.Errorf("arrow/ipc: dictionary-encoded fields are not supported (%d dictionaries in schema)", len(f.fields))
+	}
+
 	//lint:ignore SA4008 readDictionary always panics currently. ignore lint until DictionaryArray is implemented.
 	for i := 0; i < f.NumDictionaries(); i++ {
 		blk, err := f.dict(i)
 		if err != nil {
 			return xerrors.Errorf("arrow/ipc: could read dictionary[%d]: %w", i, err)
 		}
-		switch {
-		case !bitutil.IsMultipleOf8(blk.Offset):
-			return xerrors.Errorf("arrow/ipc: invalid file offset=%d for dictionary %d", blk.Offset, i)
-		case !bitutil.IsMultipleOf8(int64(blk.Meta)):
-			return xerrors.Errorf("arrow/ipc: invalid file metadata=%d position for dictionary %d", blk.Meta, i)
-		case !bitutil.IsMultipleOf8(blk.Body):
-			return xerrors.Errorf("arrow/ipc: invalid file body=%d position for dictionary %d", blk.Body, i)
+		if err := f.checkBlock(blk, "dictionary", i); err != nil {
+			return err
 		}
 
 		msg, err := blk.NewMessage()
@@ -170,17 +505,108 @@ func (f *FileReader) readSchema() error {
 	if schema == nil {
 		return xerrors.Errorf("arrow/ipc: could not load schema from flatbuffer data")
 	}
+
+	f.endianness = schema.Endianness()
+	f.opts.swap, err = checkEndianness(f.endianness, f.host, f.byteSwap)
+	if err != nil {
+		return err
+	}
+
 	f.schema, err = schemaFromFB(schema, &f.memo)
 	if err != nil {
 		return xerrors.Errorf("arrow/ipc: could not read schema: %w", err)
 	}
 
+	f.schema, err = resolveExtensions(f.schema, f.resolveExt)
+	if err != nil {
+		return err
+	}
+
+	f.schema, err = dedupSchema(f.schema, f.dups)
+	if err != nil {
+		return err
+	}
+
+	f.schema = transformMetadata(f.schema, f.schemaMeta, f.fieldMeta)
+
+	f.recSchema, err = recordSchema(f.schema, f.opts)
+	if err != nil {
+		return err
+	}
+
 	return err
 }
 
-func (f *FileReader) block(i int) (fileBlock, error) {
+// ReadSchemaOnly reads the schema of the Arrow file r from its footer,
+// without reading any dictionary or record batch.
+//
+// Dictionary-encoded fields are returned as placeholders, whose dictionary
+// is left unread: they have the type of the dictionary values, and their
+// metadata holds the ID of the dictionary, the type of its indices and
+// whether it is ordered, under the DictionaryIDKeyName,
+// DictionaryIndexTypeKeyName and DictionaryOrderedKeyName keys.
+func ReadSchemaOnly(r ReadAtSeeker) (*arrow.Schema, error) {
+	offset, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could retrieve footer offset: %w", err)
+	}
+
+	f := FileReader{r: r, memo: newMemo()}
+	f.memo.placeholders = true
+	f.footer.offset = offset
+	defer f.Close()
+
+	err = f.readFooter()
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not decode footer: %w", err)
+	}
+
+	schema := f.footer.data.Schema(nil)
+	if schema == nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not load schema from flatbuffer data")
+	}
+	return schemaFromFB(schema, &f.memo)
+}
+
+// sortBlocks orders the record batches by their offset in the file.
+func (f *FileReader) sortBlocks() {
+	var (
+		n       = f.NumRecords()
+		offsets = make([]int64, n)
+	)
+	f.order = make([]int, n)
+	for i := range f.order {
+		f.order[i] = i
+		if blk, ok := f.footerBlock(footerRecsSlot, i); ok {
+			offsets[i] = blk.Offset()
+		}
+	}
+	sort.SliceStable(f.order, func(i, j int) bool {
+		return offsets[f.order[i]] < offsets[f.order[j]]
+	})
+}
+
+// footerBlock returns the i-th entry of the block vector of the footer at
+// the given vtable slot.
+func (f *FileReader) footerBlock(slot flatbuffers.VOffsetT, i int) (flatbuf.Block, bool) {
 	var blk flatbuf.Block
-	if !f.footer.data.RecordBatches(&blk, i) {
+	switch {
+	case f.footer.lazy != nil:
+		blk, err := f.footer.lazy.block(slot, i)
+		return blk, err == nil
+	case slot == footerDictsSlot:
+		return blk, f.footer.data.Dictionaries(&blk, i)
+	default:
+		return blk, f.footer.data.RecordBatches(&blk, i)
+	}
+}
+
+func (f *FileReader) block(i int) (fileBlock, error) {
+	if f.order != nil && i >= 0 && i < len(f.order) {
+		i = f.order[i]
+	}
+	blk, ok := f.footerBlock(footerRecsSlot, i)
+	if !ok {
 		return fileBlock{}, xerrors.Errorf("arrow/ipc: could not extract file block %d", i)
 	}
 
@@ -193,8 +619,8 @@ func (f *FileReader) block(i int) (fileBlock, error) {
 }
 
 func (f *FileReader) dict(i int) (fileBlock, error) {
-	var blk flatbuf.Block
-	if !f.footer.data.Dictionaries(&blk, i) {
+	blk, ok := f.footerBlock(footerDictsSlot, i)
+	if !ok {
 		return fileBlock{}, xerrors.Errorf("arrow/ipc: could not extract dictionary block %d", i)
 	}
 
@@ -206,11 +632,53 @@ func (f *FileReader) dict(i int) (fileBlock, error) {
 	}, nil
 }
 
+// decryptBody decrypts the body of the i-th record.
+func (f *FileReader) decryptBody(i int, body []byte) ([]byte, error) {
+	plain, err := f.decrypt(i, body)
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not decrypt body of record %d: %w", i, err)
+	}
+	return plain, nil
+}
+
+// checkBlock verifies that the block of the i-th message of the given kind
+// is aligned on 8-byte boundaries, or 4-byte boundaries with relaxed
+// alignment, unless the reader was configured for trusted input. With
+// Feather compatibility, the offset of the block only needs to be aligned
+// on 4 bytes.
+func (f *FileReader) checkBlock(blk fileBlock, kind string, i int) error {
+	if f.trusted {
+		return nil
+	}
+	aligned := bitutil.IsMultipleOf8
+	if f.relaxed {
+		aligned = isMultipleOf4
+	}
+	alignedOffset := aligned
+	if f.feather {
+		alignedOffset = isMultipleOf4
+	}
+	switch {
+	case !alignedOffset(blk.Offset):
+		return xerrors.Errorf("arrow/ipc: invalid file offset=%d for %s %d", blk.Offset, kind, i)
+	case !aligned(int64(blk.Meta)):
+		return xerrors.Errorf("arrow/ipc: invalid file metadata=%d position for %s %d", blk.Meta, kind, i)
+	case !aligned(blk.Body):
+		return xerrors.Errorf("arrow/ipc: invalid file body=%d position for %s %d", blk.Body, kind, i)
+	}
+	return nil
+}
+
+func isMultipleOf4(v int64) bool { return v&3 == 0 }
+
 func (f *FileReader) Schema() *arrow.Schema {
-	return f.schema
+	return f.recSchema
 }
 
 func (f *FileReader) NumDictionaries() int {
+	if f.footer.lazy != nil {
+		return f.footer.lazy.len(footerDictsSlot)
+	}
 	if f.footer.data == nil {
 		return 0
 	}
@@ -218,9 +686,383 @@ func (f *FileReader) NumDictionaries() int {
 }
 
 func (f *FileReader) NumRecords() int {
+	if f.footer.lazy != nil {
+		return f.footer.lazy.len(footerRecsSlot)
+	}
 	return f.footer.data.RecordBatchesLength()
 }
 
+// CurrentRetainedBytes returns the number of bytes of the buffers of the
+// records returned by the reader that have not been released yet. It is
+// only tracked for readers created with WithMaxRetainedBytes, and is 0
+// otherwise.
+func (f *FileReader) CurrentRetainedBytes() int64 {
+	if f.retained == nil {
+		return 0
+	}
+	return f.retained.Len()
+}
+
+// Block describes the location of a message within an Arrow file, as
+// recorded in the file footer. Offset is relative to the start of the file,
+// or of the embedded file when reading with WithEmbeddedFile. The message
+// metadata, including its prefix and padding, spans MetaDataLength bytes
+// from Offset and is followed by the BodyLength bytes of the message body.
+type Block struct {
+	Offset         int64
+	MetaDataLength int32
+	BodyLength     int64
+}
+
+// ReadStats reports the record batches read by a FileReader.
+type ReadStats struct {
+	Records   int64 // number of record batches decoded
+	BytesRead int64 // bytes read from the file for their messages, metadata and body
+}
+
+// Stats returns the record batches read so far by the reader, including the
+// ones read ahead and the ones whose decoding failed after their message was
+// read. Stats is safe for concurrent use.
+func (f *FileReader) Stats() ReadStats {
+	return ReadStats{
+		Records:   atomic.LoadInt64(&f.stats.records),
+		BytesRead: atomic.LoadInt64(&f.stats.bytesRead),
+	}
+}
+
+// progressReporter reports the progress of a bulk read to the function of
+// WithProgressReporter. Its methods are safe for concurrent use, and nil
+// reporters report nothing.
+type progressReporter struct {
+	fn    func(int, int, int64)
+	total int
+
+	mu    sync.Mutex
+	done  int
+	bytes int64
+}
+
+// newProgress returns the reporter of a bulk read of total records, or nil
+// if no progress is reported.
+func (f *FileReader) newProgress(total int) *progressReporter {
+	if f.progress == nil {
+		return nil
+	}
+	return &progressReporter{fn: f.progress, total: total}
+}
+
+// add reports a record decoded after reading n bytes. Calls to the function
+// are serialized so that progress is monotonic.
+func (p *progressReporter) add(n int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.bytes += n
+	p.fn(p.done, p.total, p.bytes)
+}
+
+// RecordBlocks returns the blocks of the record batches of the file, in the
+// order in which they are returned by RecordAt.
+func (f *FileReader) RecordBlocks() []Block {
+	blks := make([]Block, f.NumRecords())
+	for i := range blks {
+		blk, err := f.block(i)
+		if err != nil {
+			return blks[:i]
+		}
+		blks[i] = Block{Offset: blk.Offset, MetaDataLength: blk.Meta, BodyLength: blk.Body}
+	}
+	return blks
+}
+
+// DictionaryBlocks returns the blocks of the dictionary batches of the file,
+// in footer order.
+func (f *FileReader) DictionaryBlocks() []Block {
+	blks := make([]Block, f.NumDictionaries())
+	for i := range blks {
+		blk, err := f.dict(i)
+		if err != nil {
+			return blks[:i]
+		}
+		blks[i] = Block{Offset: blk.Offset, MetaDataLength: blk.Meta, BodyLength: blk.Body}
+	}
+	return blks
+}
+
+// NumRows returns the total number of rows of the record batches of the file.
+// Only the metadata of each record batch is read, not its body.
+// The result is cached after the first successful call.
+func (f *FileReader) NumRows() (int64, error) {
+	loc, err := f.RowLocator()
+	if err != nil {
+		return 0, err
+	}
+	return loc.NumRows(), nil
+}
+
+// RowLocator returns the locator of the rows of the file. It is built from
+// the metadata of each record batch on first use, and cached afterwards.
+func (f *FileReader) RowLocator() (*RowLocator, error) {
+	f.rows.mu.Lock()
+	defer f.rows.mu.Unlock()
+
+	if f.rows.loc != nil {
+		return f.rows.loc, nil
+	}
+
+	offsets := make([]int64, f.NumRecords()+1)
+	for i := 0; i < f.NumRecords(); i++ {
+		_, md, err := f.recordMeta(i)
+		if err != nil {
+			return nil, err
+		}
+		offsets[i+1] = offsets[i] + md.Length()
+	}
+
+	f.rows.loc = &RowLocator{offsets: offsets}
+	return f.rows.loc, nil
+}
+
+// RowAt returns the record containing the n-th row of the file, counting
+// from the first row of the first record, along with the index of that row
+// within the record. As with RecordAt, ownership of the record is
+// transferred to the caller who must call Release() to free the memory.
+func (f *FileReader) RowAt(n int64) (arrow.Record, int, error) {
+	loc, err := f.RowLocator()
+	if err != nil {
+		return nil, 0, err
+	}
+	i, row, err := loc.Locate(n)
+	if err != nil {
+		return nil, 0, err
+	}
+	rec, err := f.RecordAt(i)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rec, row, nil
+}
+
+// RecordRange returns the records [start, end) of the file, concatenated into
+// a single record. As with RecordAt, ownership of the record is transferred
+// to the caller who must call Release() to free the memory.
+func (f *FileReader) RecordRange(start, end int) (arrow.Record, error) {
+	if start < 0 || end > f.NumRecords() || start >= end {
+		return nil, xerrors.Errorf("arrow/ipc: invalid record range [%d, %d) (records=%d)", start, end, f.NumRecords())
+	}
+
+	recs := make([]arrow.Record, 0, end-start)
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+
+	var (
+		rows int64
+		p    = f.newProgress(end - start)
+	)
+	for i := start; i < end; i++ {
+		rec, err := f.recordAt(context.Background(), i, f.opts, f.lazy, p)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+		rows += rec.NumRows()
+	}
+
+	if len(recs) == 1 {
+		rec := recs[0]
+		rec.Retain()
+		return rec, nil
+	}
+
+	var (
+		schema = recs[0].Schema()
+		cols   = make([]arrow.Array, len(schema.Fields()))
+		arrs   = make([]arrow.Array, len(recs))
+	)
+	defer func() {
+		for _, col := range cols {
+			if col != nil {
+				col.Release()
+			}
+		}
+	}()
+
+	for i := range cols {
+		for j, rec := range recs {
+			arrs[j] = rec.Column(i)
+		}
+		col, err := array.Concatenate(arrs, f.mem)
+		if err != nil {
+			return nil, xerrors.Errorf("arrow/ipc: could not concatenate field %q: %w", schema.Field(i).Name, err)
+		}
+		cols[i] = col
+	}
+
+	return array.NewRecord(schema, cols, rows), nil
+}
+
+// TailRecords returns the last n records of the file, in file order, or all
+// of them if the file holds fewer than n records. Only the blocks of the
+// returned records are read. Ownership of the records is transferred to the
+// caller who must call Release() on each of them.
+func (f *FileReader) TailRecords(n int) ([]arrow.Record, error) {
+	if n < 0 {
+		return nil, xerrors.Errorf("arrow/ipc: invalid negative number of records %d", n)
+	}
+
+	start := f.NumRecords() - n
+	if start < 0 {
+		start = 0
+	}
+
+	recs := make([]arrow.Record, 0, f.NumRecords()-start)
+	p := f.newProgress(cap(recs))
+	for i := start; i < f.NumRecords(); i++ {
+		rec, err := f.recordAt(context.Background(), i, f.opts, f.lazy, p)
+		if err != nil {
+			for _, rec := range recs {
+				rec.Release()
+			}
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// RecordIsStored reports whether any buffer of the i-th record was stored
+// uncompressed in a compressed file, because compressing it did not pay off.
+// Only the metadata of the record and the size prefix of its buffers are
+// read. RecordIsStored always returns false for uncompressed records.
+func (f *FileReader) RecordIsStored(i int) (bool, error) {
+	blk, md, compressed, err := f.recordHeader(i)
+	if err != nil {
+		return false, err
+	}
+	if !compressed {
+		return false, nil
+	}
+
+	stored := false
+	err = f.sizePrefixes(i, blk, md, func(_ int, _ flatbuf.Buffer, size int64) {
+		stored = stored || size == -1
+	})
+	return stored, err
+}
+
+// RecordDecodedSize returns the size of the buffers of the i-th record once
+// decoded, without loading it. Only the metadata of the record is read and,
+// for compressed records, the uncompressed size prefix of its buffers.
+// The size does not account for the padding and overhead of allocations.
+func (f *FileReader) RecordDecodedSize(i int) (int64, error) {
+	blk, md, compressed, err := f.recordHeader(i)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		buf  flatbuf.Buffer
+		size int64
+	)
+	if !compressed {
+		for j := 0; j < md.BuffersLength(); j++ {
+			md.Buffers(&buf, j)
+			size += buf.Length()
+		}
+		return size, nil
+	}
+
+	err = f.sizePrefixes(i, blk, md, func(_ int, buf flatbuf.Buffer, n int64) {
+		if n == -1 {
+			n = buf.Length() - int64(arrow.Int64SizeBytes)
+		}
+		size += n
+	})
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// sizePrefixes calls fn with the index and the uncompressed size prefix of
+// each non-empty buffer of the compressed i-th record, described by blk and
+// md. A size of -1 denotes a buffer stored uncompressed.
+func (f *FileReader) sizePrefixes(i int, blk fileBlock, md *flatbuf.RecordBatch, fn func(j int, buf flatbuf.Buffer, size int64)) error {
+	var (
+		buf    flatbuf.Buffer
+		prefix [arrow.Int64SizeBytes]byte
+	)
+	body, err := f.recordBody(context.Background(), i, blk)
+	if err != nil {
+		return err
+	}
+	if f.decrypt != nil {
+		raw := make([]byte, blk.Body)
+		if _, err := body.ReadAt(raw, 0); err != nil {
+			return xerrors.Errorf("arrow/ipc: could not read body of record %d: %w", i, err)
+		}
+		plain, err := f.decryptBody(i, raw)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(plain)
+	}
+	for j := 0; j < md.BuffersLength(); j++ {
+		md.Buffers(&buf, j)
+		if buf.Length() == 0 {
+			continue
+		}
+		if buf.Length() < int64(len(prefix)) {
+			return xerrors.Errorf("arrow/ipc: compressed buffer %d of record %d too small (size=%d)", j, i, buf.Length())
+		}
+		if _, err := body.ReadAt(prefix[:], buf.Offset()); err != nil {
+			return xerrors.Errorf("arrow/ipc: could not read buffer %d of record %d: %w", j, i, err)
+		}
+		fn(j, buf, int64(binary.LittleEndian.Uint64(prefix[:])))
+	}
+	return nil
+}
+
+// recordMeta reads the block and the metadata of the i-th record, without
+// reading its body.
+func (f *FileReader) recordMeta(i int) (fileBlock, *flatbuf.RecordBatch, error) {
+	blk, md, _, err := f.recordHeader(i)
+	return blk, md, err
+}
+
+// recordHeader reads the block and the metadata of the i-th record, along
+// with whether its body is compressed.
+func (f *FileReader) recordHeader(i int) (fileBlock, *flatbuf.RecordBatch, bool, error) {
+	blk, err := f.block(i)
+	if err != nil {
+		return blk, nil, false, err
+	}
+	meta, err := blk.readMeta(blk.section())
+	if err != nil {
+		return blk, nil, false, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+	}
+
+	var (
+		msg = flatbuf.GetRootAsMessage(meta.Bytes(), 0)
+		md  flatbuf.RecordBatch
+	)
+	if MessageType(msg.HeaderType()) != MessageRecordBatch {
+		return blk, nil, false, xerrors.Errorf("arrow/ipc: message %d is not a Record", i)
+	}
+	initFB(&md, msg.Header)
+	opts, err := f.opts.forMessage(msg, &md)
+	if err != nil {
+		return blk, nil, false, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+	}
+	return blk, &md, md.Compression(nil) != nil || opts.legacy, nil
+}
+
 func (f *FileReader) Version() MetadataVersion {
 	return MetadataVersion(f.footer.data.Version())
 }
@@ -228,6 +1070,8 @@ func (f *FileReader) Version() MetadataVersion {
 // Close cleans up resources used by the File.
 // Close does not close the underlying reader.
 func (f *FileReader) Close() error {
+	f.releaseReadAhead()
+
 	if f.footer.data != nil {
 		f.footer.data = nil
 	}
@@ -241,166 +1085,789 @@ func (f *FileReader) Close() error {
 		f.record.Release()
 		f.record = nil
 	}
+
+	var err error
+	if f.leaks != nil {
+		err = f.leaks.check()
+		f.leaks = nil
+	}
+
+	if f.allocReset != nil {
+		f.allocReset()
+		f.allocReset = nil
+	}
+
+	if f.spill != nil {
+		serr := f.spill.Close()
+		if rerr := os.Remove(f.spill.Name()); serr == nil {
+			serr = rerr
+		}
+		f.spill = nil
+		if serr != nil && err == nil {
+			err = xerrors.Errorf("arrow/ipc: could not remove spill file: %w", serr)
+		}
+	}
+	return err
+}
+
+// Record returns the i-th record from the file.
+// The returned value is valid until the next call to Record.
+// Users need to call Retain on that Record to keep it valid for longer.
+func (f *FileReader) Record(i int) (arrow.Record, error) {
+	// release the previous record first, so that it does not count against
+	// the limit of WithMaxRetainedBytes while reading the next one.
+	if f.record != nil {
+		f.record.Release()
+		f.record = nil
+	}
+
+	record, err := f.RecordAt(i)
+	if err != nil {
+		return nil, err
+	}
+
+	f.record = record
+	return record, nil
+}
+
+// Record returns the i-th record from the file. Ownership is transferred to the
+// caller and must call Release() to free the memory. This method is safe to
+// call concurrently: decoding a record only reads the footer and schema of
+// the reader, and the state it updates, such as the read statistics and the
+// allocators of WithMaxRetainedBytes and WithLeakCheck, is synchronized.
+// With WithRecordRing, concurrent calls compete for the slots of the ring
+// and fail once all of them are held by unreleased records.
+//
+// The buffers of the record are copied out of the underlying reader into
+// memory from the reader's allocator, and lazy records keep their own copy
+// of their message body: records never share memory with the underlying
+// reader, and remain valid once the reader and its source are closed.
+func (f *FileReader) RecordAt(i int) (arrow.Record, error) {
+	return f.RecordAtContext(context.Background(), i)
+}
+
+// RecordAtContext is like RecordAt but reads the record with the given
+// context. If the underlying reader implements ReaderAtContext, its reads
+// are canceled when ctx is done; otherwise ctx is only checked before
+// reading.
+func (f *FileReader) RecordAtContext(ctx context.Context, i int) (arrow.Record, error) {
+	if f.ring != nil {
+		opts := f.opts
+		opts.ring = f.ring
+		return f.recordAt(ctx, i, opts, false, nil)
+	}
+	return f.recordAt(ctx, i, f.opts, f.lazy, nil)
+}
+
+// Validate loads every record of the file with the checks of
+// WithStrictValidation enabled, releasing each record as soon as it is
+// checked, and returns the first error found, naming the record and the
+// field at fault. Validation stops with the context's error if ctx is done.
+func (f *FileReader) Validate(ctx context.Context) error {
+	opts := f.opts
+	opts.strict = true
+	for i := 0; i < f.NumRecords(); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rec, err := f.recordAt(ctx, i, opts, false, nil)
+		if err != nil {
+			return err
+		}
+		rec.Release()
+	}
 	return nil
 }
 
-// Record returns the i-th record from the file.
-// The returned value is valid until the next call to Record.
-// Users need to call Retain on that Record to keep it valid for longer.
-func (f *FileReader) Record(i int) (arrow.Record, error) {
-	record, err := f.RecordAt(i)
-	if err != nil {
-		return nil, err
+// recordAt reads the i-th record, reporting it to p once decoded.
+func (f *FileReader) recordAt(ctx context.Context, i int, opts recordOptions, lazy bool, p *progressReporter) (arrow.Record, error) {
+	rec, read, err := f.loadRecord(ctx, i, opts, lazy)
+	atomic.AddInt64(&f.stats.bytesRead, read)
+	if err == nil && f.leaks != nil {
+		rec = f.leaks.track(rec)
+	}
+	if err == nil {
+		atomic.AddInt64(&f.stats.records, 1)
+		p.add(read)
+	}
+	if f.postHook != nil {
+		f.postHook(i, rec, read, err)
+	}
+	return rec, err
+}
+
+// recordMessage reads the message of the i-th record, with its body
+// decrypted, also returning the number of bytes read from the file for it.
+// The caller must release the message.
+func (f *FileReader) recordMessage(ctx context.Context, i int) (*Message, int64, error) {
+	var read int64
+	if i < 0 || i >= f.NumRecords() {
+		return nil, read, xerrors.Errorf("arrow/ipc: record index %d out of bounds [0, %d)", i, f.NumRecords())
+	}
+
+	if f.retained != nil {
+		if err := f.retained.wait(ctx); err != nil {
+			return nil, read, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+		}
+	}
+
+	blk, err := f.block(i)
+	if err != nil {
+		return nil, read, err
+	}
+	if f.coalesce != nil {
+		blk.r = f.coalesce
+	}
+	blk.r = &ctxReaderAt{ctx: ctx, r: blk.r}
+	if err := f.checkBlock(blk, "record", i); err != nil {
+		return nil, read, err
+	}
+
+	var msg *Message
+	if f.extBody != nil {
+		msg, err = f.externalMessage(ctx, i, blk)
+	} else {
+		msg, err = blk.NewMessage()
+	}
+	if err != nil {
+		return nil, read, err
+	}
+	read = int64(blk.Meta) + blk.Body
+
+	switch typ := msg.Type(); typ {
+	case MessageRecordBatch:
+	case MessageTensor, MessageSparseTensor:
+		msg.Release()
+		return nil, read, xerrors.Errorf("arrow/ipc: message %d is a %v, not a Record: see FileReader.Tensor", i, typ)
+	default:
+		msg.Release()
+		return nil, read, xerrors.Errorf("arrow/ipc: message %d is not a Record (got=%v)", i, typ)
+	}
+
+	if f.decrypt != nil {
+		plain, err := f.decryptBody(i, msg.body.Bytes())
+		if err != nil {
+			msg.Release()
+			return nil, read, err
+		}
+		if n := msg.msg.BodyLength(); int64(len(plain)) < n {
+			msg.Release()
+			return nil, read, xerrors.Errorf("arrow/ipc: decrypted body of record %d too short (got=%d, want=%d)", i, len(plain), n)
+		}
+		msg.body.Release()
+		msg.body = memory.NewBufferBytes(plain)
+	}
+
+	return msg, read, nil
+}
+
+// loadRecord reads and decodes the i-th record, also returning the number
+// of bytes read from the file for its message.
+func (f *FileReader) loadRecord(ctx context.Context, i int, opts recordOptions, lazy bool) (arrow.Record, int64, error) {
+	msg, read, err := f.recordMessage(ctx, i)
+	if err != nil {
+		return nil, read, err
+	}
+	defer msg.Release()
+
+	mem := f.mem
+	var (
+		arena *BumpAllocator
+		slot  *ringSlot
+		gen   uint64
+	)
+	switch {
+	case opts.ring != nil:
+		var md flatbuf.RecordBatch
+		initFB(&md, msg.msg.Header)
+		slot, gen, err = opts.ring.next(arenaSize(&md, msg.body.Bytes(), opts.memLimit))
+		if err != nil {
+			return nil, read, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+		}
+		mem = slot
+		// the slot is held by the record, if any: release it on failures.
+		defer func() {
+			if slot != nil {
+				slot.release()
+			}
+		}()
+	case opts.arena:
+		var md flatbuf.RecordBatch
+		initFB(&md, msg.msg.Header)
+		arena = NewBumpAllocator(arenaSize(&md, msg.body.Bytes(), opts.memLimit))
+		mem = arena
+	}
+
+	var rec arrow.Record
+	if lazy {
+		rec, err = newLazyRecord(f.schema, msg, mem, opts)
+	} else {
+		rec, err = newRecord(f.schema, msg.meta, bytes.NewReader(msg.body.Bytes()), mem, opts)
+	}
+	if err != nil {
+		return nil, read, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+	}
+	rec = projectRecord(rec, mem, opts)
+	switch {
+	case slot != nil:
+		rec = newRingRecord(rec, slot, gen)
+		slot = nil
+	case arena != nil:
+		rec = newArenaRecord(rec, arena)
+	}
+	return rec, read, nil
+}
+
+// RecordByOffset returns the record whose block starts at the given offset
+// in the file. As with RecordAt, ownership is transferred to the caller who
+// must call Release() to free the memory.
+func (f *FileReader) RecordByOffset(offset int64) (arrow.Record, error) {
+	n := f.NumRecords()
+	offsetOf := func(i int) int64 {
+		blk, err := f.block(i)
+		if err != nil {
+			return -1
+		}
+		return blk.Offset
+	}
+
+	i := 0
+	switch f.order {
+	case nil:
+		for i < n && offsetOf(i) != offset {
+			i++
+		}
+	default:
+		i = sort.Search(n, func(i int) bool { return offsetOf(i) >= offset })
+	}
+
+	if i == n || offsetOf(i) != offset {
+		return nil, xerrors.Errorf("arrow/ipc: no record batch at offset %d", offset)
+	}
+	return f.RecordAt(i)
+}
+
+// Read reads the current record from the underlying stream and an error, if any.
+// When the Reader reaches the end of the underlying stream, it returns (nil, io.EOF).
+//
+// The returned record value is valid until the next call to Read.
+// Users need to call Retain on that Record to keep it valid for longer.
+func (f *FileReader) Read() (rec arrow.Record, err error) {
+	if f.filter != nil {
+		if f.err = f.skipRecords(); f.err != nil {
+			return nil, f.err
+		}
+	}
+	if f.irec == f.NumRecords() {
+		return nil, io.EOF
+	}
+	if f.ahead.n > 0 {
+		rec, f.err = f.readPrefetched(f.irec)
+	} else {
+		rec, f.err = f.Record(f.irec)
+	}
+	f.irec++
+	return rec, f.err
+}
+
+// ReadAt reads the i-th record from the underlying stream and an error, if any.
+func (f *FileReader) ReadAt(i int64) (arrow.Record, error) {
+	return f.Record(int(i))
+}
+
+// Table reads all the records of the file and returns them as a single table.
+// Reading stops with the context's error if ctx is done before all records
+// have been read. The caller must call Release() on the returned table.
+func (f *FileReader) Table(ctx context.Context) (arrow.Table, error) {
+	recs := make([]arrow.Record, 0, f.NumRecords())
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+
+	p := f.newProgress(f.NumRecords())
+	for i := 0; i < f.NumRecords(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rec, err := f.recordAt(ctx, i, f.opts, f.lazy, p)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+
+	return array.NewTableFromRecords(f.recSchema, recs), nil
+}
+
+// RowLocator maps the rows of a file, numbered across all its record
+// batches, to the record batch holding them.
+type RowLocator struct {
+	offsets []int64 // index of the first row of each record, then the total number of rows
+}
+
+// NumRows returns the total number of rows.
+func (loc *RowLocator) NumRows() int64 { return loc.offsets[len(loc.offsets)-1] }
+
+// Locate returns the index of the record holding the n-th row, and the index
+// of that row within the record.
+func (loc *RowLocator) Locate(n int64) (rec, row int, err error) {
+	if n < 0 || n >= loc.NumRows() {
+		return 0, 0, xerrors.Errorf("arrow/ipc: row index %d out of bounds [0, %d)", n, loc.NumRows())
+	}
+	// first record starting after n, skipping empty records.
+	i := sort.Search(len(loc.offsets), func(i int) bool { return loc.offsets[i] > n }) - 1
+	return i, int(n - loc.offsets[i]), nil
+}
+
+// recordOptions holds the reader settings used to decode a record batch.
+type recordOptions struct {
+	swap     bool                  // byte-swap multi-byte values to the host endianness
+	zstdDict []byte                // dictionary for zstd-compressed bodies
+	memLimit int64                 // maximum bytes allocated for the record, if > 0
+	strict   bool                  // validate the loaded data
+	registry *DecompressorRegistry // decompressors consulted before the default ones
+	feather  bool                  // honor the legacy compression metadata of messages
+
+	legacy      bool            // whether the body is compressed as declared by the legacy metadata
+	legacyCodec CompressionType // codec declared by the legacy compression metadata
+
+	rescale map[string]*arrow.Decimal128Type // target types of rescaled decimal fields
+	trace   func(BufferTrace)                // called after each buffer is loaded, if not nil
+	utc     bool                             // declare zoned timestamps in UTC
+	casts   map[string]arrow.DataType        // target types of cast fields
+	colMem  map[int]memory.Allocator         // allocators of the top-level columns, by index
+
+	target      *arrow.Schema // schema the records are projected onto, if not nil
+	fillMissing bool          // fill the target fields absent from records with nulls
+
+	decompressNP int // number of goroutines decompressing the buffers of a record
+
+	ring  *recordRing // allocates the buffers of the record, if not nil
+	arena bool        // whether the record is allocated from its own arena
+
+	bufferMem func(BufferRole, arrow.Field) memory.Allocator // selects the allocator of each buffer, if not nil
+}
+
+func newRecordOptions(cfg *config) recordOptions {
+	return recordOptions{
+		zstdDict: cfg.zstdDict,
+		memLimit: cfg.memLimit,
+		strict:   cfg.strict,
+		registry: cfg.registry,
+		feather:  cfg.feather,
+		rescale:  cfg.rescale,
+		trace:    cfg.trace,
+		utc:      cfg.utc,
+		casts:    cfg.casts,
+		colMem:   cfg.colMem,
+
+		target:      cfg.target,
+		fillMissing: cfg.fillMissing,
+
+		decompressNP: cfg.decompressNP,
+
+		bufferMem: cfg.bufferMem,
+
+		arena: cfg.arena,
+	}
+}
+
+// forMessage returns the options decoding the body of msg. With Feather
+// compatibility, a body without compression metadata is decompressed with
+// the codec declared by the legacy compression metadata of msg, if any.
+func (opts recordOptions) forMessage(msg *flatbuf.Message, md *flatbuf.RecordBatch) (recordOptions, error) {
+	// body compression was introduced with the V5 metadata: an older message
+	// declaring it is malformed, and its body cannot be trusted to be compressed.
+	if v := MetadataVersion(msg.Version()); v < MetadataV5 && md.Compression(nil) != nil {
+		return opts, xerrors.Errorf("arrow/ipc: malformed record batch: body compression requires metadata version %v or later (got %v)", MetadataV5, v)
 	}
 
-	if f.record != nil {
-		f.record.Release()
+	if !opts.feather || md.Compression(nil) != nil {
+		return opts, nil
 	}
-
-	f.record = record
-	return record, nil
+	codec, ok, err := legacyCompression(msg)
+	if err != nil {
+		return opts, err
+	}
+	opts.legacy, opts.legacyCodec = ok, codec
+	return opts, nil
 }
 
-// Record returns the i-th record from the file. Ownership is transferred to the
-// caller and must call Release() to free the memory. This method is safe to
-// call concurrently.
-func (f *FileReader) RecordAt(i int) (arrow.Record, error) {
-	if i < 0 || i > f.NumRecords() {
-		panic("arrow/ipc: record index out of bounds")
+// columnAllocator returns the allocator for the i-th top-level column,
+// defaulting to mem.
+func (opts recordOptions) columnAllocator(i int, mem memory.Allocator) memory.Allocator {
+	if m, ok := opts.colMem[i]; ok {
+		return m
 	}
+	return mem
+}
 
-	blk, err := f.block(i)
+// recordSchema returns the schema of the records decoded from a file or
+// stream declaring schema, after the read-time conversions of opts.
+func recordSchema(schema *arrow.Schema, opts recordOptions) (*arrow.Schema, error) {
+	if opts.utc {
+		schema = utcSchema(schema)
+	}
+	schema, err := convertedSchema(schema, opts)
 	if err != nil {
 		return nil, err
 	}
-	switch {
-	case !bitutil.IsMultipleOf8(blk.Offset):
-		return nil, xerrors.Errorf("arrow/ipc: invalid file offset=%d for record %d", blk.Offset, i)
-	case !bitutil.IsMultipleOf8(int64(blk.Meta)):
-		return nil, xerrors.Errorf("arrow/ipc: invalid file metadata=%d position for record %d", blk.Meta, i)
-	case !bitutil.IsMultipleOf8(blk.Body):
-		return nil, xerrors.Errorf("arrow/ipc: invalid file body=%d position for record %d", blk.Body, i)
-	}
+	return targetedSchema(schema, opts)
+}
 
-	msg, err := blk.NewMessage()
+// convertedSchema returns the schema of records whose columns, loaded
+// following schema, went through convertField.
+func convertedSchema(schema *arrow.Schema, opts recordOptions) (*arrow.Schema, error) {
+	schema, err := rescaledSchema(schema, opts.rescale)
 	if err != nil {
 		return nil, err
 	}
-	defer msg.Release()
-
-	if msg.Type() != MessageRecordBatch {
-		return nil, xerrors.Errorf("arrow/ipc: message %d is not a Record", i)
-	}
+	return castedSchema(schema, opts.casts)
+}
 
-	return newRecord(f.schema, msg.meta, bytes.NewReader(msg.body.Bytes()), f.mem), nil
+// ReaderAtContext is implemented by readers, such as readers of remote
+// objects, whose ReadAt operations can be canceled through a context.
+type ReaderAtContext interface {
+	ReadAtContext(ctx context.Context, p []byte, off int64) (int, error)
 }
 
-// Read reads the current record from the underlying stream and an error, if any.
-// When the Reader reaches the end of the underlying stream, it returns (nil, io.EOF).
-//
-// The returned record value is valid until the next call to Read.
-// Users need to call Retain on that Record to keep it valid for longer.
-func (f *FileReader) Read() (rec arrow.Record, err error) {
-	if f.irec == f.NumRecords() {
-		return nil, io.EOF
-	}
-	rec, f.err = f.Record(f.irec)
-	f.irec++
-	return rec, f.err
+// ctxReaderAt reads from r with the context ctx.
+type ctxReaderAt struct {
+	ctx context.Context
+	r   io.ReaderAt
 }
 
-// ReadAt reads the i-th record from the underlying stream and an error, if any.
-func (f *FileReader) ReadAt(i int64) (arrow.Record, error) {
-	return f.Record(int(i))
+func (r *ctxReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if rc, ok := r.r.(ReaderAtContext); ok {
+		return rc.ReadAtContext(r.ctx, p, off)
+	}
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.ReadAt(p, off)
 }
 
-func newRecord(schema *arrow.Schema, meta *memory.Buffer, body ReadAtSeeker, mem memory.Allocator) arrow.Record {
+// newRecord decodes a record batch from its metadata and body.
+// Malformed input detected while loading the arrays is reported as an error
+// naming the field being loaded.
+func newRecord(schema *arrow.Schema, meta *memory.Buffer, body ReadAtSeeker, mem memory.Allocator, opts recordOptions) (rec arrow.Record, err error) {
 	var (
 		msg   = flatbuf.GetRootAsMessage(meta.Bytes(), 0)
 		md    flatbuf.RecordBatch
-		codec decompressor
+		codec Decompressor
 	)
 	initFB(&md, msg.Header)
 	rows := md.Length()
 
-	bodyCompress := md.Compression(nil)
-	if bodyCompress != nil {
-		codec = getDecompressor(bodyCompress.Codec())
+	if got, want := md.NodesLength(), numFieldNodes(schema); got != want {
+		return nil, xerrors.Errorf("arrow/ipc: record batch has %d field nodes, schema requires %d", got, want)
+	}
+
+	if opts.utc {
+		schema = utcSchema(schema)
+	}
+
+	opts, err = opts.forMessage(msg, &md)
+	if err != nil {
+		return nil, err
+	}
+	codec, err = bodyDecompressor(&md, opts)
+	if err != nil {
+		return nil, err
+	}
+	if codec != nil {
 		defer codec.Close()
 	}
 
-	ctx := &arrayLoaderContext{
-		src: ipcSource{
-			meta:  &md,
-			r:     body,
-			codec: codec,
-			mem:   mem,
-		},
-		max: kMaxNestingDepth,
+	ctx := newArrayLoaderContext(&md, body, codec, mem, opts)
+	if codec != nil && opts.decompressNP > 1 {
+		if err := ctx.src.predecompress(schema, mem, opts.decompressNP, opts); err != nil {
+			return nil, err
+		}
+		defer ctx.src.releaseDecoded()
 	}
 
 	cols := make([]arrow.Array, len(schema.Fields()))
 	for i, field := range schema.Fields() {
-		cols[i] = ctx.loadArray(field.Type)
+		ctx.src.mem = opts.columnAllocator(i, mem)
+		arr, err := ctx.loadField(field)
+		if err != nil {
+			return nil, err
+		}
+		arr, err = convertField(ctx.src.mem, field, arr, opts)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = arr
 		defer cols[i].Release()
 	}
 
-	return array.NewRecord(schema, cols, rows)
+	schema, err = convertedSchema(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return array.NewRecord(schema, cols, rows), nil
+}
+
+// convertField applies the read-time conversions of opts to the array arr
+// loaded for field. convertField takes ownership of arr.
+func convertField(mem memory.Allocator, field arrow.Field, arr arrow.Array, opts recordOptions) (arrow.Array, error) {
+	if dt, ok := opts.rescale[field.Name]; ok {
+		out, err := rescaleDecimal(mem, arr.(*array.Decimal128), dt)
+		arr.Release()
+		if err != nil {
+			return nil, xerrors.Errorf("arrow/ipc: could not rescale field %q: %w", field.Name, err)
+		}
+		arr = out
+	}
+
+	if dt, ok := opts.casts[field.Name]; ok {
+		out, err := castArray(mem, arr, dt)
+		arr.Release()
+		if err != nil {
+			return nil, xerrors.Errorf("arrow/ipc: could not cast field %q: %w", field.Name, err)
+		}
+		arr = out
+	}
+
+	return arr, nil
+}
+
+// numFieldNodes returns the number of field nodes a record batch following
+// schema must hold: one per array, including nested children.
+func numFieldNodes(schema *arrow.Schema) int {
+	n := 0
+	for _, field := range schema.Fields() {
+		n += numTypeNodes(field.Type)
+	}
+	return n
+}
+
+func numTypeNodes(dt arrow.DataType) int {
+	switch dt := dt.(type) {
+	case *arrow.ListType:
+		return 1 + numTypeNodes(dt.Elem())
+	case *arrow.FixedSizeListType:
+		return 1 + numTypeNodes(dt.Elem())
+	case *arrow.MapType:
+		return 1 + numTypeNodes(dt.ValueType())
+	case *arrow.StructType:
+		n := 1
+		for _, field := range dt.Fields() {
+			n += numTypeNodes(field.Type)
+		}
+		return n
+	case arrow.ExtensionType:
+		return numTypeNodes(dt.StorageType())
+	default:
+		return 1
+	}
+}
+
+// BufferTrace describes how a buffer of a record was read.
+type BufferTrace struct {
+	Index        int   // index of the buffer in the record batch
+	Offset       int64 // offset of the buffer in the record batch body
+	Length       int64 // size of the buffer in the record batch body
+	Size         int64 // size of the loaded, uncompressed, buffer
+	Decompressed bool  // whether the buffer was decoded by the compression codec
+}
+
+// BufferRole tells what a buffer of an array holds.
+type BufferRole int8
+
+const (
+	// BufferValidity is the validity bitmap of an array.
+	BufferValidity BufferRole = iota
+	// BufferOffsets holds the value offsets of a binary, string, list or
+	// map array.
+	BufferOffsets
+	// BufferData holds the values of a fixed-width or binary array.
+	BufferData
+)
+
+func (r BufferRole) String() string {
+	switch r {
+	case BufferValidity:
+		return "validity"
+	case BufferOffsets:
+		return "offsets"
+	case BufferData:
+		return "data"
+	}
+	return fmt.Sprintf("BufferRole(%d)", int8(r))
+}
+
+// numTypeBuffers returns the number of buffers an array of type dt spans in
+// a record batch, including the buffers of its children.
+func numTypeBuffers(dt arrow.DataType) int {
+	switch dt := dt.(type) {
+	case *arrow.NullType:
+		return 0
+	case *arrow.BinaryType, *arrow.StringType:
+		return 3
+	case *arrow.ListType:
+		return 2 + numTypeBuffers(dt.Elem())
+	case *arrow.FixedSizeListType:
+		return 1 + numTypeBuffers(dt.Elem())
+	case *arrow.MapType:
+		return 2 + numTypeBuffers(dt.ValueType())
+	case *arrow.StructType:
+		n := 1
+		for _, field := range dt.Fields() {
+			n += numTypeBuffers(field.Type)
+		}
+		return n
+	case arrow.ExtensionType:
+		return numTypeBuffers(dt.StorageType())
+	default:
+		return 2
+	}
 }
 
 type ipcSource struct {
 	meta  *flatbuf.RecordBatch
 	r     ReadAtSeeker
-	codec decompressor
+	codec Decompressor
 	mem   memory.Allocator
+	limit int64             // maximum bytes allocated for the record, if > 0
+	used  int64             // bytes allocated so far for the record
+	trace func(BufferTrace) // called after each buffer is loaded, if not nil
+
+	decoded []decodedBuffer // buffers decompressed ahead of loading, by index, if not nil
+}
+
+// reserve accounts for n more bytes allocated for the record, failing
+// before the allocation takes place if it would exceed the memory limit.
+func (src *ipcSource) reserve(n int64) error {
+	src.used += n
+	if src.limit > 0 && src.used > src.limit {
+		return xerrors.Errorf("%w (limit=%d, requested=%d)", errRecordMemoryLimit, src.limit, src.used)
+	}
+	return nil
 }
 
-func (src *ipcSource) buffer(i int) *memory.Buffer {
+// buffer loads the i-th buffer of the record, allocating it from mem.
+func (src *ipcSource) buffer(i int, mem memory.Allocator) *memory.Buffer {
 	var buf flatbuf.Buffer
-	if !src.meta.Buffers(&buf, i) {
+	if i >= src.meta.BuffersLength() || !src.meta.Buffers(&buf, i) {
 		panic("buffer index out of bound")
 	}
 	if buf.Length() == 0 {
+		if src.trace != nil {
+			src.trace(BufferTrace{Index: i, Offset: buf.Offset()})
+		}
 		return memory.NewBufferBytes(nil)
 	}
 
-	raw := memory.NewResizableBuffer(src.mem)
+	if i < len(src.decoded) && src.decoded[i].buf != nil {
+		raw, decoded := src.decoded[i].buf, src.decoded[i].decoded
+		src.decoded[i].buf = nil
+		if src.trace != nil {
+			src.trace(BufferTrace{
+				Index:        i,
+				Offset:       buf.Offset(),
+				Length:       buf.Length(),
+				Size:         int64(raw.Len()),
+				Decompressed: decoded,
+			})
+		}
+		return raw
+	}
+
+	raw := memory.NewResizableBuffer(mem)
 	if src.codec == nil {
+		if err := src.reserve(buf.Length()); err != nil {
+			raw.Release()
+			panic(err)
+		}
 		raw.Resize(int(buf.Length()))
 		_, err := src.r.ReadAt(raw.Bytes(), buf.Offset())
 		if err != nil {
+			raw.Release()
 			panic(err)
 		}
-	} else {
-		sr := io.NewSectionReader(src.r, buf.Offset(), buf.Length())
-		var uncompressedSize uint64
-
-		err := binary.Read(sr, binary.LittleEndian, &uncompressedSize)
-		if err != nil {
-			panic(err)
+		if src.trace != nil {
+			src.trace(BufferTrace{Index: i, Offset: buf.Offset(), Length: buf.Length(), Size: buf.Length()})
 		}
+		return raw
+	}
 
-		var r io.Reader = sr
-		// check for an uncompressed buffer
-		if int64(uncompressedSize) != -1 {
-			raw.Resize(int(uncompressedSize))
-			src.codec.Reset(sr)
-			r = src.codec
-		} else {
-			raw.Resize(int(buf.Length()))
-		}
+	decoded, err := src.decompress(raw, buf.Offset(), buf.Length())
+	if err != nil {
+		raw.Release()
+		panic(err)
+	}
+	if src.trace != nil {
+		src.trace(BufferTrace{
+			Index:        i,
+			Offset:       buf.Offset(),
+			Length:       buf.Length(),
+			Size:         int64(raw.Len()),
+			Decompressed: decoded,
+		})
+	}
+	return raw
+}
 
-		if _, err = io.ReadFull(r, raw.Bytes()); err != nil {
-			panic(err)
+// decompress reads the compressed buffer at [offset, offset+length) into raw,
+// reporting whether it was decoded by the codec or stored uncompressed.
+// The compressed bytes are staged in a pooled scratch buffer which is
+// recycled once its content has been decoded into raw.
+func (src *ipcSource) decompress(raw *memory.Buffer, offset, length int64) (bool, error) {
+	if length < int64(arrow.Int64SizeBytes) {
+		return false, xerrors.Errorf("arrow/ipc: compressed buffer too small (size=%d)", length)
+	}
+
+	scratch := getScratch(int(length))
+	defer putScratch(scratch)
+
+	data := *scratch
+	if _, err := src.r.ReadAt(data, offset); err != nil {
+		return false, err
+	}
+
+	uncompressedSize := int64(binary.LittleEndian.Uint64(data))
+	data = data[arrow.Int64SizeBytes:]
+
+	// check for an uncompressed buffer
+	if uncompressedSize == -1 {
+		if err := src.reserve(int64(len(data))); err != nil {
+			return false, err
 		}
+		raw.Resize(len(data))
+		copy(raw.Bytes(), data)
+		return false, nil
 	}
 
-	return raw
+	if err := src.reserve(uncompressedSize); err != nil {
+		return false, err
+	}
+	raw.Resize(int(uncompressedSize))
+	switch codec := src.codec.(type) {
+	case bufferDecompressor:
+		out, err := codec.DecodeAll(data, raw.Bytes()[:0])
+		if err != nil {
+			return false, err
+		}
+		if int64(len(out)) != uncompressedSize {
+			return false, xerrors.Errorf("arrow/ipc: invalid decompressed buffer size (got=%d, want=%d)", len(out), uncompressedSize)
+		}
+	default:
+		codec.Reset(bytes.NewReader(data))
+		if _, err := io.ReadFull(codec, raw.Bytes()); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
 }
 
 func (src *ipcSource) fieldMetadata(i int) *flatbuf.FieldNode {
 	var node flatbuf.FieldNode
-	if !src.meta.Nodes(&node, i) {
+	if i >= src.meta.NodesLength() || !src.meta.Nodes(&node, i) {
 		panic("field metadata out of bound")
 	}
 	return &node
@@ -411,6 +1878,51 @@ type arrayLoaderContext struct {
 	ifield  int
 	ibuffer int
 	max     int
+	swap    bool     // byte-swap multi-byte values to the host endianness
+	path    []string // names of the fields being loaded, for error reporting
+	strict  bool     // validate the loaded data
+
+	cur       arrow.Field                                    // field of the array being loaded
+	bufferMem func(BufferRole, arrow.Field) memory.Allocator // selects the allocator of each buffer, if not nil
+}
+
+func newArrayLoaderContext(md *flatbuf.RecordBatch, body ReadAtSeeker, codec Decompressor, mem memory.Allocator, opts recordOptions) *arrayLoaderContext {
+	return &arrayLoaderContext{
+		src: ipcSource{
+			meta:  md,
+			r:     body,
+			codec: codec,
+			mem:   mem,
+			limit: opts.memLimit,
+			trace: opts.trace,
+		},
+		max:    kMaxNestingDepth,
+		swap:   opts.swap,
+		strict: opts.strict,
+
+		bufferMem: opts.bufferMem,
+	}
+}
+
+// loadField loads the array of the next top-level field. Malformed input
+// detected while loading the array is reported as an error naming the field
+// being loaded.
+func (ctx *arrayLoaderContext) loadField(field arrow.Field) (arr arrow.Array, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			path := strings.Join(ctx.path, ".")
+			switch e := e.(type) {
+			case error:
+				err = xerrors.Errorf("arrow/ipc: could not load field %q: %w", path, e)
+			default:
+				err = xerrors.Errorf("arrow/ipc: could not load field %q: %v", path, e)
+			}
+		}
+	}()
+
+	ctx.path = append(ctx.path[:0], field.Name)
+	ctx.cur = field
+	return ctx.loadArray(field.Type), nil
 }
 
 func (ctx *arrayLoaderContext) field() *flatbuf.FieldNode {
@@ -419,12 +1931,29 @@ func (ctx *arrayLoaderContext) field() *flatbuf.FieldNode {
 	return field
 }
 
-func (ctx *arrayLoaderContext) buffer() *memory.Buffer {
-	buf := ctx.src.buffer(ctx.ibuffer)
+// buffer loads the next buffer, holding the given role for the field being
+// loaded.
+func (ctx *arrayLoaderContext) buffer(role BufferRole) *memory.Buffer {
+	mem := ctx.src.mem
+	if ctx.bufferMem != nil {
+		if m := ctx.bufferMem(role, ctx.cur); m != nil {
+			mem = m
+		}
+	}
+	buf := ctx.src.buffer(ctx.ibuffer, mem)
 	ctx.ibuffer++
 	return buf
 }
 
+// offsets loads the next buffer as a buffer of int32 value offsets.
+func (ctx *arrayLoaderContext) offsets() *memory.Buffer {
+	buf := ctx.buffer(BufferOffsets)
+	if ctx.swap {
+		swapWords(buf.Bytes(), arrow.Int32SizeBytes)
+	}
+	return buf
+}
+
 func (ctx *arrayLoaderContext) loadArray(dt arrow.DataType) arrow.Array {
 	switch dt := dt.(type) {
 	case *arrow.NullType:
@@ -470,6 +1999,9 @@ func (ctx *arrayLoaderContext) loadArray(dt arrow.DataType) arrow.Array {
 	}
 }
 
+// loadCommon reads the field node and validity bitmap of the next array.
+// Field nodes carry no offset: writers shift sliced arrays so their buffers
+// start at the first value, hence loaders always build arrays at offset 0.
 func (ctx *arrayLoaderContext) loadCommon(nbufs int) (*flatbuf.FieldNode, []*memory.Buffer) {
 	buffers := make([]*memory.Buffer, 0, nbufs)
 	field := ctx.field()
@@ -479,26 +2011,40 @@ func (ctx *arrayLoaderContext) loadCommon(nbufs int) (*flatbuf.FieldNode, []*mem
 	case 0:
 		ctx.ibuffer++
 	default:
-		buf = ctx.buffer()
+		buf = ctx.buffer(BufferValidity)
 	}
 	buffers = append(buffers, buf)
 
+	if ctx.strict {
+		if err := validateBitmap(buf, field.Length(), field.NullCount()); err != nil {
+			releaseBuffers(buffers)
+			panic(err)
+		}
+	}
+
 	return field, buffers
 }
 
-func (ctx *arrayLoaderContext) loadChild(dt arrow.DataType) arrow.Array {
+func (ctx *arrayLoaderContext) loadChild(field arrow.Field) arrow.Array {
 	if ctx.max == 0 {
 		panic("arrow/ipc: nested type limit reached")
 	}
 	ctx.max--
-	sub := ctx.loadArray(dt)
+	ctx.path = append(ctx.path, field.Name)
+	parent := ctx.cur
+	ctx.cur = field
+	sub := ctx.loadArray(field.Type)
+	ctx.cur = parent
+	ctx.path = ctx.path[:len(ctx.path)-1]
 	ctx.max++
 	return sub
 }
 
 func (ctx *arrayLoaderContext) loadNull() arrow.Array {
 	field := ctx.field()
-	data := array.NewData(arrow.Null, int(field.Length()), nil, nil, int(field.NullCount()), 0)
+	// all the values of a null array are null, whatever the null count
+	// recorded by the producer (some write 0).
+	data := array.NewData(arrow.Null, int(field.Length()), nil, nil, int(field.Length()), 0)
 	defer data.Release()
 
 	return array.MakeFromData(data)
@@ -506,17 +2052,25 @@ func (ctx *arrayLoaderContext) loadNull() arrow.Array {
 
 func (ctx *arrayLoaderContext) loadPrimitive(dt arrow.DataType) arrow.Array {
 	field, buffers := ctx.loadCommon(2)
+	defer func() { releaseBuffers(buffers) }()
 
 	switch field.Length() {
 	case 0:
 		buffers = append(buffers, nil)
 		ctx.ibuffer++
 	default:
-		buffers = append(buffers, ctx.buffer())
+		buf := ctx.buffer(BufferData)
+		if ctx.swap {
+			swapValues(dt, buf.Bytes())
+		}
+		buffers = append(buffers, buf)
+		if ctx.strict {
+			if err := validateValues(buf, field.Length(), dt.(arrow.FixedWidthDataType).BitWidth()); err != nil {
+				panic(err)
+			}
+		}
 	}
 
-	defer releaseBuffers(buffers)
-
 	data := array.NewData(dt, int(field.Length()), buffers, nil, int(field.NullCount()), 0)
 	defer data.Release()
 
@@ -525,8 +2079,15 @@ func (ctx *arrayLoaderContext) loadPrimitive(dt arrow.DataType) arrow.Array {
 
 func (ctx *arrayLoaderContext) loadBinary(dt arrow.DataType) arrow.Array {
 	field, buffers := ctx.loadCommon(3)
-	buffers = append(buffers, ctx.buffer(), ctx.buffer())
-	defer releaseBuffers(buffers)
+	defer func() { releaseBuffers(buffers) }()
+	buffers = append(buffers, ctx.offsets())
+	buffers = append(buffers, ctx.buffer(BufferData))
+
+	if ctx.strict {
+		if err := validateOffsets(buffers[1], field.Length(), int64(buffers[2].Len())); err != nil {
+			panic(err)
+		}
+	}
 
 	data := array.NewData(dt, int(field.Length()), buffers, nil, int(field.NullCount()), 0)
 	defer data.Release()
@@ -536,8 +2097,14 @@ func (ctx *arrayLoaderContext) loadBinary(dt arrow.DataType) arrow.Array {
 
 func (ctx *arrayLoaderContext) loadFixedSizeBinary(dt *arrow.FixedSizeBinaryType) arrow.Array {
 	field, buffers := ctx.loadCommon(2)
-	buffers = append(buffers, ctx.buffer())
-	defer releaseBuffers(buffers)
+	defer func() { releaseBuffers(buffers) }()
+	buffers = append(buffers, ctx.buffer(BufferData))
+
+	if ctx.strict {
+		if err := validateValues(buffers[1], field.Length(), dt.BitWidth()); err != nil {
+			panic(err)
+		}
+	}
 
 	data := array.NewData(dt, int(field.Length()), buffers, nil, int(field.NullCount()), 0)
 	defer data.Release()
@@ -547,26 +2114,45 @@ func (ctx *arrayLoaderContext) loadFixedSizeBinary(dt *arrow.FixedSizeBinaryType
 
 func (ctx *arrayLoaderContext) loadMap(dt *arrow.MapType) arrow.Array {
 	field, buffers := ctx.loadCommon(2)
-	buffers = append(buffers, ctx.buffer())
-	defer releaseBuffers(buffers)
+	defer func() { releaseBuffers(buffers) }()
+	buffers = append(buffers, ctx.offsets())
 
-	sub := ctx.loadChild(dt.ValueType())
+	sub := ctx.loadChild(dt.ValueField())
 	defer sub.Release()
 
+	if ctx.strict {
+		if err := validateOffsets(buffers[1], field.Length(), int64(sub.Len())); err != nil {
+			panic(err)
+		}
+	}
+
 	data := array.NewData(dt, int(field.Length()), buffers, []arrow.ArrayData{sub.Data()}, int(field.NullCount()), 0)
 	defer data.Release()
 
-	return array.NewMapData(data)
+	arr := array.NewMapData(data)
+	if ctx.strict && dt.KeysSorted {
+		if err := validateSortedKeys(arr); err != nil {
+			arr.Release()
+			panic(err)
+		}
+	}
+	return arr
 }
 
 func (ctx *arrayLoaderContext) loadList(dt *arrow.ListType) arrow.Array {
 	field, buffers := ctx.loadCommon(2)
-	buffers = append(buffers, ctx.buffer())
-	defer releaseBuffers(buffers)
+	defer func() { releaseBuffers(buffers) }()
+	buffers = append(buffers, ctx.offsets())
 
-	sub := ctx.loadChild(dt.Elem())
+	sub := ctx.loadChild(dt.ElemField())
 	defer sub.Release()
 
+	if ctx.strict {
+		if err := validateOffsets(buffers[1], field.Length(), int64(sub.Len())); err != nil {
+			panic(err)
+		}
+	}
+
 	data := array.NewData(dt, int(field.Length()), buffers, []arrow.ArrayData{sub.Data()}, int(field.NullCount()), 0)
 	defer data.Release()
 
@@ -577,9 +2163,13 @@ func (ctx *arrayLoaderContext) loadFixedSizeList(dt *arrow.FixedSizeListType) ar
 	field, buffers := ctx.loadCommon(1)
 	defer releaseBuffers(buffers)
 
-	sub := ctx.loadChild(dt.Elem())
+	sub := ctx.loadChild(dt.ElemField())
 	defer sub.Release()
 
+	if want := int64(dt.Len()) * field.Length(); ctx.strict && int64(sub.Len()) != want {
+		panic(xerrors.Errorf("arrow/ipc: fixed-size list child has %d elements, want %d (list size=%d, length=%d)", sub.Len(), want, dt.Len(), field.Length()))
+	}
+
 	data := array.NewData(dt, int(field.Length()), buffers, []arrow.ArrayData{sub.Data()}, int(field.NullCount()), 0)
 	defer data.Release()
 
@@ -592,15 +2182,20 @@ func (ctx *arrayLoaderContext) loadStruct(dt *arrow.StructType) arrow.Array {
 
 	arrs := make([]arrow.Array, len(dt.Fields()))
 	subs := make([]arrow.ArrayData, len(dt.Fields()))
-	for i, f := range dt.Fields() {
-		arrs[i] = ctx.loadChild(f.Type)
-		subs[i] = arrs[i].Data()
-	}
 	defer func() {
 		for i := range arrs {
-			arrs[i].Release()
+			if arrs[i] != nil {
+				arrs[i].Release()
+			}
 		}
 	}()
+	for i, f := range dt.Fields() {
+		arrs[i] = ctx.loadChild(f)
+		subs[i] = arrs[i].Data()
+		if n := int64(arrs[i].Len()); ctx.strict && n < field.Length() {
+			panic(xerrors.Errorf("arrow/ipc: struct child %q has %d elements, want at least %d", f.Name, n, field.Length()))
+		}
+	}
 
 	data := array.NewData(dt, int(field.Length()), buffers, subs, int(field.NullCount()), 0)
 	defer data.Release()
@@ -651,6 +2246,116 @@ func readDictionary(meta *memory.Buffer, types dictTypeMap, r ReadAtSeeker) (int
 	panic("not implemented")
 }
 
+// swapValues byte-swaps, in place, the values buffer of a fixed-width
+// data type loaded from a file with non-native endianness.
+func swapValues(dt arrow.DataType, buf []byte) {
+	switch dt := dt.(type) {
+	case *arrow.BooleanType:
+		// bit-packed: nothing to swap.
+	case *arrow.DayTimeIntervalType:
+		swapWords(buf, arrow.Int32SizeBytes)
+	case *arrow.MonthDayNanoIntervalType:
+		for i := 0; i+arrow.MonthDayNanoIntervalSizeBytes <= len(buf); i += arrow.MonthDayNanoIntervalSizeBytes {
+			swapWords(buf[i:i+8], arrow.Int32SizeBytes)
+			swapWords(buf[i+8:i+16], arrow.Int64SizeBytes)
+		}
+	case *arrow.Decimal128Type:
+		// a decimal128 is a single 128b integer: reverse all of its bytes.
+		swapWords(buf, arrow.Decimal128SizeBytes)
+	case arrow.FixedWidthDataType:
+		swapWords(buf, dt.BitWidth()/8)
+	}
+}
+
+// checkEndianness reports whether buffers declared with the given
+// endianness must be byte-swapped to the host endianness, failing if they
+// must and byteSwap is false.
+func checkEndianness(declared, host flatbuf.Endianness, byteSwap bool) (bool, error) {
+	if declared == host {
+		return false, nil
+	}
+	if !byteSwap {
+		return false, xerrors.Errorf("arrow/ipc: data endianness (%v) does not match host endianness (%v)",
+			flatbuf.EnumNamesEndianness[declared], flatbuf.EnumNamesEndianness[host])
+	}
+	return true, nil
+}
+
+// swapWords reverses the byte order of each consecutive word of the given size.
+func swapWords(buf []byte, size int) {
+	if size <= 1 {
+		return
+	}
+	for i := 0; i+size <= len(buf); i += size {
+		w := buf[i : i+size]
+		for j, k := 0, size-1; j < k; j, k = j+1, k-1 {
+			w[j], w[k] = w[k], w[j]
+		}
+	}
+}
+
+// validateSortedKeys checks that the keys of each map of arr are sorted in
+// ascending order. Maps with key types without a natural ordering are not
+// checked.
+func validateSortedKeys(arr *array.Map) error {
+	less := lessFunc(arr.Keys())
+	if less == nil {
+		return nil
+	}
+
+	offsets := arr.Offsets()
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			continue
+		}
+		beg, end := int(offsets[i]), int(offsets[i+1])
+		for j := beg + 1; j < end; j++ {
+			if less(j, j-1) {
+				return xerrors.Errorf("arrow/ipc: keys of map %d are not sorted (key %d < key %d)", i, j-beg, j-beg-1)
+			}
+		}
+	}
+	return nil
+}
+
+// lessFunc returns a function reporting whether the i-th value of arr is
+// less than its j-th value, or nil if the values of arr are not ordered.
+func lessFunc(arr arrow.Array) func(i, j int) bool {
+	switch arr := arr.(type) {
+	case *array.Int8:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Int16:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Int32:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Int64:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Uint8:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Uint16:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Uint32:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Uint64:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Float32:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Float64:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Date32:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Date64:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Timestamp:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.String:
+		return func(i, j int) bool { return arr.Value(i) < arr.Value(j) }
+	case *array.Binary:
+		return func(i, j int) bool { return bytes.Compare(arr.Value(i), arr.Value(j)) < 0 }
+	}
+	return nil
+}
+
 func releaseBuffers(buffers []*memory.Buffer) {
 	for _, b := range buffers {
 		if b != nil {
@@ -658,3 +2363,50 @@ func releaseBuffers(buffers []*memory.Buffer) {
 		}
 	}
 }
+
+// validateBitmap checks that the validity bitmap buf, if any, holds the n
+// bits of an array with the given number of nulls.
+func validateBitmap(buf *memory.Buffer, n, nulls int64) error {
+	switch {
+	case n < 0:
+		return xerrors.Errorf("arrow/ipc: negative array length %d", n)
+	case nulls < 0 || nulls > n:
+		return xerrors.Errorf("arrow/ipc: invalid null count %d for array of length %d", nulls, n)
+	case buf != nil && int64(buf.Len()) < bitutil.CeilByte64(n)/8:
+		return xerrors.Errorf("arrow/ipc: validity bitmap too short (got=%d bytes, want=%d)", buf.Len(), bitutil.CeilByte64(n)/8)
+	}
+	return nil
+}
+
+// validateValues checks that buf holds n values of the given bit width.
+func validateValues(buf *memory.Buffer, n int64, width int) error {
+	if want := bitutil.CeilByte64(n*int64(width)) / 8; int64(buf.Len()) < want {
+		return xerrors.Errorf("arrow/ipc: values buffer too short (got=%d bytes, want=%d)", buf.Len(), want)
+	}
+	return nil
+}
+
+// validateOffsets checks that buf holds the n+1 offsets of an array of
+// length n, that they do not decrease and that they stay within [0, max].
+func validateOffsets(buf *memory.Buffer, n, max int64) error {
+	if n == 0 && buf.Len() == 0 {
+		return nil
+	}
+	if want := (n + 1) * int64(arrow.Int32SizeBytes); int64(buf.Len()) < want {
+		return xerrors.Errorf("arrow/ipc: offsets buffer too short (got=%d bytes, want=%d)", buf.Len(), want)
+	}
+
+	offsets := arrow.Int32Traits.CastFromBytes(buf.Bytes())[:n+1]
+	if offsets[0] < 0 {
+		return xerrors.Errorf("arrow/ipc: negative first offset %d", offsets[0])
+	}
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i] < offsets[i-1] {
+			return xerrors.Errorf("arrow/ipc: offsets decrease at index %d (%d < %d)", i, offsets[i], offsets[i-1])
+		}
+	}
+	if last := int64(offsets[n]); last > max {
+		return xerrors.Errorf("arrow/ipc: last offset %d out of bounds (max=%d)", last, max)
+	}
+	return nil
+}
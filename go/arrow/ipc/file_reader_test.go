@@ -0,0 +1,3262 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/klauspost/compress/huff0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+// writeTestFile writes the given records as an Arrow file and returns its content.
+func writeTestFile(t testing.TB, recs []arrow.Record, opts ...Option) []byte {
+	t.Helper()
+
+	f, err := ioutil.TempFile(t.TempDir(), "go-arrow-file-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	opts = append([]Option{WithSchema(recs[0].Schema())}, opts...)
+	w, err := NewFileWriter(f, opts...)
+	require.NoError(t, err)
+	for _, rec := range recs {
+		require.NoError(t, w.Write(rec))
+	}
+	require.NoError(t, w.Close())
+
+	raw, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	return raw
+}
+
+var testSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	{Name: "str", Type: arrow.BinaryTypes.String},
+}, nil)
+
+// makeTestRecords returns records following testSchema, with the given
+// number of rows each. Values are numbered consecutively across records.
+func makeTestRecords(t testing.TB, mem memory.Allocator, rows ...int) []arrow.Record {
+	t.Helper()
+
+	b := array.NewRecordBuilder(mem, testSchema)
+	defer b.Release()
+
+	var (
+		recs = make([]arrow.Record, len(rows))
+		n    = 0
+	)
+	for i, nrows := range rows {
+		for j := 0; j < nrows; j++ {
+			if n%3 == 2 {
+				b.Field(0).AppendNull()
+			} else {
+				b.Field(0).(*array.Int64Builder).Append(int64(n))
+			}
+			b.Field(1).(*array.StringBuilder).Append("s" + strconv.Itoa(n))
+			n++
+		}
+		recs[i] = b.NewRecord()
+	}
+	return recs
+}
+
+func releaseRecords(recs []arrow.Record) {
+	for _, rec := range recs {
+		rec.Release()
+	}
+}
+
+// rewriteFooter replaces the footer of the Arrow file raw with one declaring
+// the given metadata version and listing the record batch blocks returned
+// by fn. A nil fn keeps the original blocks.
+func rewriteFooter(t testing.TB, raw []byte, version MetadataVersion, fn func(blks []fileBlock) []fileBlock) []byte {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	defer r.Close()
+
+	blks := make([]fileBlock, r.footer.data.RecordBatchesLength())
+	for i := range blks {
+		var blk flatbuf.Block
+		require.True(t, r.footer.data.RecordBatches(&blk, i))
+		blks[i] = fileBlock{Offset: blk.Offset(), Meta: blk.MetaDataLength(), Body: blk.BodyLength()}
+	}
+	if fn != nil {
+		blks = fn(blks)
+	}
+
+	var (
+		b    = flatbuffers.NewBuilder(1024)
+		memo = newMemo()
+	)
+	schemaFB := schemaToFB(b, r.Schema(), &memo)
+	dictsFB := fileBlocksToFB(b, nil, flatbuf.FooterStartDictionariesVector)
+	recsFB := fileBlocksToFB(b, blks, flatbuf.FooterStartRecordBatchesVector)
+	flatbuf.FooterStart(b)
+	flatbuf.FooterAddVersion(b, flatbuf.MetadataVersion(version))
+	flatbuf.FooterAddSchema(b, schemaFB)
+	flatbuf.FooterAddDictionaries(b, dictsFB)
+	flatbuf.FooterAddRecordBatches(b, recsFB)
+	b.Finish(flatbuf.FooterEnd(b))
+	footer := b.FinishedBytes()
+
+	size := int64(binary.LittleEndian.Uint32(raw[len(raw)-len(Magic)-4:]))
+	start := int64(len(raw)) - size - int64(len(Magic)+4)
+
+	out := append([]byte{}, raw[:start]...)
+	out = append(out, footer...)
+	out = append(out, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(out[len(out)-4:], uint32(len(footer)))
+	return append(out, Magic...)
+}
+
+func TestFileReaderByteSwap(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i16", Type: arrow.PrimitiveTypes.Int16},
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int16Builder).AppendValues([]int16{1, 0x0102}, nil)
+	b.Field(1).(*array.Int32Builder).AppendValues([]int32{1, 0x01020304}, nil)
+	b.Field(2).(*array.Int64Builder).AppendValues([]int64{1, 0x0102030405060708}, nil)
+	b.Field(3).(*array.Float64Builder).AppendValues([]float64{1, -2.5}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	// read native data as if the host had the opposite endianness.
+	foreign := flatbuf.EndiannessBig
+	if nativeEndianness == flatbuf.EndiannessBig {
+		foreign = flatbuf.EndiannessLittle
+	}
+	host := withHostEndianness(foreign)
+
+	check := func(t *testing.T, got arrow.Record) {
+		t.Helper()
+		assert.Equal(t, []int16{0x0100, 0x0201}, got.Column(0).(*array.Int16).Int16Values())
+		assert.Equal(t, []int32{0x01000000, 0x04030201}, got.Column(1).(*array.Int32).Int32Values())
+		assert.Equal(t, []int64{0x0100000000000000, 0x0807060504030201}, got.Column(2).(*array.Int64).Int64Values())
+
+		f64 := got.Column(3).(*array.Float64).Float64Values()
+		assert.Equal(t, math.Float64frombits(0x000000000000f03f), f64[0])
+		assert.Equal(t, math.Float64frombits(0x00000000000004c0), f64[1])
+	}
+
+	t.Run("file", func(t *testing.T) {
+		raw := writeTestFile(t, []arrow.Record{rec})
+
+		_, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), host)
+		assert.Error(t, err)
+
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), host, WithByteSwap(true))
+		require.NoError(t, err)
+		defer r.Close()
+
+		got, err := r.Record(0)
+		require.NoError(t, err)
+		check(t, got)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, WithSchema(schema))
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Close())
+		raw := buf.Bytes()
+
+		_, err := NewReader(bytes.NewReader(raw), WithAllocator(mem), host)
+		assert.Error(t, err)
+
+		r, err := NewReader(bytes.NewReader(raw), WithAllocator(mem), host, WithByteSwap(true))
+		require.NoError(t, err)
+		defer r.Release()
+
+		require.True(t, r.Next(), "%v", r.Err())
+		check(t, r.Record())
+	})
+}
+
+func TestSwapWords(t *testing.T) {
+	buf := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	swapWords(buf, 4)
+	assert.Equal(t, []byte{4, 3, 2, 1, 8, 7, 6, 5}, buf)
+
+	buf = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	swapValues(arrow.FixedWidthTypes.MonthDayNanoInterval, buf)
+	assert.Equal(t, []byte{4, 3, 2, 1, 8, 7, 6, 5, 16, 15, 14, 13, 12, 11, 10, 9}, buf)
+}
+
+func TestFileReaderEmbedded(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5)
+	defer releaseRecords(recs)
+
+	raw := writeTestFile(t, recs)
+	head := bytes.Repeat([]byte("junk"), 5)
+	tail := bytes.Repeat([]byte("trailer"), 3)
+	buf := append(append(append([]byte{}, head...), raw...), tail...)
+
+	start, end := int64(len(head)), int64(len(head)+len(raw))
+	r, err := NewFileReader(bytes.NewReader(buf), WithAllocator(mem), WithEmbeddedFile(start, end))
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.Equal(t, len(recs), r.NumRecords())
+	for i := range recs {
+		rec, err := r.Record(i)
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+	}
+
+	_, err = NewFileReader(bytes.NewReader(buf), WithEmbeddedFile(start+1, end))
+	assert.Error(t, err)
+	_, err = NewFileReader(bytes.NewReader(buf), WithEmbeddedFile(start, end+1))
+	assert.Error(t, err)
+	_, err = NewFileReader(bytes.NewReader(buf), WithEmbeddedFile(end, start))
+	assert.Error(t, err)
+}
+
+// readAtRecorder records the ranges read through ReadAt.
+type readAtRecorder struct {
+	*bytes.Reader
+	mu    sync.Mutex
+	reads [][2]int64 // offset, length
+}
+
+func (r *readAtRecorder) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	r.reads = append(r.reads, [2]int64{off, int64(len(p))})
+	r.mu.Unlock()
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestReadSchemaOnly(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 10, 20)
+	defer releaseRecords(recs)
+
+	raw := writeTestFile(t, recs)
+	footerSize := int64(binary.LittleEndian.Uint32(raw[len(raw)-len(Magic)-4:]))
+	footerStart := int64(len(raw)) - footerSize - int64(len(Magic)+4)
+
+	r := &readAtRecorder{Reader: bytes.NewReader(raw)}
+	schema, err := ReadSchemaOnly(r)
+	require.NoError(t, err)
+	assert.True(t, schema.Equal(testSchema), "got=%v, want=%v", schema, testSchema)
+
+	require.NotEmpty(t, r.reads)
+	for _, rd := range r.reads {
+		assert.GreaterOrEqualf(t, rd[0], footerStart, "read of %d bytes at offset %d outside of footer", rd[1], rd[0])
+	}
+
+	_, err = ReadSchemaOnly(bytes.NewReader(raw[:len(raw)-1]))
+	assert.Error(t, err)
+
+	t.Run("dictionary placeholder", func(t *testing.T) {
+		want := arrow.Field{
+			Name: "item", Type: arrow.BinaryTypes.String, Nullable: true,
+			Metadata: arrow.NewMetadata(
+				[]string{DictionaryIDKeyName, DictionaryIndexTypeKeyName, DictionaryOrderedKeyName},
+				[]string{"0", "int32", "false"},
+			),
+		}
+		for _, outer := range []flatbuf.Type{flatbuf.TypeStruct_, flatbuf.TypeList} {
+			schema, err := ReadSchemaOnly(bytes.NewReader(nestedDictFooter(t, raw, outer)))
+			require.NoError(t, err, outer)
+			require.Len(t, schema.Fields(), 1)
+
+			nested := schema.Field(0).Type.(arrow.NestedType)
+			require.Len(t, nested.Fields(), 1)
+			assert.True(t, want.Equal(nested.Fields()[0]), "got=%v, want=%v", nested.Fields()[0], want)
+		}
+	})
+}
+
+// unknownType is a data type the array loader does not know about.
+type unknownType struct{}
+
+func (unknownType) ID() arrow.Type      { return arrow.Type(-1) }
+func (unknownType) Name() string        { return "unknown" }
+func (unknownType) Fingerprint() string { return "unknown" }
+func (unknownType) String() string      { return "unknown" }
+
+func TestRecordAtLoadErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 4)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, tc := range []struct {
+		name   string
+		fields []arrow.Field
+		want   string
+	}{
+		{
+			name: "unhandled type",
+			fields: []arrow.Field{
+				{Name: "i64", Type: arrow.PrimitiveTypes.Int64},
+				{Name: "bad", Type: unknownType{}},
+			},
+			want: `could not read record 0: arrow/ipc: could not load field "bad": array type ipc.unknownType not handled yet`,
+		},
+		{
+			name: "missing field node",
+			fields: []arrow.Field{
+				{Name: "i64", Type: arrow.PrimitiveTypes.Int64},
+				{Name: "s", Type: arrow.StructOf(
+					arrow.Field{Name: "extra", Type: arrow.PrimitiveTypes.Int32},
+				)},
+			},
+			want: `could not read record 0: arrow/ipc: record batch has 2 field nodes, schema requires 3`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			r.schema = arrow.NewSchema(tc.fields, nil)
+			rec, err := r.RecordAt(0)
+			assert.Nil(t, rec)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestFileReaderSortedBlocks(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 1, 2, 3, 4)
+	defer releaseRecords(recs)
+
+	var offsets []int64
+	raw := rewriteFooter(t, writeTestFile(t, recs), currentMetadataVersion, func(blks []fileBlock) []fileBlock {
+		for _, blk := range blks {
+			offsets = append(offsets, blk.Offset)
+		}
+		// list blocks in the footer in the order 2, 0, 3, 1.
+		return []fileBlock{blks[2], blks[0], blks[3], blks[1]}
+	})
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	rec, err := r.Record(0)
+	require.NoError(t, err)
+	assert.True(t, array.RecordEqual(recs[2], rec), "footer order should be used by default")
+	r.Close()
+
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithSortedBlocks())
+	require.NoError(t, err)
+	defer r.Close()
+
+	for i := range recs {
+		rec, err := r.Record(i)
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+
+		rec, err = r.RecordByOffset(offsets[i])
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records at offset %d differ", offsets[i])
+		rec.Release()
+	}
+
+	_, err = r.RecordByOffset(offsets[1] + 8)
+	assert.Error(t, err)
+}
+
+func TestFileReaderCompressedScratch(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 100, 200, 300)
+	defer releaseRecords(recs)
+
+	for _, opt := range []Option{WithLZ4(), WithZstd()} {
+		raw := writeTestFile(t, recs, opt)
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+		require.NoError(t, err)
+
+		// retain every record while reading the next ones, so that recycled
+		// scratch buffers would clobber them if they were aliased.
+		got := make([]arrow.Record, len(recs))
+		for i := range recs {
+			got[i], err = r.RecordAt(i)
+			require.NoError(t, err)
+		}
+		for i := range recs {
+			assert.Truef(t, array.RecordEqual(recs[i], got[i]), "records[%d] differ", i)
+		}
+		releaseRecords(got)
+		r.Close()
+	}
+}
+
+func BenchmarkRecordAtCompressed(b *testing.B) {
+	mem := memory.NewGoAllocator()
+	recs := makeTestRecords(b, mem, 10000)
+	defer releaseRecords(recs)
+
+	f, err := ioutil.TempFile(b.TempDir(), "go-arrow-file-")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+
+	w, err := NewFileWriter(f, WithSchema(testSchema), WithZstd())
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := w.Write(recs[0]); err != nil {
+		b.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		b.Fatal(err)
+	}
+
+	r, err := NewFileReader(f)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer r.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec, err := r.RecordAt(0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		rec.Release()
+	}
+}
+
+func TestFileReaderVersion(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, v := range []MetadataVersion{MetadataV4, MetadataV5} {
+		r, err := NewFileReader(bytes.NewReader(rewriteFooter(t, raw, v, nil)))
+		require.NoErrorf(t, err, "version %v", v)
+		assert.Equal(t, v, r.Version())
+		r.Close()
+	}
+
+	for _, v := range []MetadataVersion{MetadataV1, MetadataV2, MetadataV3, MetadataV5 + 1} {
+		buf := rewriteFooter(t, raw, v, nil)
+		_, err := NewFileReader(bytes.NewReader(buf))
+		require.Errorf(t, err, "version %v", v)
+		assert.Contains(t, err.Error(), "unsupported Arrow metadata version")
+
+		r, err := NewFileReader(bytes.NewReader(buf), WithAllowVersions(v))
+		require.NoErrorf(t, err, "version %v", v)
+		assert.Equal(t, v, r.Version())
+		r.Close()
+	}
+}
+
+func TestFileReaderCompressionVersion(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name    string
+		version MetadataVersion
+		opts    []Option
+		err     bool
+	}{
+		{"v4", MetadataV4, nil, false},
+		{"v5", MetadataV5, nil, false},
+		{"v4-lz4", MetadataV4, []Option{WithLZ4()}, true},
+		{"v5-lz4", MetadataV5, []Option{WithLZ4()}, false},
+		{"v4-zstd", MetadataV4, []Option{WithZstd()}, true},
+		{"v5-zstd", MetadataV5, []Option{WithZstd()}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := writeTestFile(t, recs, tc.opts...)
+			for i := range recs {
+				mutateRecordMessage(t, raw, i, func(msg *flatbuf.Message) {
+					require.True(t, msg.MutateVersion(flatbuf.MetadataVersion(tc.version)))
+				})
+			}
+			raw = rewriteFooter(t, raw, tc.version, nil)
+
+			checkErr := func(err error) {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "malformed record batch")
+				assert.Contains(t, err.Error(), "requires metadata version V5")
+			}
+
+			for _, mode := range []struct {
+				name string
+				opts []Option
+			}{
+				{"eager", nil},
+				{"lazy", []Option{WithLazyColumns()}},
+			} {
+				r, err := NewFileReader(bytes.NewReader(raw), append([]Option{WithAllocator(mem)}, mode.opts...)...)
+				require.NoError(t, err)
+				defer r.Close()
+
+				for i, want := range recs {
+					got, err := r.RecordAt(i)
+					if tc.err {
+						checkErr(err)
+						continue
+					}
+					require.NoErrorf(t, err, "%s: record %d", mode.name, i)
+					assert.Truef(t, array.RecordEqual(want, got), "%s: record %d", mode.name, i)
+					got.Release()
+				}
+			}
+
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			arr, err := r.ColumnReader(0).Next()
+			if tc.err {
+				checkErr(err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, array.ArrayEqual(recs[0].Column(0), arr))
+			arr.Release()
+		})
+	}
+}
+
+func TestFileReaderTable(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	tbl, err := r.Table(context.Background())
+	require.NoError(t, err)
+	defer tbl.Release()
+
+	var rows int64
+	for _, rec := range recs {
+		rows += rec.NumRows()
+	}
+	assert.Equal(t, rows, tbl.NumRows())
+	assert.True(t, tbl.Schema().Equal(testSchema))
+	for i, f := range testSchema.Fields() {
+		assert.True(t, arrow.TypeEqual(f.Type, tbl.Column(i).DataType()))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tbl, err = r.Table(ctx)
+	assert.Nil(t, tbl)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// buildZstdDict builds a zstd dictionary from the given content.
+// The literals table is trained on the content, while the sequence tables
+// use a flat distribution; this is enough for the encoder and decoder to
+// agree on a dictionary without needing a full dictionary trainer.
+func buildZstdDict(t *testing.T, id uint32, content []byte) []byte {
+	t.Helper()
+
+	var u32 [4]byte
+	dict := []byte{0x37, 0xa4, 0x30, 0xec}
+	binary.LittleEndian.PutUint32(u32[:], id)
+	dict = append(dict, u32[:]...)
+
+	// every byte value must be encodable with the literals table.
+	sample := append([]byte(nil), content...)
+	for i := 0; i < 256; i++ {
+		sample = append(sample, byte(i))
+	}
+	var huff huff0.Scratch
+	_, _, err := huff0.Compress1X(sample, &huff)
+	require.NoError(t, err)
+	dict = append(dict, huff.OutTable...)
+
+	// offsets, match lengths and literal lengths tables.
+	// offset codes only go up to 30, hence 31 symbols.
+	for i := 0; i < 3; i++ {
+		dict = appendFlatNCount(dict, 5, 31)
+	}
+
+	// repeat offsets.
+	for _, off := range []uint32{1, 4, 8} {
+		binary.LittleEndian.PutUint32(u32[:], off)
+		dict = append(dict, u32[:]...)
+	}
+
+	return append(dict, content...)
+}
+
+// appendFlatNCount appends the description of a FSE table of the given
+// accuracy over nsym symbols, as described in the "FSE Table Description"
+// section of the zstd format. The first symbol gets a normalized count
+// making up for the table size while the others all get a count of 1.
+func appendFlatNCount(dst []byte, tableLog uint, nsym int) []byte {
+	var (
+		tableSize = 1 << tableLog
+		remaining = tableSize + 1
+		threshold = tableSize
+		nbBits    = tableLog + 1
+		bits      = uint64(tableLog - 5)
+		nbits     = uint(4)
+	)
+	for i := 0; i < nsym; i++ {
+		norm := 1
+		if i == 0 {
+			norm = tableSize - nsym + 1
+		}
+		max := 2*threshold - 1 - remaining
+		remaining -= norm
+		count := norm + 1 // extra accuracy
+		if count >= threshold {
+			count += max
+		}
+		bits |= uint64(count) << nbits
+		nbits += nbBits
+		if count < max {
+			nbits--
+		}
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+		for nbits >= 8 {
+			dst = append(dst, byte(bits))
+			bits >>= 8
+			nbits -= 8
+		}
+	}
+	if nbits > 0 {
+		dst = append(dst, byte(bits))
+	}
+	return dst
+}
+
+func TestFileReaderZstdDictionary(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 10, 100)
+	defer releaseRecords(recs)
+
+	var content bytes.Buffer
+	for i := 0; i < 200; i++ {
+		content.WriteString("str-" + strconv.Itoa(i))
+	}
+	dict := buildZstdDict(t, 42, content.Bytes())
+
+	raw := writeTestFile(t, recs, WithZstd(), WithZstdDictionary(dict))
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithZstdDictionary(dict))
+	require.NoError(t, err)
+	defer r.Close()
+
+	for i := range recs {
+		rec, err := r.RecordAt(i)
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+		rec.Release()
+	}
+
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, err = r.RecordAt(0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "compressed with dictionary 42 but no dictionary was supplied")
+}
+
+func TestFileReaderRecordMemoryLimit(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 1000)
+	defer releaseRecords(recs)
+
+	for _, opt := range []Option{WithLZ4(), WithZstd(), WithCompressConcurrency(0)} {
+		raw := writeTestFile(t, recs, opt)
+
+		// trip the limit within the first column, then within the second one.
+		for _, limit := range []int64{4096, 10000} {
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithRecordMemoryLimit(limit))
+			require.NoError(t, err)
+
+			rec, err := r.RecordAt(0)
+			assert.Nil(t, rec)
+			assert.ErrorIs(t, err, errRecordMemoryLimit)
+			r.Close()
+		}
+
+		// the limit applies to the whole record when its columns are
+		// loaded lazily, each one fitting on its own, and is checked
+		// before any column is loaded.
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithRecordMemoryLimit(10000), WithLazyColumns())
+		require.NoError(t, err)
+
+		rec, err := r.RecordAt(0)
+		assert.Nil(t, rec)
+		assert.ErrorIs(t, err, errRecordMemoryLimit)
+		r.Close()
+
+		r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithRecordMemoryLimit(1<<20), WithLazyColumns())
+		require.NoError(t, err)
+
+		rec, err = r.RecordAt(0)
+		require.NoError(t, err)
+		assert.True(t, array.RecordEqual(recs[0], rec))
+		rec.Release()
+		r.Close()
+
+		r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithRecordMemoryLimit(1<<20))
+		require.NoError(t, err)
+
+		rec, err = r.RecordAt(0)
+		require.NoError(t, err)
+		assert.True(t, array.RecordEqual(recs[0], rec))
+		rec.Release()
+		r.Close()
+	}
+}
+
+func TestFileReaderMapKeysSorted(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	build := func(keys ...[]int32) arrow.Record {
+		bldr := array.NewMapBuilder(mem, arrow.PrimitiveTypes.Int32, arrow.BinaryTypes.String, true)
+		defer bldr.Release()
+
+		kb := bldr.KeyBuilder().(*array.Int32Builder)
+		ib := bldr.ItemBuilder().(*array.StringBuilder)
+		for _, ks := range keys {
+			bldr.Append(true)
+			for _, k := range ks {
+				kb.Append(k)
+				ib.Append(strconv.Itoa(int(k)))
+			}
+		}
+		bldr.AppendNull()
+
+		arr := bldr.NewArray()
+		defer arr.Release()
+
+		schema := arrow.NewSchema([]arrow.Field{{Name: "map", Type: arr.DataType(), Nullable: true}}, nil)
+		return array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+	}
+
+	for _, tc := range []struct {
+		name   string
+		keys   [][]int32
+		sorted bool
+	}{
+		{"sorted", [][]int32{{1, 2, 3}, {}, {-5, 0}}, true},
+		{"unsorted", [][]int32{{1, 2, 3}, {2, 1}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := build(tc.keys...)
+			defer rec.Release()
+			raw := writeTestFile(t, []arrow.Record{rec})
+
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			assert.True(t, r.Schema().Field(0).Type.(*arrow.MapType).KeysSorted)
+
+			got, err := r.RecordAt(0)
+			require.NoError(t, err)
+			assert.True(t, got.Column(0).(*array.Map).KeysSorted())
+			assert.True(t, array.RecordEqual(rec, got))
+			got.Release()
+
+			r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithStrictValidation())
+			require.NoError(t, err)
+			defer r.Close()
+
+			got, err = r.RecordAt(0)
+			if tc.sorted {
+				require.NoError(t, err)
+				got.Release()
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "keys of map 1 are not sorted")
+		})
+	}
+}
+
+// renameField replaces the flatbuffer string old by new, of the same length,
+// everywhere in raw.
+func renameField(t *testing.T, raw []byte, old, new string) []byte {
+	t.Helper()
+	require.Equal(t, len(old), len(new))
+
+	fbstring := func(s string) []byte {
+		b := make([]byte, 4, 4+len(s)+1)
+		binary.LittleEndian.PutUint32(b, uint32(len(s)))
+		return append(append(b, s...), 0)
+	}
+	require.True(t, bytes.Contains(raw, fbstring(old)), "%q not found", old)
+	return bytes.Replace(raw, fbstring(old), fbstring(new), -1)
+}
+
+func TestFileReaderMapFieldNames(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	dt := arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32)
+	arr := columnFromJSON(t, mem, dt, `[[{"key": "a", "value": 1}], [], null, [{"key": "b", "value": 2}, {"key": "c", "value": null}]]`)
+	defer arr.Release()
+	schema := arrow.NewSchema([]arrow.Field{{Name: "col", Type: dt, Nullable: true}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+	defer rec.Release()
+
+	// as written by producers naming the entries and their fields differently.
+	raw := writeTestFile(t, []arrow.Record{rec})
+	raw = renameField(t, raw, "entries", "my_ents")
+	raw = renameField(t, raw, "key", "src")
+	raw = renameField(t, raw, "value", "dests")
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	assert.True(t, arrow.TypeEqual(dt, got.Schema().Field(0).Type), "got=%v, want=%v", got.Schema().Field(0).Type, dt)
+	assert.True(t, array.RecordEqual(rec, got))
+}
+
+func TestFileReaderNumRows(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 5)
+	defer releaseRecords(recs)
+
+	for _, opt := range []Option{WithCompressConcurrency(0), WithZstd()} {
+		raw := writeTestFile(t, recs, opt)
+
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+		require.NoError(t, err)
+
+		var want int64
+		for i := 0; i < r.NumRecords(); i++ {
+			rec, err := r.RecordAt(i)
+			require.NoError(t, err)
+			want += rec.NumRows()
+			rec.Release()
+		}
+
+		for i := 0; i < 2; i++ {
+			got, err := r.NumRows()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+		r.Close()
+	}
+
+	raw := writeTestFile(t, recs)
+	rr := &readAtRecorder{Reader: bytes.NewReader(raw)}
+	r, err := NewFileReader(rr)
+	require.NoError(t, err)
+	defer r.Close()
+
+	rr.reads = nil
+	_, err = r.NumRows()
+	require.NoError(t, err)
+	assert.Len(t, rr.reads, len(recs), "only the metadata of each record should be read")
+
+	rr.reads = nil
+	_, err = r.NumRows()
+	require.NoError(t, err)
+	assert.Empty(t, rr.reads, "the number of rows should be cached")
+}
+
+// bufferSizePrefixes returns the uncompressed size prefix of each non-empty
+// buffer of the i-th record of a compressed file.
+func bufferSizePrefixes(t *testing.T, raw []byte, i int) []int64 {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	defer r.Close()
+
+	blk, err := r.block(i)
+	require.NoError(t, err)
+	msg, err := blk.NewMessage()
+	require.NoError(t, err)
+	defer msg.Release()
+
+	var (
+		md       flatbuf.RecordBatch
+		buf      flatbuf.Buffer
+		body     = msg.body.Bytes()
+		prefixes []int64
+	)
+	initFB(&md, msg.msg.Header)
+	for j := 0; j < md.BuffersLength(); j++ {
+		md.Buffers(&buf, j)
+		if buf.Length() == 0 {
+			continue
+		}
+		prefixes = append(prefixes, int64(binary.LittleEndian.Uint64(body[buf.Offset():])))
+	}
+	return prefixes
+}
+
+// makeCompressibilityRecord returns a record with a column of n constant
+// values, which compresses well, and a column of random bytes, which does not.
+func makeCompressibilityRecord(t *testing.T, mem memory.Allocator, n int) arrow.Record {
+	t.Helper()
+
+	var (
+		rnd   = rand.New(rand.NewSource(1))
+		noise = make([]byte, 64)
+	)
+	ib := array.NewInt64Builder(mem)
+	defer ib.Release()
+	bb := array.NewBinaryBuilder(mem, arrow.BinaryTypes.Binary)
+	defer bb.Release()
+	for i := 0; i < n; i++ {
+		ib.Append(42)
+		rnd.Read(noise)
+		bb.Append(noise)
+	}
+	cst := ib.NewArray()
+	defer cst.Release()
+	bin := bb.NewArray()
+	defer bin.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "constant", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "noise", Type: arrow.BinaryTypes.Binary},
+	}, nil)
+	return array.NewRecord(schema, []arrow.Array{cst, bin}, int64(n))
+}
+
+func TestFileWriterCompression(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	const n = 1024
+	rec := makeCompressibilityRecord(t, mem, n)
+	defer rec.Release()
+
+	for _, codec := range []CompressionType{CompressionLZ4Frame, CompressionZstd} {
+		t.Run(codec.String(), func(t *testing.T) {
+			raw := writeTestFile(t, []arrow.Record{rec}, WithCompression(codec))
+
+			// constant values, noise offsets, noise data.
+			prefixes := bufferSizePrefixes(t, raw, 0)
+			require.Len(t, prefixes, 3)
+			assert.Equal(t, int64(n*arrow.Int64SizeBytes), prefixes[0])
+			assert.Equal(t, int64(-1), prefixes[2], "incompressible buffer should be stored uncompressed")
+
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			got, err := r.RecordAt(0)
+			require.NoError(t, err)
+			defer got.Release()
+			assert.True(t, array.RecordEqual(rec, got))
+		})
+	}
+}
+
+func TestFileWriterCompressionThreshold(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	mixed := makeCompressibilityRecord(t, mem, 1024)
+	defer mixed.Release()
+	constant := array.NewRecord(arrow.NewSchema(mixed.Schema().Fields()[:1], nil), mixed.Columns()[:1], mixed.NumRows())
+	defer constant.Release()
+
+	for _, tc := range []struct {
+		name   string
+		opts   []Option
+		stored [2]bool // for the constant record, then the mixed one
+	}{
+		{"uncompressed", nil, [2]bool{false, false}},
+		{"default", []Option{WithZstd()}, [2]bool{false, true}},
+		{"always", []Option{WithZstd(), WithCompressionThreshold(0)}, [2]bool{true, true}},
+		{"never", []Option{WithZstd(), WithCompressionThreshold(math.Inf(1))}, [2]bool{false, false}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, rec := range []arrow.Record{constant, mixed} {
+				raw := writeTestFile(t, []arrow.Record{rec}, tc.opts...)
+				r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+				require.NoError(t, err)
+
+				stored, err := r.RecordIsStored(0)
+				require.NoError(t, err)
+				assert.Equal(t, tc.stored[i], stored)
+
+				got, err := r.RecordAt(0)
+				require.NoError(t, err)
+				assert.True(t, array.RecordEqual(rec, got))
+				got.Release()
+				r.Close()
+			}
+		})
+	}
+}
+
+func TestFileReaderRecordDecodedSize(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 100)
+	mixed := makeCompressibilityRecord(t, mem, 1024)
+	recs = append(recs, mixed)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"uncompressed", nil},
+		{"lz4", []Option{WithLZ4()}},
+		{"zstd", []Option{WithZstd()}},
+		{"stored", []Option{WithZstd(), WithCompressionThreshold(0)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// records of different schemas are written to separate files.
+			for _, recs := range [][]arrow.Record{recs[:3], recs[3:]} {
+				var decoded int64
+				raw := writeTestFile(t, recs, tc.opts...)
+				r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithBufferTraceHook(func(bt BufferTrace) {
+					decoded += bt.Size
+				}))
+				require.NoError(t, err)
+				defer r.Close()
+
+				for i := range recs {
+					stats := r.Stats()
+					size, err := r.RecordDecodedSize(i)
+					require.NoError(t, err)
+					assert.Equal(t, stats, r.Stats(), "estimating the size should not decode the record")
+
+					decoded = 0
+					rec, err := r.RecordAt(i)
+					require.NoError(t, err)
+					rec.Release()
+					assert.Equalf(t, decoded, size, "record %d", i)
+				}
+			}
+		})
+	}
+
+	raw := writeTestFile(t, recs[:3])
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+	for _, i := range []int{-1, 3} {
+		_, err := r.RecordDecodedSize(i)
+		assert.Errorf(t, err, "record %d", i)
+	}
+}
+
+// blockingReader is a reader whose context-aware reads block until their
+// context is done, once block is set.
+type blockingReader struct {
+	*bytes.Reader
+	block bool
+}
+
+func (r *blockingReader) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	if r.block {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	return r.ReadAt(p, off)
+}
+
+func TestRecordAtContext(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	br := &blockingReader{Reader: bytes.NewReader(raw)}
+	r, err := NewFileReader(br, WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err := r.RecordAtContext(context.Background(), 0)
+	require.NoError(t, err)
+	rec.Release()
+
+	br.block = true
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	rec, err = r.RecordAtContext(ctx, 0)
+	assert.Nil(t, rec)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// readers without ReadAtContext only check the context before reading.
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = r.RecordAtContext(ctx, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// mutateRecordMeta calls fn on the metadata of the i-th record of raw,
+// allowing tests to corrupt it in place.
+func mutateRecordMeta(t *testing.T, raw []byte, i int, fn func(md *flatbuf.RecordBatch)) {
+	t.Helper()
+
+	mutateRecordMessage(t, raw, i, func(msg *flatbuf.Message) {
+		var md flatbuf.RecordBatch
+		initFB(&md, msg.Header)
+		fn(&md)
+	})
+}
+
+// mutateRecordMessage calls fn on the message of the i-th record of raw,
+// allowing tests to corrupt it in place.
+func mutateRecordMessage(t *testing.T, raw []byte, i int, fn func(msg *flatbuf.Message)) {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	defer r.Close()
+
+	blk, err := r.block(i)
+	require.NoError(t, err)
+
+	meta := raw[blk.Offset : blk.Offset+int64(blk.Meta)]
+	if binary.LittleEndian.Uint32(meta) == kIPCContToken {
+		meta = meta[8:]
+	} else {
+		meta = meta[4:]
+	}
+
+	fn(flatbuf.GetRootAsMessage(meta, 0))
+}
+
+func TestFixedSizeListStrictValidation(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	bldr := array.NewFixedSizeListBuilder(mem, 3, arrow.PrimitiveTypes.Int32)
+	defer bldr.Release()
+	vb := bldr.ValueBuilder().(*array.Int32Builder)
+	for i := 0; i < 4; i++ {
+		bldr.Append(true)
+		vb.AppendValues([]int32{int32(i), int32(i + 1), int32(i + 2)}, nil)
+	}
+	arr := bldr.NewArray()
+	defer arr.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "fsl", Type: arr.DataType()}}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+	defer rec.Release()
+
+	raw := writeTestFile(t, []arrow.Record{rec})
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithStrictValidation())
+	require.NoError(t, err)
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	assert.True(t, array.RecordEqual(rec, got))
+	got.Release()
+	r.Close()
+
+	// shorten the child array: node 0 is the list, node 1 its child.
+	mutateRecordMeta(t, raw, 0, func(md *flatbuf.RecordBatch) {
+		var node flatbuf.FieldNode
+		require.True(t, md.Nodes(&node, 1))
+		require.True(t, node.MutateLength(node.Length()-1))
+	})
+
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithStrictValidation())
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, err = r.RecordAt(0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fixed-size list child has 11 elements, want 12")
+}
+
+// readerAtOnly only implements io.ReaderAt.
+type readerAtOnly struct {
+	r *bytes.Reader
+}
+
+func (r readerAtOnly) ReadAt(p []byte, off int64) (int, error) { return r.r.ReadAt(p, off) }
+
+func TestNewFileReaderSize(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	var ra io.ReaderAt = readerAtOnly{bytes.NewReader(raw)}
+	_, ok := ra.(io.Seeker)
+	require.False(t, ok)
+
+	r, err := NewFileReaderSize(ra, int64(len(raw)), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.Equal(t, len(recs), r.NumRecords())
+	for i := range recs {
+		rec, err := r.RecordAt(i)
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+		rec.Release()
+	}
+
+	// context-aware readers keep being canceled through their context.
+	br := &blockingReader{Reader: bytes.NewReader(raw)}
+	r, err = NewFileReaderSize(readerAtContextOnly{br}, int64(len(raw)), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	br.block = true
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = r.RecordAtContext(ctx, 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	for _, size := range []int64{0, -1} {
+		_, err = NewFileReaderSize(ra, size)
+		assert.Error(t, err)
+	}
+	_, err = NewFileReaderSize(ra, int64(len(raw))-1)
+	assert.Error(t, err)
+}
+
+func TestNewFileReaderFromBytes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	want, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer want.Close()
+
+	r, err := NewFileReaderFromBytes(raw, WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.True(t, want.Schema().Equal(r.Schema()))
+	assert.Equal(t, want.Version(), r.Version())
+	require.Equal(t, want.NumRecords(), r.NumRecords())
+	for i := 0; i < r.NumRecords(); i++ {
+		exp, err := want.RecordAt(i)
+		require.NoError(t, err)
+		rec, err := r.RecordAt(i)
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(exp, rec), "records[%d] differ", i)
+		exp.Release()
+		rec.Release()
+	}
+
+	// the file may be followed by other data.
+	padded := append(append([]byte(nil), raw...), "trailer"...)
+	r, err = NewFileReaderFromBytes(padded, WithAllocator(mem), WithFooterOffset(int64(len(raw))))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, len(recs), r.NumRecords())
+
+	for _, data := range [][]byte{nil, raw[:len(raw)-1], padded} {
+		_, err = NewFileReaderFromBytes(data, WithAllocator(mem))
+		assert.Error(t, err)
+	}
+}
+
+func TestFileReaderRecordOutlivesReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name  string
+		write []Option
+		read  []Option
+	}{
+		{"default", nil, nil},
+		{"zstd", []Option{WithZstd()}, nil},
+		{"lazy", nil, []Option{WithLazyColumns()}},
+		{"arena", nil, []Option{WithPerRecordArena()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := writeTestFile(t, recs, tc.write...)
+			r, err := NewFileReaderFromBytes(raw, append([]Option{WithAllocator(mem)}, tc.read...)...)
+			require.NoError(t, err)
+
+			got := make([]arrow.Record, len(recs))
+			for i := range recs {
+				got[i], err = r.RecordAt(i)
+				require.NoError(t, err)
+			}
+			defer releaseRecords(got)
+
+			// closing the reader and clobbering its source, as unmapping
+			// a memory-mapped file would, leaves the records intact.
+			require.NoError(t, r.Close())
+			for i := range raw {
+				raw[i] = 0xff
+			}
+			for i := range recs {
+				assert.Truef(t, array.RecordEqual(recs[i], got[i]), "records[%d] differ", i)
+			}
+		})
+	}
+}
+
+func TestFileReaderAllocatorResetFunc(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, n := range []int{1, 2} {
+		alloc := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		resets := 0
+		r, err := NewFileReaderFromBytes(raw, WithAllocator(alloc), WithReadAhead(2), WithAllocatorResetFunc(func() {
+			resets++
+			assert.Zero(t, alloc.CurrentAlloc(), "reset before the buffers of the reader are released")
+		}))
+		require.NoError(t, err)
+
+		// the reader holds the current record, and those read ahead,
+		// until it is closed.
+		_, err = r.Read()
+		require.NoError(t, err)
+		require.NotZero(t, alloc.CurrentAlloc())
+
+		rec, err := r.RecordAt(1)
+		require.NoError(t, err)
+		rec.Release()
+		assert.Zero(t, resets)
+
+		for i := 0; i < n; i++ {
+			require.NoError(t, r.Close())
+		}
+		assert.Equal(t, 1, resets, "%d calls to Close", n)
+	}
+}
+
+// legacyFraming rewrites the message metadata buf, framed with a
+// continuation marker, with the pre-0.15 framing, whose length prefix has no
+// continuation marker, in the same space.
+func legacyFraming(t testing.TB, buf []byte) {
+	t.Helper()
+
+	require.Equal(t, kIPCContToken, binary.LittleEndian.Uint32(buf))
+	size := binary.LittleEndian.Uint32(buf[4:])
+	copy(buf[4:], buf[8:8+size])
+	binary.LittleEndian.PutUint32(buf, size+4)
+	copy(buf[4+size:], make([]byte, 4))
+}
+
+func TestFileReaderMessageFraming(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5, 7)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReaderFromBytes(raw)
+	require.NoError(t, err)
+	blks := make([]fileBlock, r.NumRecords())
+	for i := range blks {
+		blks[i], err = r.block(i)
+		require.NoError(t, err)
+	}
+	r.Close()
+
+	for _, tc := range []struct {
+		name    string
+		records []int // records rewritten with the legacy framing
+	}{
+		{"current", nil},
+		{"legacy", []int{0, 1, 2}},
+		{"mixed", []int{1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := append([]byte(nil), raw...)
+			for _, i := range tc.records {
+				legacyFraming(t, data[blks[i].Offset:blks[i].Offset+int64(blks[i].Meta)])
+			}
+
+			r, err := NewFileReaderFromBytes(data, WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			require.NoError(t, r.VerifyBlocks())
+			n, err := r.NumRows()
+			require.NoError(t, err)
+			assert.EqualValues(t, 15, n)
+			for i := range recs {
+				rec, err := r.RecordAt(i)
+				require.NoError(t, err)
+				assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+				rec.Release()
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(buf []byte)
+	}{
+		{"zero", func(buf []byte) { copy(buf, make([]byte, 8)) }},
+		{"overflow", func(buf []byte) { binary.LittleEndian.PutUint32(buf[4:], uint32(len(buf))) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := append([]byte(nil), raw...)
+			tc.mutate(data[blks[1].Offset : blks[1].Offset+int64(blks[1].Meta)])
+
+			r, err := NewFileReaderFromBytes(data, WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			_, err = r.RecordAt(1)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid message metadata length")
+		})
+	}
+}
+
+// TestFileReaderLegacyFormat reads a file whose messages, including the
+// schema at its start, all use the pre-0.15 framing, as written by Arrow
+// releases prior to 0.15 or with the legacy IPC format flag.
+func TestFileReaderLegacyFormat(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5, 7)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReaderFromBytes(raw)
+	require.NoError(t, err)
+	data := append([]byte(nil), raw...)
+	legacyFraming(t, data[paddedLength(int64(len(Magic)), kArrowIPCAlignment):])
+	for i := 0; i < r.NumRecords(); i++ {
+		blk, err := r.block(i)
+		require.NoError(t, err)
+		legacyFraming(t, data[blk.Offset:blk.Offset+int64(blk.Meta)])
+	}
+	r.Close()
+
+	r, err = NewFileReaderFromBytes(data, WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.NoError(t, r.VerifyBlocks())
+	assert.True(t, r.Schema().Equal(testSchema))
+	for i := range recs {
+		rec, err := r.RecordAt(i)
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+		rec.Release()
+	}
+
+	// the messages are forwarded as is, and read back by the stream reader.
+	var stream bytes.Buffer
+	schema, err := r.SchemaBytes()
+	require.NoError(t, err)
+	assert.NotEqual(t, kIPCContToken, binary.LittleEndian.Uint32(schema))
+	stream.Write(schema)
+	for i := range recs {
+		msg, err := r.RecordRawBytes(i)
+		require.NoError(t, err)
+		assert.NotEqual(t, kIPCContToken, binary.LittleEndian.Uint32(msg))
+		stream.Write(msg)
+	}
+	stream.Write(make([]byte, 4))
+
+	sr, err := NewReader(&stream, WithAllocator(mem))
+	require.NoError(t, err)
+	defer sr.Release()
+	for i := range recs {
+		require.True(t, sr.Next(), "record %d: %v", i, sr.Err())
+		assert.Truef(t, array.RecordEqual(recs[i], sr.Record()), "records[%d] differ", i)
+	}
+	assert.False(t, sr.Next())
+	assert.NoError(t, sr.Err())
+}
+
+// footerSize returns a mutation setting the footer length of an Arrow file.
+func footerSize(size uint32) func([]byte) []byte {
+	return func(data []byte) []byte {
+		binary.LittleEndian.PutUint32(data[len(data)-len(Magic)-4:], size)
+		return data
+	}
+}
+
+func TestFileReaderBufferAllocatorSelector(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "st", Type: arrow.StructOf(
+			arrow.Field{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+		), Nullable: true},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 0, 3}, []bool{true, false, true})
+	sb := b.Field(1).(*array.StructBuilder)
+	sb.AppendValues([]bool{true, true, false})
+	sb.FieldBuilder(0).(*array.StringBuilder).AppendValues([]string{"a", "", "c"}, []bool{true, false, true})
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"uncompressed", nil},
+		{"zstd", []Option{WithZstd()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := writeTestFile(t, []arrow.Record{rec}, tc.opts...)
+
+			var (
+				validity = memory.NewCheckedAllocator(memory.NewGoAllocator())
+				seen     = make(map[string][]BufferRole)
+			)
+			defer validity.AssertSize(t, 0)
+
+			r, err := NewFileReaderFromBytes(raw, WithAllocator(mem), WithBufferAllocatorSelector(func(role BufferRole, field arrow.Field) memory.Allocator {
+				seen[field.Name] = append(seen[field.Name], role)
+				if role == BufferValidity {
+					return validity
+				}
+				return nil
+			}))
+			require.NoError(t, err)
+			defer r.Close()
+
+			got, err := r.RecordAt(0)
+			require.NoError(t, err)
+			assert.True(t, array.RecordEqual(rec, got))
+
+			assert.Equal(t, map[string][]BufferRole{
+				"i64": {BufferValidity, BufferData},
+				"st":  {BufferValidity},
+				"str": {BufferValidity, BufferOffsets, BufferData},
+			}, seen)
+
+			// the 3 validity bitmaps come from the selected allocator.
+			assert.Equal(t, 3*64, validity.CurrentAlloc())
+			assert.NotZero(t, mem.CurrentAlloc())
+			got.Release()
+		})
+	}
+}
+
+// readerAtContextOnly only implements io.ReaderAt and ReaderAtContext.
+type readerAtContextOnly struct {
+	r *blockingReader
+}
+
+func (r readerAtContextOnly) ReadAt(p []byte, off int64) (int, error) { return r.r.ReadAt(p, off) }
+
+func (r readerAtContextOnly) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	return r.r.ReadAtContext(ctx, p, off)
+}
+
+// passthroughDecompressor is a decompressor for data stored as-is.
+type passthroughDecompressor struct {
+	io.Reader
+	resets *int
+}
+
+func (d *passthroughDecompressor) Reset(r io.Reader) { d.Reader = r; *d.resets++ }
+func (d *passthroughDecompressor) Close()            {}
+
+func TestDecompressorRegistry(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 10)
+	defer releaseRecords(recs)
+
+	// store every buffer as-is, then flag them with their actual size and
+	// declare them compressed with a custom codec.
+	const passthrough = CompressionType(7)
+	raw := writeTestFile(t, recs, WithZstd(), WithCompressionThreshold(0))
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	blk, err := r.block(0)
+	require.NoError(t, err)
+	r.Close()
+
+	body := raw[blk.Offset+int64(blk.Meta):]
+	mutateRecordMeta(t, raw, 0, func(md *flatbuf.RecordBatch) {
+		require.True(t, md.Compression(nil).MutateCodec(flatbuf.CompressionType(passthrough)))
+		var buf flatbuf.Buffer
+		for i := 0; i < md.BuffersLength(); i++ {
+			md.Buffers(&buf, i)
+			if buf.Length() == 0 {
+				continue
+			}
+			prefix := body[buf.Offset():]
+			require.Equal(t, int64(-1), int64(binary.LittleEndian.Uint64(prefix)))
+			binary.LittleEndian.PutUint64(prefix, uint64(buf.Length()-int64(arrow.Int64SizeBytes)))
+		}
+	})
+
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	_, err = r.RecordAt(0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported compression codec CompressionType(7)")
+	r.Close()
+
+	var resets int
+	reg := NewDecompressorRegistry()
+	reg.Register(passthrough, func(r io.Reader) Decompressor {
+		return &passthroughDecompressor{Reader: r, resets: &resets}
+	})
+
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithDecompressorRegistry(reg))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err := r.RecordAt(0)
+	require.NoError(t, err)
+	assert.True(t, array.RecordEqual(recs[0], rec))
+	assert.NotZero(t, resets)
+	rec.Release()
+
+	RegisterDecompressor(passthrough, func(r io.Reader) Decompressor {
+		return &passthroughDecompressor{Reader: r, resets: &resets}
+	})
+	t.Cleanup(func() { UnregisterDecompressor(passthrough) })
+
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err = r.RecordAt(0)
+	require.NoError(t, err)
+	assert.True(t, array.RecordEqual(recs[0], rec))
+	rec.Release()
+}
+
+func TestFileReaderRowAt(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	sizes := []int{3, 0, 7, 1, 0, 5}
+	recs := makeTestRecords(t, mem, sizes...)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	loc, err := r.RowLocator()
+	require.NoError(t, err)
+	assert.Equal(t, int64(16), loc.NumRows())
+
+	again, err := r.RowLocator()
+	require.NoError(t, err)
+	assert.Same(t, loc, again)
+
+	var n int64
+	for i, size := range sizes {
+		for row := 0; row < size; row++ {
+			irec, irow, err := loc.Locate(n)
+			require.NoError(t, err)
+			assert.Equal(t, i, irec, "row %d", n)
+			assert.Equal(t, row, irow, "row %d", n)
+
+			rec, irow, err := r.RowAt(n)
+			require.NoError(t, err)
+			assert.Equal(t, row, irow)
+			assert.True(t, array.RecordEqual(recs[i], rec), "row %d", n)
+			rec.Release()
+			n++
+		}
+	}
+
+	for _, n := range []int64{-1, loc.NumRows(), loc.NumRows() + 1} {
+		_, _, err = loc.Locate(n)
+		assert.Error(t, err)
+		rec, _, err := r.RowAt(n)
+		assert.Nil(t, rec)
+		assert.Error(t, err)
+	}
+}
+
+func TestRecordAtShortFieldNodes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 4, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	// drop the last field node of the second record.
+	mutateRecordMeta(t, raw, 1, func(md *flatbuf.RecordBatch) {
+		tbl := md.Table()
+		pos := tbl.Vector(flatbuffers.UOffsetT(tbl.Offset(6))) - flatbuffers.SizeUOffsetT
+		n := binary.LittleEndian.Uint32(tbl.Bytes[pos:])
+		binary.LittleEndian.PutUint32(tbl.Bytes[pos:], n-1)
+	})
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err := r.RecordAt(0)
+	require.NoError(t, err)
+	rec.Release()
+
+	rec, err = r.RecordAt(1)
+	assert.Nil(t, rec)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "record batch has 1 field nodes, schema requires 2")
+}
+
+func TestNumFieldNodes(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "list", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+		{Name: "fsl", Type: arrow.FixedSizeListOf(2, arrow.ListOf(arrow.PrimitiveTypes.Int8))},
+		{Name: "struct", Type: arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+			arrow.Field{Name: "b", Type: arrow.StructOf()},
+		)},
+		{Name: "map", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64)},
+		{Name: "null", Type: arrow.Null},
+	}, nil)
+
+	// 1 + 2 + 3 + 3 + 4 + 1
+	assert.Equal(t, 14, numFieldNodes(schema))
+}
+
+func TestFileReaderRecordRange(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 1, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs, WithCompression(CompressionZstd))
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	for _, rng := range [][2]int{{0, 5}, {1, 4}, {2, 3}, {0, 2}} {
+		start, end := rng[0], rng[1]
+		t.Run(fmt.Sprintf("%d-%d", start, end), func(t *testing.T) {
+			cols := make([]arrow.Array, len(testSchema.Fields()))
+			rows := int64(0)
+			for i := range cols {
+				arrs := make([]arrow.Array, 0, end-start)
+				for _, rec := range recs[start:end] {
+					arrs = append(arrs, rec.Column(i))
+				}
+				cols[i], err = array.Concatenate(arrs, mem)
+				require.NoError(t, err)
+				defer cols[i].Release()
+			}
+			for _, rec := range recs[start:end] {
+				rows += rec.NumRows()
+			}
+			want := array.NewRecord(testSchema, cols, rows)
+			defer want.Release()
+
+			got, err := r.RecordRange(start, end)
+			require.NoError(t, err)
+			defer got.Release()
+
+			assert.Equal(t, rows, got.NumRows())
+			assert.True(t, array.RecordEqual(want, got))
+		})
+	}
+
+	for _, rng := range [][2]int{{-1, 2}, {0, 6}, {3, 3}, {4, 2}} {
+		rec, err := r.RecordRange(rng[0], rng[1])
+		assert.Nil(t, rec)
+		assert.Error(t, err, "range [%d, %d)", rng[0], rng[1])
+	}
+}
+
+func TestFileReaderTailRecords(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 1, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, n := range []int{0, 1, 2, 5, 10} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			rr := &readAtRecorder{Reader: bytes.NewReader(raw)}
+			r, err := NewFileReader(rr, WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			start := len(recs) - n
+			if start < 0 {
+				start = 0
+			}
+			blks := r.RecordBlocks()
+			rr.reads = nil
+
+			got, err := r.TailRecords(n)
+			require.NoError(t, err)
+			defer releaseRecords(got)
+
+			require.Len(t, got, len(recs)-start)
+			for i, rec := range got {
+				assert.True(t, array.RecordEqual(recs[start+i], rec), "record %d", start+i)
+			}
+
+			for _, rd := range rr.reads {
+				assert.GreaterOrEqualf(t, rd[0], blks[start].Offset,
+					"read of %d bytes at offset %d before record %d", rd[1], rd[0], start)
+			}
+		})
+	}
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+	got, err := r.TailRecords(-1)
+	assert.Nil(t, got)
+	assert.Error(t, err)
+}
+
+func TestFileReaderFeatherCompat(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 4, 6)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs, WithCompression(CompressionZstd))
+
+	// shift all record batches by 4 bytes, as done by some feather writers.
+	const shift = 4
+	unaligned, offset := shiftRecords(t, raw, shift)
+
+	r, err := NewFileReader(bytes.NewReader(unaligned), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err := r.RecordAt(0)
+	assert.Nil(t, rec)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("invalid file offset=%d for record 0", offset+shift))
+
+	r, err = NewFileReader(bytes.NewReader(unaligned), WithAllocator(mem), WithFeatherCompat())
+	require.NoError(t, err)
+	defer r.Close()
+
+	for i := range recs {
+		rec, err := r.RecordAt(i)
+		require.NoError(t, err)
+		assert.True(t, array.RecordEqual(recs[i], rec))
+		rec.Release()
+	}
+
+	// feather writers do not misalign blocks by less than 4 bytes.
+	unaligned, offset = shiftRecords(t, raw, 2)
+	r, err = NewFileReader(bytes.NewReader(unaligned), WithAllocator(mem), WithFeatherCompat())
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err = r.RecordAt(0)
+	assert.Nil(t, rec)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("invalid file offset=%d for record 0", offset+2))
+}
+
+// legacyCompressedFile rewrites the compressed Arrow file raw as written by
+// Arrow 0.17 for Feather V2: the record batches carry no body compression
+// metadata, their codec being declared by the custom metadata of their V4
+// message.
+func legacyCompressedFile(t *testing.T, raw []byte, codec string) []byte {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	defer r.Close()
+
+	blk, err := r.block(0)
+	require.NoError(t, err)
+	out := append([]byte{}, raw[:blk.Offset]...)
+	blks := make([]fileBlock, r.NumRecords())
+	for i := range blks {
+		blk, md, err := r.recordMeta(i)
+		require.NoError(t, err)
+		require.NotNil(t, md.Compression(nil))
+
+		var (
+			node    flatbuf.FieldNode
+			buf     flatbuf.Buffer
+			fields  = make([]fieldMetadata, md.NodesLength())
+			buffers = make([]bufferMetadata, md.BuffersLength())
+		)
+		for j := range fields {
+			md.Nodes(&node, j)
+			fields[j] = fieldMetadata{Len: node.Length(), Nulls: node.NullCount()}
+		}
+		for j := range buffers {
+			md.Buffers(&buf, j)
+			buffers[j] = bufferMetadata{Offset: buf.Offset(), Len: buf.Length()}
+		}
+
+		b := flatbuffers.NewBuilder(0)
+		recFB := recordToFB(b, md.Length(), blk.Body, fields, buffers, -1)
+		metaFB := metadataToFB(b, arrow.NewMetadata([]string{legacyCompressionKey}, []string{codec}), flatbuf.MessageStartCustomMetadataVector)
+		flatbuf.MessageStart(b)
+		flatbuf.MessageAddVersion(b, flatbuf.MetadataVersionV4)
+		flatbuf.MessageAddHeaderType(b, flatbuf.MessageHeaderRecordBatch)
+		flatbuf.MessageAddHeader(b, recFB)
+		flatbuf.MessageAddBodyLength(b, blk.Body)
+		flatbuf.MessageAddCustomMetadata(b, metaFB)
+		b.Finish(flatbuf.MessageEnd(b))
+		msg := b.FinishedBytes()
+
+		size := paddedLength(int64(len(msg))+8, kArrowIPCAlignment)
+		meta := make([]byte, size)
+		binary.LittleEndian.PutUint32(meta, kIPCContToken)
+		binary.LittleEndian.PutUint32(meta[4:], uint32(size-8))
+		copy(meta[8:], msg)
+
+		blks[i] = fileBlock{Offset: int64(len(out)), Meta: int32(size), Body: blk.Body}
+		out = append(out, meta...)
+		out = append(out, raw[blk.Offset+int64(blk.Meta):blk.Offset+int64(blk.Meta)+blk.Body]...)
+	}
+
+	w := bytes.NewBuffer(out)
+	require.NoError(t, writeFileFooter(r.Schema(), nil, blks, w))
+	size := w.Len() - len(out)
+	require.NoError(t, binary.Write(w, binary.LittleEndian, uint32(size)))
+	w.Write(Magic)
+	return w.Bytes()
+}
+
+func TestFileReaderFeatherLegacyCompression(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 500, 700)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		codec CompressionType
+		name  string
+	}{
+		{CompressionLZ4Frame, "lz4"},
+		{CompressionZstd, "zstd"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			orig := writeTestFile(t, recs, WithCompression(tc.codec))
+			raw := legacyCompressedFile(t, orig, tc.name)
+			ref, err := NewFileReader(bytes.NewReader(orig))
+			require.NoError(t, err)
+			defer ref.Close()
+
+			// without feather compatibility, the compressed buffers are
+			// not recognized as such.
+			r, err := NewFileReader(bytes.NewReader(raw))
+			require.NoError(t, err)
+			defer r.Close()
+			rec, err := r.RecordAt(0)
+			if err == nil {
+				assert.False(t, array.RecordEqual(recs[0], rec))
+				rec.Release()
+			}
+
+			for _, opt := range []Option{WithFeatherCompat(), WithLazyColumns(), WithParallelDecompress(2)} {
+				opts := []Option{WithAllocator(mem), WithFeatherCompat(), opt}
+				r, err := NewFileReader(bytes.NewReader(raw), opts...)
+				require.NoError(t, err)
+				defer r.Close()
+
+				for i, want := range recs {
+					got, err := r.RecordAt(i)
+					require.NoError(t, err)
+					assert.Truef(t, array.RecordEqual(want, got), "record %d", i)
+					got.Release()
+
+					stored, err := r.RecordIsStored(i)
+					require.NoError(t, err)
+					wantStored, err := ref.RecordIsStored(i)
+					require.NoError(t, err)
+					assert.Equal(t, wantStored, stored)
+					size, err := r.RecordDecodedSize(i)
+					require.NoError(t, err)
+					wantSize, err := ref.RecordDecodedSize(i)
+					require.NoError(t, err)
+					assert.Equal(t, wantSize, size)
+				}
+
+				_, _, err = r.RecordBodyBytes(0)
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	raw := legacyCompressedFile(t, writeTestFile(t, recs, WithCompression(CompressionZstd)), "snappy")
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithFeatherCompat())
+	require.NoError(t, err)
+	defer r.Close()
+	rec, err := r.RecordAt(0)
+	assert.Nil(t, rec)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported legacy compression codec "snappy"`)
+}
+
+// shiftRecords inserts shift bytes of padding before the first record batch
+// of the Arrow file raw, moving all record batches. It returns the shifted
+// file and the original offset of the first record batch.
+func shiftRecords(t *testing.T, raw []byte, shift int64) ([]byte, int64) {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	blk, err := r.block(0)
+	require.NoError(t, err)
+	version := r.Version()
+	r.Close()
+
+	out := append([]byte{}, raw[:blk.Offset]...)
+	out = append(out, make([]byte, shift)...)
+	out = append(out, raw[blk.Offset:]...)
+	out = rewriteFooter(t, out, version, func(blks []fileBlock) []fileBlock {
+		for i := range blks {
+			blks[i].Offset += shift
+		}
+		return blks
+	})
+	return out, blk.Offset
+}
+
+func TestFileReaderRelaxedAlignment(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 4, 6, 3)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name  string
+		opts  []Option
+		shift int64
+		ok    bool
+	}{
+		{"default-4", nil, 4, false},
+		{"relaxed-4", []Option{WithRelaxedAlignment()}, 4, true},
+		{"relaxed-12", []Option{WithRelaxedAlignment()}, 12, true},
+		{"relaxed-2", []Option{WithRelaxedAlignment()}, 2, false},
+		{"relaxed-zstd-4", []Option{WithRelaxedAlignment(), WithCompression(CompressionZstd)}, 4, true},
+		{"feather-4", []Option{WithFeatherCompat()}, 4, true},
+		{"relaxed-feather-2", []Option{WithRelaxedAlignment(), WithFeatherCompat()}, 2, false},
+		{"trusted-2", []Option{WithTrustedInput()}, 2, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, offset := shiftRecords(t, writeTestFile(t, recs, tc.opts...), tc.shift)
+
+			r, err := NewFileReader(bytes.NewReader(raw), append([]Option{WithAllocator(mem)}, tc.opts...)...)
+			require.NoError(t, err)
+			defer r.Close()
+
+			for i, want := range recs {
+				got, err := r.RecordAt(i)
+				if !tc.ok {
+					assert.Nil(t, got)
+					require.Error(t, err)
+					if i == 0 {
+						assert.Contains(t, err.Error(), fmt.Sprintf("invalid file offset=%d for record 0", offset+tc.shift))
+					}
+					continue
+				}
+				require.NoError(t, err)
+				assert.Truef(t, array.RecordEqual(want, got), "record %d", i)
+				got.Release()
+			}
+		})
+	}
+}
+
+func TestBufferTraceHook(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	// the i64 column holds a null, the str column does not: the validity
+	// bitmap of the latter (buffer 2) is never read.
+	recs := makeTestRecords(t, mem, 4)
+	defer releaseRecords(recs)
+
+	for _, codec := range []CompressionType{-1, CompressionZstd} {
+		t.Run(codec.String(), func(t *testing.T) {
+			raw := writeTestFile(t, recs, WithCompression(codec))
+
+			var traces []BufferTrace
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithBufferTraceHook(func(bt BufferTrace) {
+				traces = append(traces, bt)
+			}))
+			require.NoError(t, err)
+			defer r.Close()
+
+			rec, err := r.RecordAt(0)
+			require.NoError(t, err)
+			rec.Release()
+
+			idx := make([]int, len(traces))
+			for i, bt := range traces {
+				idx[i] = bt.Index
+				if codec < 0 {
+					assert.False(t, bt.Decompressed)
+					assert.Equal(t, bt.Length, bt.Size)
+				}
+			}
+			assert.Equal(t, []int{0, 1, 3, 4}, idx)
+
+			// 4 int64 values, then 5 int32 offsets.
+			assert.Equal(t, int64(32), traces[1].Size)
+			assert.Equal(t, int64(20), traces[2].Size)
+			if codec >= 0 {
+				prefixes := bufferSizePrefixes(t, raw, 0)
+				require.Len(t, prefixes, len(traces))
+				for i, bt := range traces {
+					assert.Equal(t, prefixes[i] != -1, bt.Decompressed, "buffer %d", bt.Index)
+				}
+			}
+		})
+	}
+}
+
+func TestFileReaderEmpty(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	f, err := ioutil.TempFile(t.TempDir(), "go-arrow-file-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	w, err := NewFileWriter(f, WithSchema(testSchema), WithAllocator(mem))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	raw, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.True(t, testSchema.Equal(r.Schema()))
+	assert.Equal(t, 0, r.NumRecords())
+	rows, err := r.NumRows()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), rows)
+
+	rec, err := r.Read()
+	assert.Nil(t, rec)
+	assert.Equal(t, io.EOF, err)
+
+	tbl, err := r.Table(context.Background())
+	require.NoError(t, err)
+	defer tbl.Release()
+	assert.True(t, testSchema.Equal(tbl.Schema()))
+	assert.Equal(t, int64(0), tbl.NumRows())
+	assert.Equal(t, int64(len(testSchema.Fields())), tbl.NumCols())
+
+	rec, err = r.RecordAt(0)
+	assert.Nil(t, rec)
+	assert.EqualError(t, err, "arrow/ipc: record index 0 out of bounds [0, 0)")
+}
+
+func TestRecordAtBounds(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 2, 3)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err := r.RecordAt(r.NumRecords() - 1)
+	require.NoError(t, err)
+	assert.True(t, array.RecordEqual(recs[1], rec))
+	rec.Release()
+
+	for _, i := range []int{r.NumRecords(), r.NumRecords() + 1, -1} {
+		rec, err := r.RecordAt(i)
+		assert.Nil(t, rec)
+		assert.EqualError(t, err, fmt.Sprintf("arrow/ipc: record index %d out of bounds [0, 2)", i))
+	}
+
+	// Record reports the error too.
+	rec, err = r.Record(2)
+	assert.Nil(t, rec)
+	assert.Error(t, err)
+}
+
+// rewriteBodies returns a copy of the Arrow file raw where the body of each
+// record is replaced by fn(i, body), padded to 8 bytes.
+func rewriteBodies(t *testing.T, raw []byte, fn func(i int, body []byte) []byte) []byte {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	blks := make([]fileBlock, r.NumRecords())
+	for i := range blks {
+		blks[i], err = r.block(i)
+		require.NoError(t, err)
+	}
+	version := r.Version()
+	r.Close()
+
+	last := blks[len(blks)-1]
+	out := append([]byte{}, raw[:blks[0].Offset]...)
+	for i, blk := range blks {
+		start := blk.Offset + int64(blk.Meta)
+		body := fn(i, raw[start:start+blk.Body])
+		body = append(body, make([]byte, paddedLength(int64(len(body)), 8)-int64(len(body)))...)
+
+		blks[i] = fileBlock{Offset: int64(len(out)), Meta: blk.Meta, Body: int64(len(body))}
+		out = append(out, raw[blk.Offset:start]...)
+		out = append(out, body...)
+	}
+	out = append(out, raw[last.Offset+int64(last.Meta)+last.Body:]...)
+
+	return rewriteFooter(t, out, version, func([]fileBlock) []fileBlock { return blks })
+}
+
+func TestFileReaderBodyDecryptor(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 4, 10, 3)
+	defer releaseRecords(recs)
+
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	xor := func(i int, body []byte) []byte {
+		out := make([]byte, len(body))
+		for j, v := range body {
+			out[j] = v ^ byte(0xA5+i)
+		}
+		return out
+	}
+
+	for _, tc := range []struct {
+		name    string
+		encrypt func(i int, body []byte) []byte
+		decrypt func(i int, body []byte) ([]byte, error)
+	}{
+		{
+			name:    "xor",
+			encrypt: xor,
+			decrypt: func(i int, body []byte) ([]byte, error) { return xor(i, body), nil },
+		},
+		{
+			// encrypted bodies hold the size of the sealed data, the nonce
+			// and the sealed data, authenticated with the record index.
+			name: "aes-gcm",
+			encrypt: func(i int, body []byte) []byte {
+				nonce := make([]byte, gcm.NonceSize())
+				nonce[0] = byte(i)
+				sealed := gcm.Seal(nil, nonce, body, []byte(strconv.Itoa(i)))
+				out := make([]byte, 8, 8+len(nonce)+len(sealed))
+				binary.LittleEndian.PutUint64(out, uint64(len(sealed)))
+				out = append(out, nonce...)
+				return append(out, sealed...)
+			},
+			decrypt: func(i int, body []byte) ([]byte, error) {
+				n := int(binary.LittleEndian.Uint64(body))
+				nonce := body[8 : 8+gcm.NonceSize()]
+				sealed := body[8+len(nonce) : 8+len(nonce)+n]
+				return gcm.Open(nil, nonce, sealed, []byte(strconv.Itoa(i)))
+			},
+		},
+	} {
+		for _, codec := range []CompressionType{-1, CompressionZstd} {
+			t.Run(tc.name+"-"+codec.String(), func(t *testing.T) {
+				raw := rewriteBodies(t, writeTestFile(t, recs, WithCompression(codec)), tc.encrypt)
+
+				r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithBodyDecryptor(tc.decrypt))
+				require.NoError(t, err)
+				defer r.Close()
+
+				for i := range recs {
+					rec, err := r.RecordAt(i)
+					require.NoError(t, err)
+					assert.True(t, array.RecordEqual(recs[i], rec), "record %d", i)
+					rec.Release()
+
+					_, err = r.RecordIsStored(i)
+					require.NoError(t, err)
+				}
+			})
+		}
+	}
+
+	t.Run("error", func(t *testing.T) {
+		raw := writeTestFile(t, recs)
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithBodyDecryptor(func(i int, body []byte) ([]byte, error) {
+			if i == 1 {
+				return nil, xerrors.New("bad key")
+			}
+			return body, nil
+		}))
+		require.NoError(t, err)
+		defer r.Close()
+
+		rec, err := r.RecordAt(0)
+		require.NoError(t, err)
+		rec.Release()
+
+		_, err = r.RecordAt(1)
+		assert.EqualError(t, err, "arrow/ipc: could not decrypt body of record 1: bad key")
+	})
+}
+
+func TestFileReaderSchemaBytes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	md := arrow.NewMetadata([]string{"k"}, []string{"v"})
+	schema := arrow.NewSchema(append(testSchema.Fields(),
+		arrow.Field{Name: "list", Type: arrow.ListOf(arrow.PrimitiveTypes.Float32), Nullable: true},
+	), &md)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	raw := writeTestFile(t, []arrow.Record{rec})
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	sb, err := r.SchemaBytes()
+	require.NoError(t, err)
+	assert.Equal(t, kIPCContToken, binary.LittleEndian.Uint32(sb))
+	assert.Equal(t, raw[8:8+len(sb)], sb)
+
+	// the schema message starts a valid stream.
+	stream := append(append([]byte{}, sb...), kEOS[:]...)
+	sr, err := NewReader(bytes.NewReader(stream), WithAllocator(mem))
+	require.NoError(t, err)
+	defer sr.Release()
+	assert.True(t, schema.Equal(sr.Schema()), "got=%v\nwant=%v", sr.Schema(), schema)
+	assert.False(t, sr.Next())
+	assert.NoError(t, sr.Err())
+
+	// a corrupted schema message length is reported.
+	bad := append([]byte{}, raw...)
+	r, err = NewFileReader(bytes.NewReader(bad), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+	binary.LittleEndian.PutUint32(bad[12:], 1<<30)
+	_, err = r.SchemaBytes()
+	assert.Error(t, err)
+}
+
+func TestFileReaderRecordRawBytes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 1)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs, WithCompression(CompressionZstd))
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	// forward a subset of the records, out of order, without decoding them.
+	stream, err := r.SchemaBytes()
+	require.NoError(t, err)
+	want := []int{2, 0, 3}
+	for _, i := range want {
+		rb, err := r.RecordRawBytes(i)
+		require.NoError(t, err)
+		assert.Equal(t, kIPCContToken, binary.LittleEndian.Uint32(rb))
+		stream = append(stream, rb...)
+	}
+	stream = append(stream, kEOS[:]...)
+
+	sr, err := NewReader(bytes.NewReader(stream), WithAllocator(mem))
+	require.NoError(t, err)
+	defer sr.Release()
+	for _, i := range want {
+		require.True(t, sr.Next(), "record %d: %v", i, sr.Err())
+		assert.True(t, array.RecordEqual(recs[i], sr.Record()), "record %d", i)
+	}
+	assert.False(t, sr.Next())
+	assert.NoError(t, sr.Err())
+
+	for _, i := range []int{-1, len(recs)} {
+		_, err := r.RecordRawBytes(i)
+		assert.Error(t, err)
+	}
+
+	blk, err := r.block(0)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name string
+		pos  int64 // offset of the corrupted word, from the start of the block
+		want string
+	}{
+		{"length", 4, "invalid message metadata length"},
+		{"root", 8, "invalid metadata of record 0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bad := append([]byte(nil), raw...)
+			binary.LittleEndian.PutUint32(bad[blk.Offset+tc.pos:], 1<<30)
+
+			r, err := NewFileReader(bytes.NewReader(bad), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			rb, err := r.RecordRawBytes(0)
+			assert.Nil(t, rb)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestLoadNullCount(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "null", Type: arrow.Null, Nullable: true},
+	}, nil)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	for i := 0; i < 5; i++ {
+		b.Field(1).AppendNull()
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	raw := writeTestFile(t, []arrow.Record{rec})
+	mutateRecordMeta(t, raw, 0, func(md *flatbuf.RecordBatch) {
+		var node flatbuf.FieldNode
+		require.True(t, md.Nodes(&node, 1))
+		require.Equal(t, int64(5), node.Length())
+		node.MutateNullCount(0)
+	})
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	nulls := got.Column(1)
+	assert.Equal(t, 5, nulls.Len())
+	assert.Equal(t, 5, nulls.NullN())
+	assert.Equal(t, 5, nulls.Data().NullN())
+	assert.True(t, array.RecordEqual(rec, got))
+}
+
+// rewriteOffset returns a copy of the file raw where the j-th int32 offset
+// of the k-th buffer of the i-th record batch was set to v.
+func rewriteOffset(t *testing.T, raw []byte, i, k, j int, v int32) []byte {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	_, md, err := r.recordMeta(i)
+	require.NoError(t, err)
+	r.Close()
+
+	var buf flatbuf.Buffer
+	require.True(t, md.Buffers(&buf, k))
+	return rewriteBodies(t, raw, func(n int, body []byte) []byte {
+		body = append([]byte{}, body...)
+		if n == i {
+			binary.LittleEndian.PutUint32(body[buf.Offset()+int64(4*j):], uint32(v))
+		}
+		return body
+	})
+}
+
+func TestFileReaderValidate(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 4, 3)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	corrupt := func(i int, fn func(md *flatbuf.RecordBatch)) []byte {
+		out := append([]byte{}, raw...)
+		mutateRecordMeta(t, out, i, fn)
+		return out
+	}
+
+	// buffers: i64 validity, i64 values, str validity, str offsets, str data.
+	for _, tc := range []struct {
+		name string
+		raw  []byte
+		want string
+	}{
+		{
+			name: "valid",
+			raw:  raw,
+		},
+		{
+			name: "decreasing offsets",
+			raw:  rewriteOffset(t, raw, 1, 3, 2, 1),
+			want: `could not read record 1: arrow/ipc: could not load field "str": arrow/ipc: offsets decrease at index 2 (1 < 2)`,
+		},
+		{
+			name: "offset out of bounds",
+			raw:  rewriteOffset(t, raw, 1, 3, 3, 1000),
+			want: `could not read record 1: arrow/ipc: could not load field "str": arrow/ipc: last offset 1000 out of bounds (max=6)`,
+		},
+		{
+			name: "null count",
+			raw: corrupt(0, func(md *flatbuf.RecordBatch) {
+				var node flatbuf.FieldNode
+				md.Nodes(&node, 0)
+				node.MutateNullCount(10)
+			}),
+			want: `could not read record 0: arrow/ipc: could not load field "i64": arrow/ipc: invalid null count 10 for array of length 4`,
+		},
+		{
+			name: "short bitmap",
+			raw: corrupt(0, func(md *flatbuf.RecordBatch) {
+				var buf flatbuf.Buffer
+				md.Buffers(&buf, 0)
+				buf.MutateLength(0)
+			}),
+			want: `could not read record 0: arrow/ipc: could not load field "i64": arrow/ipc: validity bitmap too short (got=0 bytes, want=1)`,
+		},
+		{
+			name: "short values",
+			raw: corrupt(0, func(md *flatbuf.RecordBatch) {
+				var buf flatbuf.Buffer
+				md.Buffers(&buf, 1)
+				buf.MutateLength(8)
+			}),
+			want: `could not read record 0: arrow/ipc: could not load field "i64": arrow/ipc: values buffer too short (got=8 bytes, want=32)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewFileReader(bytes.NewReader(tc.raw), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			err = r.Validate(context.Background())
+			if tc.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+		require.NoError(t, err)
+		defer r.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, r.Validate(ctx), context.Canceled)
+	})
+}
+
+// slowReaderAt is a reader whose reads take delay, counting the reads made
+// while a call to FileReader.Read is in progress.
+type slowReaderAt struct {
+	*bytes.Reader
+	delay    time.Duration
+	reading  int32 // set while the test is in FileReader.Read
+	blocking int32 // number of reads completed while reading is set
+}
+
+func (r *slowReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	time.Sleep(r.delay)
+	if atomic.LoadInt32(&r.reading) == 1 {
+		atomic.AddInt32(&r.blocking, 1)
+	}
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestFileReaderBlocks(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 2, 5, 3)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Empty(t, r.DictionaryBlocks())
+
+	blks := r.RecordBlocks()
+	require.Len(t, blks, len(recs))
+	for i, blk := range blks {
+		// fetch only the bytes of the block, as from object storage.
+		end := blk.Offset + int64(blk.MetaDataLength) + blk.BodyLength
+		mr := NewMessageReader(bytes.NewReader(raw[blk.Offset:end]), WithAllocator(mem))
+		msg, err := mr.Message()
+		require.NoError(t, err)
+		require.Equal(t, MessageRecordBatch, msg.Type())
+
+		rec, err := newRecord(testSchema, msg.meta, bytes.NewReader(msg.body.Bytes()), mem, recordOptions{})
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+		rec.Release()
+		mr.Release()
+	}
+}
+
+// nestedDictFooter returns a copy of the file raw whose footer declares, and
+// no record batches, a single field of type outer with a child of
+// dictionary-encoded strings.
+func nestedDictFooter(t *testing.T, raw []byte, outer flatbuf.Type) []byte {
+	t.Helper()
+
+	b := flatbuffers.NewBuilder(1024)
+
+	childName := b.CreateString("item")
+	flatbuf.Utf8Start(b)
+	utf8 := flatbuf.Utf8End(b)
+	flatbuf.IntStart(b)
+	flatbuf.IntAddBitWidth(b, 32)
+	flatbuf.IntAddIsSigned(b, true)
+	index := flatbuf.IntEnd(b)
+	flatbuf.DictionaryEncodingStart(b)
+	flatbuf.DictionaryEncodingAddId(b, 0)
+	flatbuf.DictionaryEncodingAddIndexType(b, index)
+	encoding := flatbuf.DictionaryEncodingEnd(b)
+	flatbuf.FieldStart(b)
+	flatbuf.FieldAddName(b, childName)
+	flatbuf.FieldAddNullable(b, true)
+	flatbuf.FieldAddTypeType(b, flatbuf.TypeUtf8)
+	flatbuf.FieldAddType(b, utf8)
+	flatbuf.FieldAddDictionary(b, encoding)
+	child := flatbuf.FieldEnd(b)
+
+	flatbuf.FieldStartChildrenVector(b, 1)
+	b.PrependUOffsetT(child)
+	children := b.EndVector(1)
+
+	name := b.CreateString("nested")
+	var typ flatbuffers.UOffsetT
+	switch outer {
+	case flatbuf.TypeList:
+		flatbuf.ListStart(b)
+		typ = flatbuf.ListEnd(b)
+	case flatbuf.TypeStruct_:
+		flatbuf.Struct_Start(b)
+		typ = flatbuf.Struct_End(b)
+	default:
+		t.Fatalf("unhandled outer type %v", outer)
+	}
+	flatbuf.FieldStart(b)
+	flatbuf.FieldAddName(b, name)
+	flatbuf.FieldAddNullable(b, true)
+	flatbuf.FieldAddTypeType(b, outer)
+	flatbuf.FieldAddType(b, typ)
+	flatbuf.FieldAddChildren(b, children)
+	field := flatbuf.FieldEnd(b)
+
+	flatbuf.SchemaStartFieldsVector(b, 1)
+	b.PrependUOffsetT(field)
+	fields := b.EndVector(1)
+	flatbuf.SchemaStart(b)
+	flatbuf.SchemaAddFields(b, fields)
+	schema := flatbuf.SchemaEnd(b)
+
+	dicts := fileBlocksToFB(b, nil, flatbuf.FooterStartDictionariesVector)
+	recs := fileBlocksToFB(b, nil, flatbuf.FooterStartRecordBatchesVector)
+	flatbuf.FooterStart(b)
+	flatbuf.FooterAddVersion(b, flatbuf.MetadataVersion(currentMetadataVersion))
+	flatbuf.FooterAddSchema(b, schema)
+	flatbuf.FooterAddDictionaries(b, dicts)
+	flatbuf.FooterAddRecordBatches(b, recs)
+	b.Finish(flatbuf.FooterEnd(b))
+	footer := b.FinishedBytes()
+
+	size := int64(binary.LittleEndian.Uint32(raw[len(raw)-len(Magic)-4:]))
+	start := int64(len(raw)) - size - int64(len(Magic)+4)
+
+	out := append([]byte{}, raw[:start]...)
+	out = append(out, footer...)
+	out = append(out, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(out[len(out)-4:], uint32(len(footer)))
+	return append(out, Magic...)
+}
+
+func TestFileReaderRejectsNestedDictionary(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 1)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, outer := range []flatbuf.Type{flatbuf.TypeList, flatbuf.TypeStruct_} {
+		t.Run(flatbuf.EnumNamesType[outer], func(t *testing.T) {
+			_, err := NewFileReader(bytes.NewReader(nestedDictFooter(t, raw, outer)), WithAllocator(mem))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "dictionary-encoded fields are not supported (1 dictionaries in schema)")
+		})
+	}
+}
+
+// recordingAllocator records the memory it allocates.
+type recordingAllocator struct {
+	memory.Allocator
+	mu   sync.Mutex
+	ptrs map[*byte]bool
+}
+
+func newRecordingAllocator(mem memory.Allocator) *recordingAllocator {
+	return &recordingAllocator{Allocator: mem, ptrs: make(map[*byte]bool)}
+}
+
+func (a *recordingAllocator) Allocate(size int) []byte {
+	b := a.Allocator.Allocate(size)
+	a.record(b)
+	return b
+}
+
+func (a *recordingAllocator) Reallocate(size int, b []byte) []byte {
+	b = a.Allocator.Reallocate(size, b)
+	a.record(b)
+	return b
+}
+
+func (a *recordingAllocator) record(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ptrs[&b[0]] = true
+}
+
+// owns reports whether buf was allocated by a.
+func (a *recordingAllocator) owns(buf *memory.Buffer) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return buf.Len() > 0 && a.ptrs[&buf.Bytes()[0]]
+}
+
+func TestFileReaderColumnAllocator(t *testing.T) {
+	checked := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer checked.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, checked, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, lazy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("lazy=%v", lazy), func(t *testing.T) {
+			var (
+				mem  = newRecordingAllocator(checked)
+				col1 = newRecordingAllocator(checked)
+				opts = []Option{WithAllocator(mem), WithColumnAllocator(1, col1)}
+			)
+			if lazy {
+				opts = append(opts, WithLazyColumns())
+			}
+
+			r, err := NewFileReader(bytes.NewReader(raw), opts...)
+			require.NoError(t, err)
+			defer r.Close()
+
+			rec, err := r.RecordAt(0)
+			require.NoError(t, err)
+			defer rec.Release()
+			assert.True(t, array.RecordEqual(recs[0], rec))
+
+			for i, want := range []*recordingAllocator{mem, col1} {
+				for j, buf := range rec.Column(i).Data().Buffers() {
+					if buf == nil || buf.Len() == 0 {
+						continue
+					}
+					assert.Truef(t, want.owns(buf), "buffer %d of column %d from wrong allocator", j, i)
+				}
+			}
+		})
+	}
+}
+
+func TestBodyDecompressor(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name  string
+		opts  []Option
+		codec bool
+	}{
+		{name: "uncompressed"},
+		{name: "zstd", opts: []Option{WithZstd()}, codec: true},
+		{name: "lz4", opts: []Option{WithLZ4()}, codec: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewFileReader(bytes.NewReader(writeTestFile(t, recs, tc.opts...)))
+			require.NoError(t, err)
+			defer r.Close()
+
+			_, md, err := r.recordMeta(0)
+			require.NoError(t, err)
+
+			codec, err := bodyDecompressor(md, r.opts)
+			require.NoError(t, err)
+			if !tc.codec {
+				assert.Nil(t, codec)
+				return
+			}
+			require.NotNil(t, codec)
+			codec.Close()
+		})
+	}
+}
+
+func BenchmarkFileReaderCoalesceReads(b *testing.B) {
+	mem := memory.NewGoAllocator()
+	recs := makeTestRecords(b, mem, 10, 10, 10, 10, 10, 10, 10, 10)
+	defer releaseRecords(recs)
+	raw := writeTestFile(b, recs)
+
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"none", nil},
+		{"coalesced", []Option{WithCoalesceReads(64)}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			sr := &slowReaderAt{Reader: bytes.NewReader(raw), delay: 100 * time.Microsecond}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				r, err := NewFileReader(sr, append(bc.opts, WithAllocator(mem))...)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for j := 0; j < r.NumRecords(); j++ {
+					rec, err := r.RecordAt(j)
+					if err != nil {
+						b.Fatal(err)
+					}
+					rec.Release()
+				}
+				r.Close()
+			}
+		})
+	}
+}
+
+func BenchmarkFileReaderLazyFooter(b *testing.B) {
+	mem := memory.NewGoAllocator()
+	recs := makeTestRecords(b, mem, 10)
+	defer releaseRecords(recs)
+
+	// 100k blocks, all pointing at the same record batch.
+	raw := rewriteFooter(b, writeTestFile(b, recs), MetadataV5, func(blks []fileBlock) []fileBlock {
+		out := make([]fileBlock, 100000)
+		for i := range out {
+			out[i] = blks[0]
+		}
+		return out
+	})
+
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"eager", nil},
+		{"lazy", []Option{WithLazyFooter()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				r, err := NewFileReader(bytes.NewReader(raw), append(bc.opts, WithAllocator(mem))...)
+				if err != nil {
+					b.Fatal(err)
+				}
+				rec, err := r.RecordAt(50000)
+				if err != nil {
+					b.Fatal(err)
+				}
+				rec.Release()
+				r.Close()
+			}
+		})
+	}
+}
+
+func BenchmarkFileReaderTrustedInput(b *testing.B) {
+	mem := memory.NewGoAllocator()
+	// many small records, for the checks to weigh on their decoding.
+	rows := make([]int, 1000)
+	for i := range rows {
+		rows[i] = 1
+	}
+	recs := makeTestRecords(b, mem, rows...)
+	defer releaseRecords(recs)
+	raw := writeTestFile(b, recs)
+
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"checked", nil},
+		{"trusted", []Option{WithTrustedInput()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			r, err := NewFileReader(bytes.NewReader(raw), append(bc.opts, WithAllocator(mem))...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer r.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < r.NumRecords(); j++ {
+					rec, err := r.RecordAt(j)
+					if err != nil {
+						b.Fatal(err)
+					}
+					rec.Release()
+				}
+			}
+		})
+	}
+}
+
+func TestFileReaderBooleans(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "b", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "dense", Type: arrow.FixedWidthTypes.Boolean},
+	}, nil)
+
+	// makeRecord returns a record of n rows, with a null every third row of
+	// the first column and no null in the second one.
+	makeRecord := func(n int) arrow.Record {
+		b := array.NewRecordBuilder(mem, schema)
+		defer b.Release()
+		for i := 0; i < n; i++ {
+			if i%3 == 1 {
+				b.Field(0).(*array.BooleanBuilder).AppendNull()
+			} else {
+				b.Field(0).(*array.BooleanBuilder).Append(i%2 == 0)
+			}
+			b.Field(1).(*array.BooleanBuilder).Append(i%4 != 3)
+		}
+		return b.NewRecord()
+	}
+
+	var recs []arrow.Record
+	for _, n := range []int{1, 7, 8, 9} {
+		recs = append(recs, makeRecord(n))
+	}
+	full := makeRecord(17)
+	defer full.Release()
+	for _, rng := range [][2]int64{{1, 8}, {3, 12}, {8, 17}, {5, 6}} {
+		recs = append(recs, full.NewSlice(rng[0], rng[1]))
+	}
+	defer releaseRecords(recs)
+
+	for _, opts := range [][]Option{nil, {WithCompression(CompressionZstd)}} {
+		r, err := NewFileReader(bytes.NewReader(writeTestFile(t, recs, opts...)),
+			WithAllocator(mem), WithStrictValidation())
+		require.NoError(t, err)
+		require.NoError(t, r.Validate(context.Background()))
+
+		for i, want := range recs {
+			got, err := r.RecordAt(i)
+			require.NoError(t, err)
+			assert.Truef(t, array.RecordEqual(want, got), "record %d:\ngot=  %v\nwant= %v", i, got, want)
+			assert.Equal(t, want.Column(0).NullN(), got.Column(0).NullN(), "record %d", i)
+			got.Release()
+		}
+		r.Close()
+	}
+}
+
+func TestFileReaderRecordPostHook(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 1)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	type call struct {
+		rows  int64
+		bytes int64
+		err   bool
+	}
+	calls := make(map[int][]call)
+	hook := func(i int, rec arrow.Record, n int64, err error) {
+		c := call{rows: -1, bytes: n, err: err != nil}
+		if rec != nil {
+			c.rows = rec.NumRows()
+		}
+		calls[i] = append(calls[i], c)
+	}
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithRecordPostHook(hook))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, ReadStats{}, r.Stats())
+
+	for {
+		_, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	rec, err := r.RecordAt(2)
+	require.NoError(t, err)
+	rec.Release()
+	_, err = r.RecordAt(len(recs))
+	assert.Error(t, err)
+
+	var total int64
+	for i, blk := range r.RecordBlocks() {
+		want := []call{{rows: recs[i].NumRows(), bytes: int64(blk.MetaDataLength) + blk.BodyLength}}
+		if i == 2 {
+			want = append(want, want[0])
+		}
+		assert.Equal(t, want, calls[i], "record %d", i)
+		for _, c := range calls[i] {
+			total += c.bytes
+		}
+	}
+	assert.Equal(t, []call{{rows: -1, err: true}}, calls[len(recs)])
+	assert.Equal(t, ReadStats{Records: int64(len(recs) + 1), BytesRead: total}, r.Stats())
+}
+
+func TestFileReaderProgressReporter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 1, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	type report struct {
+		done, total int
+		bytes       int64
+	}
+	var reports []report
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithProgressReporter(func(done, total int, bytes int64) {
+		reports = append(reports, report{done, total, bytes})
+	}))
+	require.NoError(t, err)
+	defer r.Close()
+
+	check := func(t *testing.T, total int, read func() error) {
+		reports = nil
+		before := r.Stats().BytesRead
+		require.NoError(t, read())
+
+		require.Len(t, reports, total)
+		for i, rep := range reports {
+			assert.Equal(t, i+1, rep.done)
+			assert.Equal(t, total, rep.total)
+			if i > 0 {
+				assert.Greater(t, rep.bytes, reports[i-1].bytes)
+			}
+		}
+		assert.Equal(t, r.Stats().BytesRead-before, reports[total-1].bytes)
+	}
+
+	t.Run("table", func(t *testing.T) {
+		check(t, len(recs), func() error {
+			tbl, err := r.Table(context.Background())
+			if err == nil {
+				tbl.Release()
+			}
+			return err
+		})
+	})
+	t.Run("range", func(t *testing.T) {
+		check(t, 3, func() error {
+			rec, err := r.RecordRange(1, 4)
+			if err == nil {
+				rec.Release()
+			}
+			return err
+		})
+	})
+	t.Run("tail", func(t *testing.T) {
+		check(t, 2, func() error {
+			got, err := r.TailRecords(2)
+			releaseRecords(got)
+			return err
+		})
+	})
+
+	// single records are not bulk reads.
+	reports = nil
+	rec, err := r.RecordAt(0)
+	require.NoError(t, err)
+	rec.Release()
+	assert.Empty(t, reports)
+}
+
+func TestProgressReporterConcurrent(t *testing.T) {
+	const (
+		workers = 8
+		n       = 100
+	)
+	var (
+		last int
+		p    = &progressReporter{total: workers * n}
+	)
+	p.fn = func(done, total int, bytes int64) {
+		assert.Equal(t, last+1, done)
+		assert.Equal(t, int64(done)*10, bytes)
+		last = done
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				p.add(10)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, workers*n, last)
+
+	// nil reporters report nothing.
+	(*progressReporter)(nil).add(10)
+}
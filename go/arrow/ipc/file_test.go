@@ -20,10 +20,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
 
+	"github.com/apache/arrow/go/v8/arrow/array"
 	"github.com/apache/arrow/go/v8/arrow/internal/arrdata"
 	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/ipc"
 	"github.com/apache/arrow/go/v8/arrow/memory"
 )
 
@@ -84,3 +87,109 @@ func TestFileCompressed(t *testing.T) {
 		}
 	}
 }
+
+func TestFileConcurrentRecordAt(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go-arrow-file-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for name, recs := range arrdata.Records {
+		t.Run(name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			f, err := ioutil.TempFile(tempDir, "go-arrow-file-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			arrdata.WriteFile(t, f, mem, recs[0].Schema(), recs)
+
+			r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			const nworkers = 8
+			var (
+				wg   sync.WaitGroup
+				errs = make(chan error, nworkers*r.NumRecords())
+			)
+			for w := 0; w < nworkers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < r.NumRecords(); i++ {
+						rec, err := r.RecordAt(i)
+						if err != nil {
+							errs <- err
+							return
+						}
+						if !array.RecordEqual(rec, recs[i]) {
+							errs <- fmt.Errorf("records[%d] differ", i)
+						}
+						rec.Release()
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestFileLazyColumns(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "go-arrow-file-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for name, recs := range arrdata.Records {
+		t.Run(name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			f, err := ioutil.TempFile(tempDir, "go-arrow-file-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			arrdata.WriteFileCompressed(t, f, mem, recs[0].Schema(), recs, flatbuf.CompressionTypeZSTD, 0)
+
+			r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem), ipc.WithLazyColumns())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			for i := 0; i < r.NumRecords(); i++ {
+				rec, err := r.RecordAt(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// load the columns in reverse order, so each is located
+				// without loading the preceding ones.
+				for j := int(rec.NumCols()) - 1; j >= 0; j-- {
+					if !array.ArrayEqual(rec.Column(j), recs[i].Column(j)) {
+						t.Fatalf("records[%d]: column %q differ", i, rec.ColumnName(j))
+					}
+				}
+				if !array.RecordEqual(rec, recs[i]) {
+					t.Fatalf("records[%d] differ", i)
+				}
+				rec.Release()
+			}
+		})
+	}
+}
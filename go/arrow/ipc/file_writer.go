@@ -276,6 +276,8 @@ type FileWriter struct {
 	schema     *arrow.Schema
 	codec      flatbuf.CompressionType
 	compressNP int
+	zstdDict   []byte
+	threshold  float64
 }
 
 // NewFileWriter opens an Arrow file using the provided writer w.
@@ -285,6 +287,10 @@ func NewFileWriter(w io.WriteSeeker, opts ...Option) (*FileWriter, error) {
 		err error
 	)
 
+	if err := checkCompression(cfg.codec); err != nil {
+		return nil, err
+	}
+
 	f := FileWriter{
 		w:          w,
 		pw:         &pwriter{w: w, schema: cfg.schema, pos: -1},
@@ -292,6 +298,8 @@ func NewFileWriter(w io.WriteSeeker, opts ...Option) (*FileWriter, error) {
 		schema:     cfg.schema,
 		codec:      cfg.codec,
 		compressNP: cfg.compressNP,
+		zstdDict:   cfg.zstdDict,
+		threshold:  cfg.threshold,
 	}
 
 	pos, err := f.w.Seek(0, io.SeekCurrent)
@@ -335,7 +343,7 @@ func (f *FileWriter) Write(rec arrow.Record) error {
 	const allow64b = true
 	var (
 		data = Payload{msg: MessageRecordBatch}
-		enc  = newRecordEncoder(f.mem, 0, kMaxNestingDepth, allow64b, f.codec, f.compressNP)
+		enc  = newRecordEncoder(f.mem, 0, kMaxNestingDepth, allow64b, f.codec, f.compressNP, f.zstdDict, f.threshold)
 	)
 	defer data.Release()
 
@@ -0,0 +1,44 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"hash/fnv"
+)
+
+// SchemaFingerprint returns a stable 64-bit fingerprint of the schema stored
+// in the file, suitable as a cache key for decoded schemas and for plans
+// derived from them.
+//
+// The fingerprint covers the name, nullability and type of every field,
+// including child types and type parameters such as time units, time zones
+// and decimal precision and scale, in field order. Schema and field metadata
+// are not part of the fingerprint: two files with the same logical schema
+// have the same fingerprint whatever metadata they carry.
+//
+// The fingerprint is computed from the file schema, before any read-time
+// conversion requested through the reader options.
+func (f *FileReader) SchemaFingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("S{"))
+	for _, field := range f.schema.Fields() {
+		h.Write([]byte(field.Fingerprint()))
+		h.Write([]byte{';'})
+	}
+	h.Write([]byte{'}'})
+	return h.Sum64()
+}
@@ -0,0 +1,121 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaFingerprint(t *testing.T, schema *arrow.Schema) uint64 {
+	t.Helper()
+
+	f, err := ioutil.TempFile(t.TempDir(), "go-arrow-file-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	w, err := NewFileWriter(f, WithSchema(schema))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err := NewFileReader(f)
+	require.NoError(t, err)
+	defer r.Close()
+	return r.SchemaFingerprint()
+}
+
+func TestFileReaderSchemaFingerprint(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	fields := func(ts, dec, elem arrow.DataType) []arrow.Field {
+		return []arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "ts", Type: ts, Nullable: true},
+			{Name: "dec", Type: dec, Nullable: true},
+			{Name: "list", Type: arrow.ListOf(elem), Nullable: true},
+			{Name: "st", Type: arrow.StructOf(
+				arrow.Field{Name: "a", Type: arrow.BinaryTypes.String, Nullable: true},
+			)},
+		}
+	}
+	var (
+		ts   = &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}
+		dec  = &arrow.Decimal128Type{Precision: 10, Scale: 2}
+		elem = arrow.PrimitiveTypes.Int32
+		base = fields(ts, dec, elem)
+	)
+
+	want := schemaFingerprint(t, arrow.NewSchema(base, nil))
+
+	recs := makeTestRecords(t, mem, 3)
+	defer releaseRecords(recs)
+	r, err := NewFileReader(bytes.NewReader(writeTestFile(t, recs)))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, r.SchemaFingerprint(), r.SchemaFingerprint())
+	assert.NotEqual(t, want, r.SchemaFingerprint())
+
+	t.Run("metadata", func(t *testing.T) {
+		md1 := arrow.NewMetadata([]string{"k1", "k2"}, []string{"v1", "v2"})
+		md2 := arrow.NewMetadata([]string{"k2", "k1"}, []string{"v2", "v1"})
+
+		fs1 := fields(ts, dec, elem)
+		fs1[0].Metadata = md1
+		fs2 := fields(ts, dec, elem)
+		fs2[0].Metadata = md2
+
+		assert.Equal(t, want, schemaFingerprint(t, arrow.NewSchema(fs1, &md1)))
+		assert.Equal(t, want, schemaFingerprint(t, arrow.NewSchema(fs2, &md2)))
+	})
+
+	for _, tc := range []struct {
+		name   string
+		fields []arrow.Field
+	}{
+		{"timezone", fields(&arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "Europe/Paris"}, dec, elem)},
+		{"unit", fields(&arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, dec, elem)},
+		{"precision", fields(ts, &arrow.Decimal128Type{Precision: 12, Scale: 2}, elem)},
+		{"scale", fields(ts, &arrow.Decimal128Type{Precision: 10, Scale: 3}, elem)},
+		{"child", fields(ts, dec, arrow.PrimitiveTypes.Int64)},
+		{"nullability", func() []arrow.Field {
+			fs := fields(ts, dec, elem)
+			fs[0].Nullable = true
+			return fs
+		}()},
+		{"name", func() []arrow.Field {
+			fs := fields(ts, dec, elem)
+			fs[0].Name = "key"
+			return fs
+		}()},
+		{"order", func() []arrow.Field {
+			fs := fields(ts, dec, elem)
+			fs[1], fs[2] = fs[2], fs[1]
+			return fs
+		}()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NotEqual(t, want, schemaFingerprint(t, arrow.NewSchema(tc.fields, nil)))
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"golang.org/x/xerrors"
+)
+
+// footerBlockSize is the size of a flatbuf.Block entry of the footer.
+const footerBlockSize = 24
+
+// footer vtable slots of the dictionary and record batch block vectors.
+const (
+	footerDictsSlot = 8
+	footerRecsSlot  = 10
+)
+
+// lazyFooter reads the entries of the block vectors of a footer on demand.
+// The rest of the footer is read when the file is opened.
+type lazyFooter struct {
+	r    io.ReaderAt
+	base int64                             // offset of the footer in the file
+	vecs map[flatbuffers.VOffsetT][2]int64 // position and length of the block vectors, by vtable slot
+}
+
+// footer vtable slots of the offset fields of the root table, the others
+// being scalars.
+var footerOffsetSlots = []flatbuffers.VOffsetT{6, footerDictsSlot, footerRecsSlot, 12}
+
+// readLazyFooter reads the footer of the given size at offset base of r,
+// leaving out the entries of its block vectors. The root table, its vtable
+// and the headers of the block vectors are read first, into small buffers,
+// to locate the entries. The returned buffer holds the rest of the footer,
+// compacted: the offsets of the root table are adjusted to the entries left
+// out, and its block vectors are declared empty. This assumes the entries
+// do not lie within the schema or the custom metadata of the footer, as no
+// writer lays them out that way.
+func readLazyFooter(r io.ReaderAt, base, size int64) ([]byte, *lazyFooter, error) {
+	var (
+		readInto = func(buf []byte, off int64) error {
+			if off < 0 || off+int64(len(buf)) > size {
+				return errInconsistentFileMetadata
+			}
+			if _, err := r.ReadAt(buf, base+off); err != nil {
+				return xerrors.Errorf("arrow/ipc: could not read footer data: %w", err)
+			}
+			return nil
+		}
+		read = func(off, n int64) ([]byte, error) {
+			if n < 0 || n > size {
+				return nil, errInconsistentFileMetadata
+			}
+			buf := make([]byte, n)
+			return buf, readInto(buf, off)
+		}
+		u32 = func(buf []byte) int64 { return int64(binary.LittleEndian.Uint32(buf)) }
+	)
+
+	// root table, then its vtable, as laid out by flatbuffers.
+	buf, err := read(0, 4)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := u32(buf)
+	if buf, err = read(root, 4); err != nil {
+		return nil, nil, err
+	}
+	vtab := root - int64(int32(u32(buf)))
+	if buf, err = read(vtab, 4); err != nil {
+		return nil, nil, err
+	}
+	vsize := int64(binary.LittleEndian.Uint16(buf))
+	tsize := int64(binary.LittleEndian.Uint16(buf[2:]))
+	if vsize < 4 || tsize < 4 {
+		return nil, nil, errInconsistentFileMetadata
+	}
+	vt, err := read(vtab, vsize)
+	if err != nil {
+		return nil, nil, err
+	}
+	tab, err := read(root, tsize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// field returns the offset in the root table of the field at slot, or 0
+	// if absent.
+	field := func(slot flatbuffers.VOffsetT) (int64, error) {
+		if int64(slot)+2 > vsize {
+			return 0, nil
+		}
+		o := int64(binary.LittleEndian.Uint16(vt[slot:]))
+		if o != 0 && o+4 > tsize {
+			return 0, errInconsistentFileMetadata
+		}
+		return o, nil
+	}
+
+	var (
+		lf   = &lazyFooter{r: r, base: base, vecs: make(map[flatbuffers.VOffsetT][2]int64)}
+		gaps [][2]int64 // block entries of the vectors, left out
+	)
+	for _, slot := range []flatbuffers.VOffsetT{footerDictsSlot, footerRecsSlot} {
+		o, err := field(slot)
+		if err != nil {
+			return nil, nil, err
+		}
+		if o == 0 {
+			continue
+		}
+		vec := root + o + u32(tab[o:]) + 4
+		if buf, err = read(vec-4, 4); err != nil {
+			return nil, nil, err
+		}
+		n := u32(buf)
+		end := vec + n*footerBlockSize
+		if end > size {
+			return nil, nil, errInconsistentFileMetadata
+		}
+		lf.vecs[slot] = [2]int64{vec, n}
+		if n > 0 {
+			gaps = append(gaps, [2]int64{vec, end})
+		}
+	}
+
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i][0] < gaps[j][0] })
+	merged := gaps[:0]
+	for _, gap := range gaps {
+		if n := len(merged); n > 0 && gap[0] <= merged[n-1][1] {
+			if gap[1] > merged[n-1][1] {
+				merged[n-1][1] = gap[1]
+			}
+			continue
+		}
+		merged = append(merged, gap)
+	}
+
+	// moved returns the position in the compacted footer of the byte at
+	// position pos of the footer, outside of the entries left out.
+	moved := func(pos int64) int64 {
+		out := pos
+		for _, gap := range merged {
+			if gap[1] <= pos {
+				out -= gap[1] - gap[0]
+			}
+		}
+		return out
+	}
+
+	out := make([]byte, moved(size))
+	pos := int64(0)
+	for _, gap := range append(merged, [2]int64{size, size}) {
+		if gap[0] > pos {
+			if err := readInto(out[moved(pos):moved(gap[0])], pos); err != nil {
+				return nil, nil, err
+			}
+		}
+		pos = gap[1]
+	}
+
+	put := func(pos, v int64) { binary.LittleEndian.PutUint32(out[moved(pos):], uint32(v)) }
+	put(0, moved(root))
+	put(root, moved(root)-moved(vtab))
+	for _, slot := range footerOffsetSlots {
+		o, err := field(slot)
+		if err != nil {
+			return nil, nil, err
+		}
+		if o == 0 {
+			continue
+		}
+		at, to := root+o, root+o+u32(tab[o:])
+		if to < 0 || to > size {
+			return nil, nil, errInconsistentFileMetadata
+		}
+		put(at, moved(to)-moved(at))
+	}
+	for _, vec := range lf.vecs {
+		put(vec[0]-4, 0)
+	}
+	return out, lf, nil
+}
+
+// len returns the number of entries of the block vector at the given vtable
+// slot.
+func (lf *lazyFooter) len(slot flatbuffers.VOffsetT) int {
+	return int(lf.vecs[slot][1])
+}
+
+// checkFooter checks that the root table of the footer buf, its vtable, and
+// the fields read from the footer lie within buf, so that they can be
+// accessed without panicking. The schema table is only checked to start
+// within buf.
+func checkFooter(buf []byte) error {
+	var (
+		size = int64(len(buf))
+		in   = func(off, n int64) bool { return off >= 0 && n >= 0 && off+n <= size }
+		u32  = func(off int64) int64 { return int64(binary.LittleEndian.Uint32(buf[off:])) }
+	)
+
+	// root table, then its vtable, as laid out by flatbuffers.
+	if !in(0, 4) {
+		return errInconsistentFileMetadata
+	}
+	root := u32(0)
+	if !in(root, 4) {
+		return errInconsistentFileMetadata
+	}
+	vtab := root - int64(int32(u32(root)))
+	if !in(vtab, 4) {
+		return errInconsistentFileMetadata
+	}
+	vsize := int64(binary.LittleEndian.Uint16(buf[vtab:]))
+	tsize := int64(binary.LittleEndian.Uint16(buf[vtab+2:]))
+	if vsize < 4 || vsize%2 != 0 || !in(vtab, vsize) || tsize < 4 || !in(root, tsize) {
+		return errInconsistentFileMetadata
+	}
+
+	tab := flatbuf.GetRootAsFooter(buf, 0).Table()
+	field := func(slot flatbuffers.VOffsetT, n int64) (int64, bool) {
+		o := int64(tab.Offset(slot))
+		if o == 0 {
+			return 0, false
+		}
+		if o+n > tsize {
+			return 0, true
+		}
+		return root + o, true
+	}
+
+	if pos, ok := field(4, 2); ok && pos == 0 {
+		return errInconsistentFileMetadata
+	}
+	if pos, ok := field(6, 4); ok {
+		if pos == 0 || !in(pos+u32(pos), 4) {
+			return errInconsistentFileMetadata
+		}
+	}
+	for _, slot := range []flatbuffers.VOffsetT{footerDictsSlot, footerRecsSlot} {
+		pos, ok := field(slot, 4)
+		if !ok {
+			continue
+		}
+		if pos == 0 {
+			return errInconsistentFileMetadata
+		}
+		vec := pos + u32(pos)
+		if !in(vec, 4) || !in(vec+4, u32(vec)*footerBlockSize) {
+			return errInconsistentFileMetadata
+		}
+	}
+	return nil
+}
+
+// block reads the i-th entry of the block vector at the given vtable slot.
+func (lf *lazyFooter) block(slot flatbuffers.VOffsetT, i int) (flatbuf.Block, error) {
+	var blk flatbuf.Block
+	vec, ok := lf.vecs[slot]
+	if !ok || i < 0 || int64(i) >= vec[1] {
+		return blk, xerrors.Errorf("arrow/ipc: footer block %d out of bounds", i)
+	}
+	buf := make([]byte, footerBlockSize)
+	if _, err := lf.r.ReadAt(buf, lf.base+vec[0]+int64(i)*footerBlockSize); err != nil {
+		return blk, xerrors.Errorf("arrow/ipc: could not read footer block %d: %w", i, err)
+	}
+	blk.Init(buf, 0)
+	return blk, nil
+}
@@ -0,0 +1,182 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderCorruptFooter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	eof := len(raw) - len(Magic) - 4
+	size := int(binary.LittleEndian.Uint32(raw[eof:]))
+	start := eof - size
+	require.Zero(t, size%4)
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(data []byte) []byte
+		offset int64
+	}{
+		{name: "size-zero", mutate: footerSize(0)},
+		{name: "size-one", mutate: footerSize(1)},
+		{name: "size-unaligned", mutate: footerSize(uint32(size) - 3)},
+		{name: "size-over-file", mutate: footerSize(uint32(len(raw)))},
+		{name: "size-negative", mutate: footerSize(1 << 31)},
+		{name: "size-max", mutate: footerSize(1<<32 - 1)},
+		{name: "offset-minimum", offset: int64(len(Magic)*2 + 5)},
+		{name: "offset-above-minimum", offset: int64(len(Magic)*2 + 8)},
+		{name: "root-out-of-footer", mutate: func(data []byte) []byte {
+			binary.LittleEndian.PutUint32(data[start:], uint32(size))
+			return data
+		}},
+		{name: "vtable-out-of-footer", mutate: func(data []byte) []byte {
+			root := start + int(binary.LittleEndian.Uint32(data[start:]))
+			binary.LittleEndian.PutUint32(data[root:], 1<<31)
+			return data
+		}},
+		{name: "table-out-of-footer", mutate: func(data []byte) []byte {
+			root := start + int(binary.LittleEndian.Uint32(data[start:]))
+			vtab := root - int(int32(binary.LittleEndian.Uint32(data[root:])))
+			binary.LittleEndian.PutUint16(data[vtab+2:], 1<<15)
+			return data
+		}},
+	} {
+		for _, lazy := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/lazy=%v", tc.name, lazy), func(t *testing.T) {
+				data := append([]byte(nil), raw...)
+				if tc.mutate != nil {
+					data = tc.mutate(data)
+				}
+				opts := []Option{WithAllocator(mem)}
+				if tc.offset != 0 {
+					opts = append(opts, WithFooterOffset(tc.offset))
+				}
+				if lazy {
+					opts = append(opts, WithLazyFooter())
+				}
+
+				r, err := NewFileReader(bytes.NewReader(data), opts...)
+				if err == nil {
+					r.Close()
+				}
+				assert.Error(t, err)
+			})
+		}
+	}
+}
+
+func TestFileReaderLazyFooter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rows := make([]int, 50)
+	for i := range rows {
+		rows[i] = i % 7
+	}
+	recs := makeTestRecords(t, mem, rows...)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	open := func(raw []byte, opts ...Option) (*FileReader, int64) {
+		rr := &readAtRecorder{Reader: bytes.NewReader(raw)}
+		r, err := NewFileReader(rr, append(opts, WithAllocator(mem))...)
+		require.NoError(t, err)
+		var n int64
+		for _, rd := range rr.reads {
+			n += rd[1]
+		}
+		return r, n
+	}
+
+	eager, eagerN := open(raw)
+	defer eager.Close()
+	lazy, lazyN := open(raw, WithLazyFooter())
+	defer lazy.Close()
+
+	// the block entries are skipped, while the few bytes of the root table
+	// and its vtable are read twice.
+	assert.Less(t, lazyN, eagerN-int64(len(recs)*footerBlockSize)+64,
+		"lazy footer read %d bytes, eager one %d", lazyN, eagerN)
+	// nor are they kept in memory.
+	assert.Equal(t, eager.footer.buffer.Len()-len(recs)*footerBlockSize, lazy.footer.buffer.Len())
+	assert.True(t, eager.Schema().Equal(lazy.Schema()))
+	assert.Equal(t, eager.NumRecords(), lazy.NumRecords())
+	assert.Equal(t, eager.NumDictionaries(), lazy.NumDictionaries())
+	assert.Equal(t, eager.RecordBlocks(), lazy.RecordBlocks())
+	assert.Equal(t, eager.DictionaryBlocks(), lazy.DictionaryBlocks())
+	for _, i := range []int{0, 17, len(recs) - 1} {
+		rec, err := lazy.RecordAt(i)
+		require.NoError(t, err)
+		assert.True(t, array.RecordEqual(recs[i], rec), "record %d", i)
+		rec.Release()
+	}
+	_, err := lazy.RecordAt(len(recs))
+	assert.Error(t, err)
+
+	// sorting the blocks reads them all.
+	reversed := rewriteFooter(t, raw, MetadataV5, func(blks []fileBlock) []fileBlock {
+		for i, j := 0, len(blks)-1; i < j; i, j = i+1, j-1 {
+			blks[i], blks[j] = blks[j], blks[i]
+		}
+		return blks
+	})
+	sorted, _ := open(reversed, WithLazyFooter(), WithSortedBlocks())
+	defer sorted.Close()
+	for i := range recs {
+		rec, err := sorted.RecordAt(i)
+		require.NoError(t, err)
+		assert.True(t, array.RecordEqual(recs[i], rec), "record %d", i)
+		rec.Release()
+	}
+
+	// the custom metadata is read past the block entries, and the digest
+	// still covers the bytes preceding the footer.
+	sum := sha256.Sum256(raw[:eager.footer.start])
+	md := arrow.NewMetadata([]string{"k", FooterDigestKey}, []string{"v", hex.EncodeToString(sum[:])})
+	digested, _ := open(withFooterMetadata(t, raw, md), WithLazyFooter(), WithFooterDigest(sha256.New))
+	defer digested.Close()
+	got, err := metadataFromFB(digested.footer.data)
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+	assert.Equal(t, len(recs), digested.NumRecords())
+
+	// a root table outside of the footer is reported.
+	bad := append([]byte{}, raw...)
+	size := int64(binary.LittleEndian.Uint32(bad[len(bad)-len(Magic)-4:]))
+	start := int64(len(bad)) - size - int64(len(Magic)+4)
+	binary.LittleEndian.PutUint32(bad[start:], uint32(size))
+	_, err = NewFileReader(bytes.NewReader(bad), WithAllocator(mem), WithLazyFooter())
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+)
+
+// FormatOptions configures the formatting of a record by FormatRecord.
+type FormatOptions struct {
+	MaxRows  int    // maximum number of rows to format, all rows if <= 0
+	MaxWidth int    // maximum width of a formatted value, unlimited if <= 0
+	Null     string // representation of null values, "(null)" if empty
+}
+
+// FormatRecord returns a human-readable representation of the i-th record
+// of the file, one row after the other. Nested values are formatted over
+// several lines, with indentation.
+func (f *FileReader) FormatRecord(i int, opts FormatOptions) (string, error) {
+	rec, err := f.RecordAt(i)
+	if err != nil {
+		return "", err
+	}
+	defer rec.Release()
+
+	if opts.Null == "" {
+		opts.Null = "(null)"
+	}
+	p := formatter{opts: opts}
+
+	fmt.Fprintf(&p.o, "record %d (%d rows)\n", i, rec.NumRows())
+	nrows := int(rec.NumRows())
+	if opts.MaxRows > 0 && nrows > opts.MaxRows {
+		nrows = opts.MaxRows
+	}
+	for row := 0; row < nrows; row++ {
+		fmt.Fprintf(&p.o, "row %d:\n", row)
+		for j, col := range rec.Columns() {
+			fmt.Fprintf(&p.o, "  %s: ", rec.ColumnName(j))
+			p.value(col, row, "  ")
+			p.o.WriteString("\n")
+		}
+	}
+	if n := int(rec.NumRows()) - nrows; n > 0 {
+		fmt.Fprintf(&p.o, "... %d more rows\n", n)
+	}
+
+	return p.o.String(), nil
+}
+
+type formatter struct {
+	o    strings.Builder
+	opts FormatOptions
+}
+
+// value formats the i-th value of arr. Nested values are formatted over
+// several lines, indented relative to indent.
+func (p *formatter) value(arr arrow.Array, i int, indent string) {
+	if arr.IsNull(i) {
+		p.o.WriteString(p.opts.Null)
+		return
+	}
+
+	switch arr := arr.(type) {
+	case *array.Null:
+		p.o.WriteString(p.opts.Null)
+	case *array.String:
+		p.scalar(strconv.Quote(arr.Value(i)))
+	case *array.Binary:
+		p.scalar(fmt.Sprintf("%q", arr.Value(i)))
+	case *array.FixedSizeBinary:
+		p.scalar(fmt.Sprintf("%q", arr.Value(i)))
+	case *array.Decimal128:
+		p.scalar(formatDecimal(arr.Value(i).BigInt().String(), arr.DataType().(*arrow.Decimal128Type).Scale))
+	case *array.Map:
+		j := i + arr.Data().Offset()
+		beg, end := int(arr.Offsets()[j]), int(arr.Offsets()[j+1])
+		if beg == end {
+			p.o.WriteString("{}")
+			return
+		}
+		p.o.WriteString("{\n")
+		for k := beg; k < end; k++ {
+			p.o.WriteString(indent + "  ")
+			p.value(arr.Keys(), k, indent+"  ")
+			p.o.WriteString(": ")
+			p.value(arr.Items(), k, indent+"  ")
+			p.o.WriteString("\n")
+		}
+		p.o.WriteString(indent + "}")
+	case *array.List:
+		j := i + arr.Data().Offset()
+		p.list(arr.ListValues(), int(arr.Offsets()[j]), int(arr.Offsets()[j+1]), indent)
+	case *array.FixedSizeList:
+		n := int(arr.DataType().(*arrow.FixedSizeListType).Len())
+		beg := (i + arr.Data().Offset()) * n
+		p.list(arr.ListValues(), beg, beg+n, indent)
+	case *array.Struct:
+		dt := arr.DataType().(*arrow.StructType)
+		p.o.WriteString("{\n")
+		for k := 0; k < arr.NumField(); k++ {
+			fmt.Fprintf(&p.o, "%s  %s: ", indent, dt.Field(k).Name)
+			p.value(arr.Field(k), i, indent+"  ")
+			p.o.WriteString("\n")
+		}
+		p.o.WriteString(indent + "}")
+	case array.ExtensionArray:
+		p.value(arr.Storage(), i, indent)
+	default:
+		// fixed-width values, formatted from their Value method.
+		v := reflect.ValueOf(arr).MethodByName("Value")
+		if !v.IsValid() {
+			p.scalar(fmt.Sprintf("<%v>", arr.DataType()))
+			return
+		}
+		p.scalar(fmt.Sprint(v.Call([]reflect.Value{reflect.ValueOf(i)})[0].Interface()))
+	}
+}
+
+// list formats the values [beg, end) of arr, one per line.
+func (p *formatter) list(arr arrow.Array, beg, end int, indent string) {
+	if beg == end {
+		p.o.WriteString("[]")
+		return
+	}
+	p.o.WriteString("[\n")
+	for k := beg; k < end; k++ {
+		p.o.WriteString(indent + "  ")
+		p.value(arr, k, indent+"  ")
+		p.o.WriteString("\n")
+	}
+	p.o.WriteString(indent + "]")
+}
+
+// scalar writes s, truncated to the maximum width.
+func (p *formatter) scalar(s string) {
+	if max := p.opts.MaxWidth; max > 0 {
+		if r := []rune(s); len(r) > max {
+			if max > 3 {
+				s = string(r[:max-3]) + "..."
+			} else {
+				s = string(r[:max])
+			}
+		}
+	}
+	p.o.WriteString(s)
+}
+
+// formatDecimal formats the unscaled decimal digits v with the given scale.
+func formatDecimal(v string, scale int32) string {
+	if scale <= 0 {
+		return v + strings.Repeat("0", int(-scale))
+	}
+	sign := ""
+	if strings.HasPrefix(v, "-") {
+		sign, v = "-", v[1:]
+	}
+	if n := int(scale) + 1 - len(v); n > 0 {
+		v = strings.Repeat("0", n) + v
+	}
+	i := len(v) - int(scale)
+	return sign + v[:i] + "." + v[i:]
+}
@@ -0,0 +1,87 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/internal/arrdata"
+	"github.com/apache/arrow/go/v8/arrow/ipc"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestFormatRecord(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts ipc.FormatOptions
+	}{
+		{name: "primitives", opts: ipc.FormatOptions{MaxRows: 3}},
+		{name: "nulls"},
+		{name: "strings", opts: ipc.FormatOptions{MaxWidth: 3, Null: "NULL"}},
+		{name: "structs"},
+		{name: "lists"},
+		{name: "fixed_size_lists"},
+		{name: "maps"},
+		{name: "fixed_width_types"},
+		{name: "decimal128", opts: ipc.FormatOptions{MaxRows: 2}},
+		{name: "extension"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			f, err := ioutil.TempFile(t.TempDir(), "go-arrow-format-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			recs := arrdata.Records[tc.name]
+			arrdata.WriteFile(t, f, mem, recs[0].Schema(), recs)
+
+			r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			got, err := r.FormatRecord(0, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fname := filepath.Join("testdata", "format_"+tc.name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(fname, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(fname)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Fatalf("invalid output:\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package ipc
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/memory"
+)
+
+// FuzzReadFooter checks that opening arbitrary bytes as an Arrow file fails
+// cleanly, without panicking, unless they hold a valid file.
+func FuzzReadFooter(f *testing.F) {
+	mem := memory.NewGoAllocator()
+	recs := makeTestRecords(f, mem, 3, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(f, recs)
+
+	f.Add(raw)
+	for _, n := range []int{0, 4, len(Magic), len(Magic)*2 + 4, len(Magic)*2 + 5, len(raw) / 2, len(raw) - 1} {
+		f.Add(raw[:n])
+	}
+	f.Add(raw[len(raw)-len(Magic)*2-4:])
+	for _, size := range []uint32{0, 1, 3, 5, 7, 1 << 31, 1<<32 - 1} {
+		data := append([]byte(nil), raw...)
+		binary.LittleEndian.PutUint32(data[len(data)-len(Magic)-4:], size)
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, opts := range [][]Option{nil, {WithLazyFooter()}} {
+			r, err := NewFileReaderFromBytes(data, opts...)
+			if err != nil {
+				continue
+			}
+			r.NumRecords()
+			r.NumDictionaries()
+			_ = r.VerifyBlocks()
+			r.Close()
+		}
+	})
+}
@@ -0,0 +1,200 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// httpMinRead is the minimum number of bytes fetched by a range request.
+// Small reads, such as the ones of the footer and of record metadata, are
+// widened to that size so that neighbouring reads are served from memory.
+const httpMinRead = 64 << 10
+
+// HTTPRangeReaderAt reads a remote file with HTTP range requests.
+//
+// Each request fetches at least 64KiB; the last range fetched is kept in
+// memory and serves the reads falling within it. The first range holds the
+// end of the file, where the footer of an Arrow file is.
+type HTTPRangeReaderAt struct {
+	client *http.Client
+	url    string
+	size   int64
+
+	mu   sync.Mutex
+	buf  []byte // last range fetched
+	boff int64  // offset of buf in the file
+}
+
+// NewHTTPRangeReaderAt returns a reader of the file at url, using client to
+// issue the requests, or http.DefaultClient if client is nil. It fetches the
+// end of the file to learn its size, and fails if the server does not
+// support range requests.
+func NewHTTPRangeReaderAt(url string, client *http.Client) (*HTTPRangeReaderAt, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	r := &HTTPRangeReaderAt{client: client, url: url}
+
+	// a suffix range returns the tail of the file along with its size.
+	buf, off, size, err := r.get(context.Background(), fmt.Sprintf("bytes=-%d", httpMinRead))
+	if err != nil {
+		return nil, err
+	}
+	r.buf, r.boff, r.size = buf, off, size
+	return r, nil
+}
+
+// NewFileReaderURL opens the Arrow file at url, reading it with HTTP range
+// requests issued by client, or http.DefaultClient if client is nil.
+func NewFileReaderURL(url string, client *http.Client, opts ...Option) (*FileReader, error) {
+	r, err := NewHTTPRangeReaderAt(url, client)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileReaderSize(r, r.Size(), opts...)
+}
+
+// Size returns the size of the remote file.
+func (r *HTTPRangeReaderAt) Size() int64 { return r.size }
+
+// ReadAt implements io.ReaderAt.
+func (r *HTTPRangeReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.ReadAtContext(context.Background(), p, off)
+}
+
+// ReadAtContext implements ReaderAtContext, canceling the range request
+// with ctx.
+func (r *HTTPRangeReaderAt) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, xerrors.Errorf("arrow/ipc: invalid negative offset %d", off)
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	end := off + int64(len(p))
+	if end > r.size {
+		end = r.size
+	}
+
+	// the lock is not held during requests, so that reads served from
+	// memory do not wait for them.
+	r.mu.Lock()
+	buf, boff := r.buf, r.boff
+	r.mu.Unlock()
+
+	if off < boff || end > boff+int64(len(buf)) {
+		beg, last := off, end
+		if last-beg < httpMinRead {
+			// widen the range forward, or backward near the end of the file.
+			last = beg + httpMinRead
+			if last > r.size {
+				last = r.size
+				beg = last - httpMinRead
+				if beg < 0 {
+					beg = 0
+				}
+			}
+		}
+		var err error
+		buf, boff, _, err = r.get(ctx, fmt.Sprintf("bytes=%d-%d", beg, last-1))
+		if err != nil {
+			return 0, err
+		}
+		if off < boff || end > boff+int64(len(buf)) {
+			return 0, xerrors.Errorf("arrow/ipc: range [%d, %d) received does not cover [%d, %d)", boff, boff+int64(len(buf)), off, end)
+		}
+
+		r.mu.Lock()
+		r.buf, r.boff = buf, boff
+		r.mu.Unlock()
+	}
+
+	n := copy(p, buf[off-boff:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// get issues a GET request for the given byte range, and returns the bytes
+// received with their offset, along with the size of the file.
+func (r *HTTPRangeReaderAt) get(ctx context.Context, rng string) ([]byte, int64, int64, error) {
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, 0, 0, xerrors.Errorf("arrow/ipc: could not create request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Range", rng)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, 0, 0, xerrors.Errorf("arrow/ipc: could not request range %q: %w", rng, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return nil, 0, 0, xerrors.Errorf("arrow/ipc: server does not support range requests for %q", r.url)
+	default:
+		return nil, 0, 0, xerrors.Errorf("arrow/ipc: could not request range %q: %s", rng, resp.Status)
+	}
+
+	beg, end, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	buf, err := ioutil.ReadAll(io.LimitReader(resp.Body, end-beg+1))
+	if err != nil {
+		return nil, 0, 0, xerrors.Errorf("arrow/ipc: could not read range %q: %w", rng, err)
+	}
+	if int64(len(buf)) != end-beg+1 {
+		return nil, 0, 0, xerrors.Errorf("arrow/ipc: short range %q (got=%d, want=%d)", rng, len(buf), end-beg+1)
+	}
+	return buf, beg, size, nil
+}
+
+// parseContentRange parses a "bytes first-last/size" Content-Range header.
+func parseContentRange(v string) (beg, end, size int64, err error) {
+	rng := strings.TrimPrefix(v, "bytes ")
+	i := strings.IndexByte(rng, '-')
+	j := strings.IndexByte(rng, '/')
+	if rng == v || i < 0 || j < i {
+		return 0, 0, 0, xerrors.Errorf("arrow/ipc: invalid Content-Range %q", v)
+	}
+	if beg, err = strconv.ParseInt(rng[:i], 10, 64); err == nil {
+		if end, err = strconv.ParseInt(rng[i+1:j], 10, 64); err == nil {
+			size, err = strconv.ParseInt(rng[j+1:], 10, 64)
+		}
+	}
+	if err != nil || beg > end || end >= size {
+		return 0, 0, 0, xerrors.Errorf("arrow/ipc: invalid Content-Range %q", v)
+	}
+	return beg, end, size, nil
+}
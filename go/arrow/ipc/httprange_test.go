@@ -0,0 +1,225 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rangeServer serves raw, recording the Range header of each request.
+type rangeServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	ranges []string
+}
+
+func newRangeServer(t *testing.T, raw []byte, ranges bool) *rangeServer {
+	srv := &rangeServer{}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		srv.mu.Lock()
+		srv.ranges = append(srv.ranges, req.Header.Get("Range"))
+		srv.mu.Unlock()
+		if !ranges {
+			w.Write(raw)
+			return
+		}
+		http.ServeContent(w, req, "file.arrow", time.Time{}, bytes.NewReader(raw))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (srv *rangeServer) requests() []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]string(nil), srv.ranges...)
+}
+
+func TestNewFileReaderURL(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	for _, tc := range []struct {
+		name string
+		rows []int
+		reqs int // requests issued to open the file and read all records
+	}{
+		// footer and records are all in the tail fetched when opening.
+		{"small", []int{3, 0, 7}, 1},
+		// records larger than 64KiB need a request for their metadata,
+		// then another one for their body.
+		{"large", []int{4000, 4000, 4000}, 7},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recs := makeTestRecords(t, mem, tc.rows...)
+			defer releaseRecords(recs)
+			raw := writeTestFile(t, recs)
+
+			srv := newRangeServer(t, raw, true)
+			r, err := NewFileReaderURL(srv.URL, srv.Client(), WithAllocator(mem))
+			require.NoError(t, err)
+			defer r.Close()
+
+			require.Equal(t, len(recs), r.NumRecords())
+			for i := range recs {
+				rec, err := r.RecordAt(i)
+				require.NoError(t, err)
+				assert.True(t, array.RecordEqual(recs[i], rec), "record %d", i)
+				rec.Release()
+			}
+
+			reqs := srv.requests()
+			assert.Len(t, reqs, tc.reqs, "requests: %q", reqs)
+			for _, rng := range reqs {
+				assert.NotEmpty(t, rng)
+			}
+		})
+	}
+}
+
+func TestHTTPRangeReaderAt(t *testing.T) {
+	raw := make([]byte, 3*httpMinRead+123)
+	for i := range raw {
+		raw[i] = byte(i % 251)
+	}
+	srv := newRangeServer(t, raw, true)
+
+	r, err := NewHTTPRangeReaderAt(srv.URL, srv.Client())
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(raw)), r.Size())
+	assert.Equal(t, []string{"bytes=-65536"}, srv.requests())
+
+	for _, rng := range [][2]int64{
+		{int64(len(raw)) - 10, 10},   // within the tail fetched when opening
+		{0, 16},                      // widened to [0, 64KiB)
+		{100, 200},                   // served from memory
+		{httpMinRead - 8, 16},        // across the range in memory
+		{10, 2*httpMinRead + 1},      // larger than the minimum read
+		{int64(len(raw)) - 100, 200}, // past the end of the file
+	} {
+		p := make([]byte, rng[1])
+		n, err := r.ReadAt(p, rng[0])
+		end := rng[0] + rng[1]
+		if end > int64(len(raw)) {
+			end = int64(len(raw))
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, raw[rng[0]:end], p[:n], "range %v", rng)
+	}
+	assert.Equal(t, []string{
+		"bytes=-65536",
+		"bytes=0-65535",
+		"bytes=65528-131063",
+		"bytes=10-131082",
+		"bytes=131195-196730",
+	}, srv.requests())
+
+	_, err = r.ReadAt(make([]byte, 1), int64(len(raw)))
+	assert.Error(t, err)
+}
+
+func TestHTTPRangeReaderAtNoRanges(t *testing.T) {
+	recs := makeTestRecords(t, memory.DefaultAllocator, 3)
+	defer releaseRecords(recs)
+	srv := newRangeServer(t, writeTestFile(t, recs), false)
+
+	_, err := NewFileReaderURL(srv.URL, srv.Client())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server does not support range requests")
+
+	srv = newRangeServer(t, nil, true)
+	srv.Config.Handler = http.NotFoundHandler()
+	_, err = NewHTTPRangeReaderAt(srv.URL, srv.Client())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestHTTPRangeReaderAtBadRange(t *testing.T) {
+	raw := make([]byte, 3*httpMinRead)
+	srv := newRangeServer(t, raw, true)
+
+	r, err := NewHTTPRangeReaderAt(srv.URL, srv.Client())
+	require.NoError(t, err)
+
+	// the server answers with the start of the file whatever the range.
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-9/%d", len(raw)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(raw[:10])
+	})
+
+	for _, off := range []int64{httpMinRead, 5} {
+		n, err := r.ReadAt(make([]byte, 16), off)
+		assert.Equal(t, 0, n)
+		require.Error(t, err, "offset %d", off)
+		assert.Contains(t, err.Error(), "does not cover")
+	}
+}
+
+func TestHTTPRangeReaderAtConcurrent(t *testing.T) {
+	raw := make([]byte, 3*httpMinRead)
+	for i := range raw {
+		raw[i] = byte(i % 251)
+	}
+	srv := newRangeServer(t, raw, true)
+
+	r, err := NewHTTPRangeReaderAt(srv.URL, srv.Client())
+	require.NoError(t, err)
+
+	// block the next request until a read served from memory completes.
+	var (
+		blocked = make(chan struct{})
+		release = make(chan struct{})
+		done    = make(chan error)
+	)
+	handler := srv.Config.Handler
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(blocked)
+		<-release
+		handler.ServeHTTP(w, req)
+	})
+	go func() {
+		_, err := r.ReadAt(make([]byte, 16), 0)
+		done <- err
+	}()
+	<-blocked
+
+	p := make([]byte, 16)
+	timer := time.AfterFunc(5*time.Second, func() { close(release) })
+	n, err := r.ReadAt(p, int64(len(raw))-16)
+	if !timer.Stop() {
+		t.Fatal("read served from memory waited for a pending request")
+	}
+	close(release)
+	require.NoError(t, err)
+	assert.Equal(t, raw[len(raw)-16:], p[:n])
+	assert.NoError(t, <-done)
+}
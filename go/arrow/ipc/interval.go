@@ -0,0 +1,97 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"math"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"golang.org/x/xerrors"
+)
+
+const day = 24 * time.Hour
+
+// MonthDayNanoIntervalAt returns the components of the i-th element of arr.
+func MonthDayNanoIntervalAt(arr *array.MonthDayNanoInterval, i int) (months, days int32, nanos int64) {
+	v := arr.Value(i)
+	return v.Months, v.Days, v.Nanoseconds
+}
+
+// MonthDayNanoIntervalDuration returns the i-th element of arr as a
+// time.Duration, counting days as 24 hours.
+// An error is returned if the element has a non-zero month component, as
+// months have no fixed duration, or if it does not fit in a time.Duration.
+func MonthDayNanoIntervalDuration(arr *array.MonthDayNanoInterval, i int) (time.Duration, error) {
+	v := arr.Value(i)
+	if v.Months != 0 {
+		return 0, xerrors.Errorf("arrow/ipc: interval %d has a month component (months=%d)", i, v.Months)
+	}
+	return intervalDuration(i, v.Days, time.Duration(v.Nanoseconds))
+}
+
+// DayTimeIntervalDuration returns the i-th element of arr as a
+// time.Duration, counting days as 24 hours.
+// An error is returned if the element does not fit in a time.Duration.
+func DayTimeIntervalDuration(arr *array.DayTimeInterval, i int) (time.Duration, error) {
+	v := arr.Value(i)
+	// an int32 count of milliseconds always fits in a time.Duration.
+	return intervalDuration(i, v.Days, time.Duration(v.Milliseconds)*time.Millisecond)
+}
+
+// intervalDuration returns days*24h+d, reporting an error for the i-th
+// interval instead of silently wrapping around on overflow.
+func intervalDuration(i int, days int32, d time.Duration) (time.Duration, error) {
+	if int64(days) > int64(math.MaxInt64/day) || int64(days) < int64(math.MinInt64/day) {
+		return 0, xerrors.Errorf("arrow/ipc: interval %d overflows time.Duration (days=%d)", i, days)
+	}
+	n := time.Duration(days) * day
+	sum := n + d
+	if (d > 0 && sum < n) || (d < 0 && sum > n) {
+		return 0, xerrors.Errorf("arrow/ipc: interval %d overflows time.Duration (days=%d, duration=%v)", i, days, d)
+	}
+	return sum, nil
+}
+
+// MonthDayNanoValues returns the values of the col-th column of rec, of type
+// month_day_nano_interval, along with a mask telling which values are
+// present. The values are decoded from the fixed-width values buffer of the
+// column, 16 bytes per element. Null values are left as the zero interval,
+// with their mask set to false.
+func MonthDayNanoValues(rec arrow.Record, col int) ([]arrow.MonthDayNanoInterval, []bool, error) {
+	if col < 0 || col >= int(rec.NumCols()) {
+		return nil, nil, xerrors.Errorf("arrow/ipc: column index %d out of bounds [0, %d)", col, rec.NumCols())
+	}
+
+	arr, ok := rec.Column(col).(*array.MonthDayNanoInterval)
+	if !ok {
+		return nil, nil, xerrors.Errorf("arrow/ipc: column %q of type %v is not a month_day_nano_interval column", rec.ColumnName(col), rec.Column(col).DataType())
+	}
+
+	var (
+		values  = make([]arrow.MonthDayNanoInterval, arr.Len())
+		present = make([]bool, arr.Len())
+	)
+	for i, v := range arr.MonthDayNanoIntervalValues() {
+		if arr.IsValid(i) {
+			values[i] = v
+			present[i] = true
+		}
+	}
+	return values, present, nil
+}
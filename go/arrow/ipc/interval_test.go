@@ -0,0 +1,173 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalDurations(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	dtb := array.NewDayTimeIntervalBuilder(mem)
+	defer dtb.Release()
+	dtb.AppendValues([]arrow.DayTimeInterval{
+		{Days: 1, Milliseconds: 500},
+		{Days: -2, Milliseconds: -1},
+		{Days: 0, Milliseconds: 0},
+		{Days: 106752, Milliseconds: 0},
+		{Days: -106752, Milliseconds: 0},
+		{Days: 106751, Milliseconds: math.MaxInt32},
+	}, nil)
+	dt := dtb.NewArray()
+	defer dt.Release()
+
+	mdnb := array.NewMonthDayNanoIntervalBuilder(mem)
+	defer mdnb.Release()
+	mdnb.AppendValues([]arrow.MonthDayNanoInterval{
+		{Months: 0, Days: 1, Nanoseconds: 42},
+		{Months: 0, Days: -3, Nanoseconds: -7},
+		{Months: -1, Days: 2, Nanoseconds: 3},
+		{Months: 0, Days: math.MaxInt32, Nanoseconds: 0},
+		{Months: 0, Days: -106751, Nanoseconds: math.MinInt64},
+		{Months: 0, Days: 1, Nanoseconds: math.MaxInt64},
+	}, nil)
+	mdn := mdnb.NewArray()
+	defer mdn.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "day_time", Type: arrow.FixedWidthTypes.DayTimeInterval},
+		{Name: "month_day_nano", Type: arrow.FixedWidthTypes.MonthDayNanoInterval},
+	}, nil)
+	rec := array.NewRecord(schema, []arrow.Array{dt, mdn}, 6)
+	defer rec.Release()
+
+	r, err := NewFileReader(bytes.NewReader(writeTestFile(t, []arrow.Record{rec})), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	days := got.Column(0).(*array.DayTimeInterval)
+	for i, want := range []time.Duration{24*time.Hour + 500*time.Millisecond, -48*time.Hour - time.Millisecond, 0} {
+		d, err := DayTimeIntervalDuration(days, i)
+		require.NoError(t, err)
+		assert.Equal(t, want, d)
+	}
+	for i := 3; i < 6; i++ {
+		_, err := DayTimeIntervalDuration(days, i)
+		require.Error(t, err, "interval %d", i)
+		assert.Contains(t, err.Error(), "overflows time.Duration")
+	}
+
+	nanos := got.Column(1).(*array.MonthDayNanoInterval)
+	for i, want := range []struct {
+		months, days int32
+		nanos        int64
+	}{{0, 1, 42}, {0, -3, -7}, {-1, 2, 3}} {
+		months, days, nanos := MonthDayNanoIntervalAt(nanos, i)
+		assert.Equal(t, want.months, months)
+		assert.Equal(t, want.days, days)
+		assert.Equal(t, want.nanos, nanos)
+	}
+
+	d, err := MonthDayNanoIntervalDuration(nanos, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour+42, d)
+
+	d, err = MonthDayNanoIntervalDuration(nanos, 1)
+	require.NoError(t, err)
+	assert.Equal(t, -72*time.Hour-7, d)
+
+	_, err = MonthDayNanoIntervalDuration(nanos, 2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "month component")
+
+	for i := 3; i < 6; i++ {
+		_, err = MonthDayNanoIntervalDuration(nanos, i)
+		require.Error(t, err, "interval %d", i)
+		assert.Contains(t, err.Error(), "overflows time.Duration")
+	}
+}
+
+func TestMonthDayNanoValues(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "mdn", Type: arrow.FixedWidthTypes.MonthDayNanoInterval, Nullable: true},
+		{Name: "dt", Type: arrow.FixedWidthTypes.DayTimeInterval},
+	}, nil)
+
+	var (
+		valid = []bool{true, true, false, true, true}
+		want  = []arrow.MonthDayNanoInterval{
+			{Months: 1, Days: 2, Nanoseconds: 3},
+			{Months: -12, Days: -30, Nanoseconds: -1},
+			{},
+			{Months: math.MaxInt32, Days: math.MinInt32, Nanoseconds: math.MinInt64},
+			{Months: 0, Days: -1, Nanoseconds: 86400e9},
+		}
+	)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	in := append([]arrow.MonthDayNanoInterval(nil), want...)
+	in[2] = arrow.MonthDayNanoInterval{Months: 7, Days: 7, Nanoseconds: 7}
+	b.Field(0).(*array.MonthDayNanoIntervalBuilder).AppendValues(in, valid)
+	b.Field(1).(*array.DayTimeIntervalBuilder).AppendValues(make([]arrow.DayTimeInterval, len(valid)), nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	raw := writeTestFile(t, []arrow.Record{rec})
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	values, present, err := MonthDayNanoValues(got, 0)
+	require.NoError(t, err)
+	assert.Equal(t, valid, present)
+	assert.Equal(t, want, values)
+
+	slice := got.NewSlice(1, 4)
+	defer slice.Release()
+	values, present, err = MonthDayNanoValues(slice, 0)
+	require.NoError(t, err)
+	assert.Equal(t, valid[1:4], present)
+	assert.Equal(t, want[1:4], values)
+
+	for _, col := range []int{-1, 1, 2} {
+		_, _, err := MonthDayNanoValues(got, col)
+		assert.Errorf(t, err, "column %d", col)
+	}
+}
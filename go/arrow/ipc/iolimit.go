@@ -0,0 +1,50 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+)
+
+// ioLimiter bounds the number of concurrent ReadAt calls made to the
+// reader it wraps.
+type ioLimiter struct {
+	ReadAtSeeker
+	sem chan struct{}
+}
+
+func newIOLimiter(r ReadAtSeeker, n int) *ioLimiter {
+	return &ioLimiter{ReadAtSeeker: r, sem: make(chan struct{}, n)}
+}
+
+func (l *ioLimiter) ReadAt(p []byte, off int64) (int, error) {
+	return l.ReadAtContext(context.Background(), p, off)
+}
+
+// ReadAtContext waits for one of the concurrent reads to complete, if the
+// limit is reached, before reading from the underlying reader with ctx.
+func (l *ioLimiter) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	defer func() { <-l.sem }()
+
+	r := ctxReaderAt{ctx: ctx, r: l.ReadAtSeeker}
+	return r.ReadAt(p, off)
+}
@@ -0,0 +1,119 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// peakReaderAt is a reader whose reads take delay, recording the peak
+// number of reads in flight.
+type peakReaderAt struct {
+	*bytes.Reader
+	delay    time.Duration
+	inflight int32
+	peak     int32
+}
+
+func (r *peakReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n := atomic.AddInt32(&r.inflight, 1)
+	defer atomic.AddInt32(&r.inflight, -1)
+	for {
+		peak := atomic.LoadInt32(&r.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&r.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(r.delay)
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestFileReaderMaxConcurrentIO(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 1, 2, 3, 4, 5, 6, 7, 8)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	read := func(t *testing.T, opts ...Option) int32 {
+		pr := &peakReaderAt{Reader: bytes.NewReader(raw), delay: 2 * time.Millisecond}
+		r, err := NewFileReader(pr, append(opts, WithAllocator(mem))...)
+		require.NoError(t, err)
+		defer r.Close()
+
+		var wg sync.WaitGroup
+		for i := range recs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				rec, err := r.RecordAt(i)
+				if assert.NoError(t, err) {
+					assert.True(t, array.RecordEqual(recs[i], rec))
+					rec.Release()
+				}
+			}(i)
+		}
+		wg.Wait()
+		return pr.peak
+	}
+
+	assert.Greater(t, read(t), int32(2), "records should be read concurrently without limit")
+
+	for _, n := range []int{1, 2} {
+		for _, tc := range []struct {
+			name string
+			opts []Option
+		}{
+			{"default", nil},
+			{"coalesce", []Option{WithCoalesceReads(0)}},
+			{"read-ahead", []Option{WithReadAhead(4)}},
+		} {
+			t.Run(fmt.Sprintf("%s/n=%d", tc.name, n), func(t *testing.T) {
+				peak := read(t, append(tc.opts, WithMaxConcurrentIO(n))...)
+				assert.LessOrEqual(t, peak, int32(n))
+			})
+		}
+	}
+}
+
+func TestIOLimiterContext(t *testing.T) {
+	l := newIOLimiter(bytes.NewReader(make([]byte, 16)), 1)
+	l.sem <- struct{}{} // a read in flight
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := l.ReadAtContext(ctx, make([]byte, 4), 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	<-l.sem
+	n, err := l.ReadAtContext(context.Background(), make([]byte, 4), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Empty(t, l.sem, "reads should release the limiter")
+}
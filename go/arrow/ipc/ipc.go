@@ -17,10 +17,12 @@
 package ipc
 
 import (
+	"hash"
 	"io"
 
 	"github.com/apache/arrow/go/v8/arrow"
 	"github.com/apache/arrow/go/v8/arrow/arrio"
+	"github.com/apache/arrow/go/v8/arrow/endian"
 	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
 	"github.com/apache/arrow/go/v8/arrow/memory"
 )
@@ -31,6 +33,8 @@ const (
 	errInconsistentSchema       = errString("arrow/ipc: tried to write record batch with different schema")
 	errMaxRecursion             = errString("arrow/ipc: max recursion depth reached")
 	errBigArray                 = errString("arrow/ipc: array larger than 2^31-1 in length")
+	errRecordMemoryLimit        = errString("arrow/ipc: record memory limit exceeded")
+	errMaxRetainedBytes         = errString("arrow/ipc: maximum retained bytes reached")
 
 	kArrowAlignment    = 64 // buffers are padded to 64b boundaries (for SIMD)
 	kTensorAlignment   = 64 // tensors are padded to 64b boundaries
@@ -41,8 +45,18 @@ var (
 	paddingBytes  [kArrowAlignment]byte
 	kEOS                 = [8]byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0} // end of stream message
 	kIPCContToken uint32 = 0xFFFFFFFF                                  // 32b continuation indicator for FlatBuffers 8b alignment
+
+	// nativeEndianness is the endianness of the host, which is the
+	// endianness of the buffers we write and the one we expect to read.
+	nativeEndianness = flatbuf.EndiannessLittle
 )
 
+func init() {
+	if endian.IsBigEndian {
+		nativeEndianness = flatbuf.EndiannessBig
+	}
+}
+
 func paddedLength(nbytes int64, alignment int32) int64 {
 	align := int64(alignment)
 	return ((nbytes + align - 1) / align) * align
@@ -66,14 +80,63 @@ type config struct {
 	footer struct {
 		offset int64
 	}
-	codec      flatbuf.CompressionType
-	compressNP int
+	embedded struct {
+		start, end int64
+	}
+	codec        flatbuf.CompressionType
+	compressNP   int
+	byteSwap     bool
+	sortBlocks   bool
+	versions     []MetadataVersion
+	zstdDict     []byte
+	memLimit     int64
+	strict       bool
+	threshold    float64
+	registry     *DecompressorRegistry
+	arena        bool
+	rescale      map[string]*arrow.Decimal128Type
+	feather      bool
+	trace        func(BufferTrace)
+	utc          bool
+	lazy         bool
+	decrypt      func(int, []byte) ([]byte, error)
+	casts        map[string]arrow.DataType
+	readAhead    int
+	dups         DuplicateFieldPolicy
+	digest       func() hash.Hash
+	colMem       map[int]memory.Allocator
+	maxRetained  int64
+	filter       func(map[string]ColumnStats) bool
+	coalesce     int64
+	resolveExt   func(string, arrow.DataType, string) (arrow.ExtensionType, error)
+	lazyFooter   bool
+	postHook     func(int, arrow.Record, int64, error)
+	relaxed      bool
+	progress     func(int, int, int64)
+	trusted      bool
+	target       *arrow.Schema
+	fillMissing  bool
+	decompressNP int
+	maxIO        int
+	spill        int64
+	allocReset   func()
+	ring         int
+	bufferMem    func(BufferRole, arrow.Field) memory.Allocator
+	schemaMeta   func(arrow.Metadata) arrow.Metadata
+	fieldMeta    func([]int, arrow.Field) arrow.Metadata
+	extBody      func(int) (io.ReaderAt, int64, error)
+	leakCheck    bool
+	host         flatbuf.Endianness // endianness of the buffers readers produce
 }
 
 func newConfig(opts ...Option) *config {
 	cfg := &config{
-		alloc: memory.NewGoAllocator(),
-		codec: -1, // uncompressed
+		alloc:     memory.NewGoAllocator(),
+		codec:     -1, // uncompressed
+		threshold: 1,
+		coalesce:  -1, // reads are not coalesced
+		spill:     defaultSpillThreshold,
+		host:      nativeEndianness,
 	}
 
 	for _, opt := range opts {
@@ -94,6 +157,17 @@ func WithFooterOffset(offset int64) Option {
 	}
 }
 
+// WithEmbeddedFile specifies that the Arrow file is embedded in a larger
+// container, occupying the bytes [start, end) of the underlying reader.
+// All reads are then performed relative to start, and the Arrow magic bytes
+// are checked at both ends of that range.
+func WithEmbeddedFile(start, end int64) Option {
+	return func(cfg *config) {
+		cfg.embedded.start = start
+		cfg.embedded.end = end
+	}
+}
+
 // WithAllocator specifies the Arrow memory allocator used while building records.
 func WithAllocator(mem memory.Allocator) Option {
 	return func(cfg *config) {
@@ -124,6 +198,438 @@ func WithZstd() Option {
 	}
 }
 
+// WithCompression tells the writer to compress the data buffers with the
+// given codec before writing. Requires >= Arrow 1.0.0 to read/decompress.
+// Buffers that do not shrink when compressed are stored uncompressed.
+// Codecs other than CompressionLZ4Frame and CompressionZstd are rejected by
+// the writers.
+func WithCompression(codec CompressionType) Option {
+	return func(cfg *config) {
+		cfg.codec = flatbuf.CompressionType(codec)
+	}
+}
+
+// WithCompressionThreshold specifies the compression ratio above which the
+// writer stores a buffer uncompressed rather than compressed: a buffer is
+// stored as-is when its compressed size is not below ratio times its
+// uncompressed size. The default ratio is 1, meaning buffers are stored
+// uncompressed only when compression does not shrink them.
+func WithCompressionThreshold(ratio float64) Option {
+	return func(cfg *config) {
+		cfg.threshold = ratio
+	}
+}
+
+// WithDecompressorRegistry specifies a registry of decompressors for the
+// reader to consult before the ones registered with RegisterDecompressor
+// and the built-in ones.
+func WithDecompressorRegistry(reg *DecompressorRegistry) Option {
+	return func(cfg *config) {
+		cfg.registry = reg
+	}
+}
+
+// WithZstdDictionary specifies a zstd dictionary to use when compressing
+// or decompressing the data buffers with ZSTD. Readers need it to decode
+// files whose buffers were compressed with that dictionary.
+func WithZstdDictionary(dict []byte) Option {
+	return func(cfg *config) {
+		cfg.zstdDict = dict
+	}
+}
+
+// WithRecordMemoryLimit specifies the maximum number of bytes the reader may
+// allocate for the buffers of a single record. Reading a record that would
+// need more returns an error instead. A limit <= 0 means no limit.
+func WithRecordMemoryLimit(bytes int64) Option {
+	return func(cfg *config) {
+		cfg.memLimit = bytes
+	}
+}
+
+// WithPerRecordArena tells the file reader to allocate the buffers of each
+// record returned by RecordAt from a dedicated BumpAllocator, instead of the
+// allocator given with WithAllocator. The arena is reset when the record is
+// released.
+func WithPerRecordArena() Option {
+	return func(cfg *config) {
+		cfg.arena = true
+	}
+}
+
+// WithDecimalRescale tells readers to convert the values of the top-level
+// Decimal128 field named field to the given precision and scale. Reading a
+// value that does not fit the new precision, or that would need rounding to
+// fit the new scale, returns an error.
+func WithDecimalRescale(field string, precision, scale int32) Option {
+	return func(cfg *config) {
+		if cfg.rescale == nil {
+			cfg.rescale = make(map[string]*arrow.Decimal128Type)
+		}
+		cfg.rescale[field] = &arrow.Decimal128Type{Precision: precision, Scale: scale}
+	}
+}
+
+// WithTimestampToUTC tells readers to declare all zoned timestamp columns,
+// including nested ones, in the UTC time zone. Zoned timestamps are stored as
+// instants in UTC, so the values themselves are left unchanged.
+func WithTimestampToUTC() Option {
+	return func(cfg *config) {
+		cfg.utc = true
+	}
+}
+
+// WithLazyColumns tells the file reader to return records from RecordAt
+// whose columns are only loaded when first accessed. Loading a column of
+// such a record is not safe for concurrent use: callers must synchronize
+// calls to Column, Columns, NewSlice and MarshalJSON. RecordAt checks that
+// the buffers of the record lie within its body and fit within the limit of
+// WithRecordMemoryLimit: Column then panics only if a buffer cannot be
+// decompressed, or if a column fails the checks of WithStrictValidation or
+// its read-time conversion.
+func WithLazyColumns() Option {
+	return func(cfg *config) {
+		cfg.lazy = true
+	}
+}
+
+// WithBodyDecryptor specifies a function the file reader calls to decrypt
+// the body of each record batch, before any decompression. The function is
+// given the index of the record, as passed to RecordAt, and the encrypted
+// body. Metadata, schema and footer are expected in clear.
+func WithBodyDecryptor(fn func(blockIndex int, ciphertext []byte) ([]byte, error)) Option {
+	return func(cfg *config) {
+		cfg.decrypt = fn
+	}
+}
+
+// WithFeatherCompat tells the file reader to accept the quirks of files
+// written by some older Feather V2 writers: message blocks starting on a
+// 4-byte rather than an 8-byte boundary, and bodies compressed as declared
+// by the "ARROW:experimental_compression" custom metadata of their message,
+// as written by Arrow 0.17, rather than by their body compression metadata.
+// The length of the metadata and body of the blocks must still be aligned.
+func WithFeatherCompat() Option {
+	return func(cfg *config) {
+		cfg.feather = true
+	}
+}
+
+// WithRelaxedAlignment tells the file reader to accept files whose message
+// blocks are aligned on 4-byte boundaries rather than 8-byte ones, as written
+// by some non-conforming producers. Blocks that are not even 4-byte aligned
+// are still rejected.
+func WithRelaxedAlignment() Option {
+	return func(cfg *config) {
+		cfg.relaxed = true
+	}
+}
+
+// WithTrustedInput tells the file reader to skip the alignment checks of the
+// message blocks listed by the footer, for files known to be well-formed.
+// The reader still fails cleanly on blocks it cannot read, but a misaligned
+// block of a corrupted file may then be decoded from the wrong bytes instead
+// of being rejected: only use it for files produced by a trusted writer.
+func WithTrustedInput() Option {
+	return func(cfg *config) {
+		cfg.trusted = true
+	}
+}
+
+// WithBufferTraceHook specifies a function readers call after loading each
+// buffer of a record, describing how the buffer was read.
+func WithBufferTraceHook(fn func(BufferTrace)) Option {
+	return func(cfg *config) {
+		cfg.trace = fn
+	}
+}
+
+// WithFieldCast tells readers to cast the values of the top-level field
+// named field to type target. Supported casts are integer widening,
+// timestamp unit changes and string to/from binary. Casting timestamps to a
+// coarser unit returns an error for values that would lose precision.
+func WithFieldCast(field string, target arrow.DataType) Option {
+	return func(cfg *config) {
+		if cfg.casts == nil {
+			cfg.casts = make(map[string]arrow.DataType)
+		}
+		cfg.casts[field] = target
+	}
+}
+
+// WithTargetSchema tells readers to project the records they read onto
+// schema, matching their fields by name: fields absent from schema are
+// dropped and the remaining ones reordered as in schema. Target fields
+// absent from the file or stream are filled with nulls if fillMissing is
+// true, provided they are nullable; reading fails otherwise, as it does for
+// fields whose type differs from the target one, after any WithFieldCast.
+// Records are decoded eagerly, even with WithLazyColumns.
+func WithTargetSchema(schema *arrow.Schema, fillMissing bool) Option {
+	return func(cfg *config) {
+		cfg.target = schema
+		cfg.fillMissing = fillMissing
+	}
+}
+
+// WithReadAhead tells FileReader.Read to read and decode, in background
+// goroutines, the n records following the one it returns, so that
+// sequential scans over a slow reader do not wait for each record in turn.
+// Records read ahead are kept in memory until they are returned by Read or
+// the reader is closed. The default, 0, disables reading ahead.
+func WithReadAhead(n int) Option {
+	return func(cfg *config) {
+		cfg.readAhead = n
+	}
+}
+
+// WithDuplicateFieldPolicy tells readers how to handle top-level fields of
+// the schema sharing the same name. Renaming applies before the other
+// options selecting fields by name, such as WithFieldCast, which then see
+// the renamed fields. The default is DuplicateFieldAllow.
+func WithDuplicateFieldPolicy(policy DuplicateFieldPolicy) Option {
+	return func(cfg *config) {
+		cfg.dups = policy
+	}
+}
+
+// WithFooterDigest tells FileReader to verify, when the file is opened, the
+// digest stored in the footer custom metadata under FooterDigestKey against
+// the digest of the bytes preceding the footer, computed with a hash
+// returned by newHash. Files without a stored digest are rejected.
+func WithFooterDigest(newHash func() hash.Hash) Option {
+	return func(cfg *config) {
+		cfg.digest = newHash
+	}
+}
+
+// WithColumnAllocator tells readers to allocate the buffers of the col-th
+// top-level column of the records they decode with mem, instead of the
+// allocator set with WithAllocator. This allows, for instance, binding
+// columns to memory of specific NUMA nodes. Columns of records read with
+// WithPerRecordArena are allocated with mem, outside of the arena.
+func WithColumnAllocator(col int, mem memory.Allocator) Option {
+	return func(cfg *config) {
+		if cfg.colMem == nil {
+			cfg.colMem = make(map[int]memory.Allocator)
+		}
+		cfg.colMem[col] = mem
+	}
+}
+
+// WithMaxRetainedBytes bounds the memory held by the records a FileReader
+// has returned and that were not released yet. Once n bytes or more are
+// retained, reading a record with a context, as with RecordAtContext, blocks
+// until enough records are released, or fails with the context's error.
+// Reads without a context, as with RecordAt, Record and Read, fail at once
+// instead: they could otherwise block forever. Records are read whole, so
+// the memory retained may exceed n by the size of one record. Only memory
+// obtained from the reader's allocator is counted: columns allocated with
+// WithColumnAllocator or from a per-record arena are not.
+func WithMaxRetainedBytes(n int64) Option {
+	return func(cfg *config) {
+		cfg.maxRetained = n
+	}
+}
+
+// WithRecordFilter tells FileReader.Read to skip the record batches whose
+// statistics, stored in the custom metadata of their message under the keys
+// returned by RecordStatKey, are rejected by pred. pred is given the
+// statistics of the batch by column name. Skipped batches are decided on
+// their metadata alone: their bodies are never read. Batches without
+// statistics are always returned. Records read ahead with WithReadAhead are
+// read before being filtered.
+func WithRecordFilter(pred func(stats map[string]ColumnStats) bool) Option {
+	return func(cfg *config) {
+		cfg.filter = pred
+	}
+}
+
+// WithCoalesceReads tells FileReader to fetch the metadata and body of a
+// record batch with a single ReadAt, rather than one for each. The fetched
+// span extends over the record batches following it in the file, as long as
+// they are separated by gaps smaller than gapThreshold bytes and the span
+// stays within 4MiB: reading these records is then served from memory.
+// This reduces the number of reads made against sources with a high
+// per-read cost, such as remote objects.
+func WithCoalesceReads(gapThreshold int64) Option {
+	return func(cfg *config) {
+		if gapThreshold < 0 {
+			gapThreshold = 0
+		}
+		cfg.coalesce = gapThreshold
+	}
+}
+
+// WithExtensionTypeResolver specifies a function readers call for the
+// fields of a schema declaring an extension type that is not registered
+// with arrow.RegisterExtensionType. The function is given the extension name,
+// the storage type of the field and the serialized extension metadata, and
+// returns the extension type of the field. Returning a nil type keeps the
+// storage type along with the extension metadata of the field, which is what
+// readers do for unregistered extension types when no resolver is given.
+func WithExtensionTypeResolver(fn func(name string, storage arrow.DataType, metadata string) (arrow.ExtensionType, error)) Option {
+	return func(cfg *config) {
+		cfg.resolveExt = fn
+	}
+}
+
+// WithLazyFooter tells FileReader not to read the entries of the record
+// batch and dictionary block lists of the footer when the file is opened,
+// but to read each entry on demand, when the corresponding record or
+// dictionary is accessed. This saves reading and copying large footers of
+// files holding many record batches, when only a few of them are read.
+// Options needing all the entries, such as WithSortedBlocks and
+// WithCoalesceReads, still read them all when the file is opened.
+func WithLazyFooter() Option {
+	return func(cfg *config) {
+		cfg.lazyFooter = true
+	}
+}
+
+// WithRecordPostHook specifies a function FileReader calls after reading
+// each record, whether through RecordAt, Record, Read, a ColumnReader or
+// reading ahead.
+// The function is given the index of the record, the record, or nil if
+// reading it failed, the number of bytes read from the file for its message
+// and the error, if any. The record must not be retained past the call.
+// The function may be called concurrently by concurrent reads.
+func WithRecordPostHook(fn func(index int, rec arrow.Record, bytesRead int64, err error)) Option {
+	return func(cfg *config) {
+		cfg.postHook = fn
+	}
+}
+
+// WithProgressReporter specifies a function FileReader calls after decoding
+// each record of a bulk read, through Table, RecordRange or TailRecords.
+// The function is given the number of records decoded so far, the number of
+// records of the read and the number of bytes read from the file for them.
+// Calls are serialized, and the progress they report never decreases.
+func WithProgressReporter(fn func(recordsDone, recordsTotal int, bytesDone int64)) Option {
+	return func(cfg *config) {
+		cfg.progress = fn
+	}
+}
+
+// WithMaxConcurrentIO tells the file reader to issue at most n concurrent
+// ReadAt calls to the underlying reader, whatever the number of records read
+// concurrently, read ahead or fetched by coalesced reads. Reads wait for one
+// of the reads in flight to complete, or for their context to be done.
+// The default, 0, does not limit concurrent reads.
+func WithMaxConcurrentIO(n int) Option {
+	return func(cfg *config) {
+		cfg.maxIO = n
+	}
+}
+
+// WithSpillThreshold specifies the size in bytes above which
+// NewFileReaderFromCompressed writes the decompressed file to a temporary
+// file instead of keeping it in memory. A negative threshold always spills
+// to disk. The default is 64 MiB.
+func WithSpillThreshold(n int64) Option {
+	return func(cfg *config) {
+		cfg.spill = n
+	}
+}
+
+// WithAllocatorResetFunc specifies a function called by FileReader.Close
+// once the reader has released all of its buffers, such as to reset the
+// arena allocator given with WithAllocator between files. It is called once,
+// by the first call to Close. The reader cannot release records still held
+// by the caller, which must be released before Close if fn reclaims their
+// memory.
+func WithAllocatorResetFunc(fn func()) Option {
+	return func(cfg *config) {
+		cfg.allocReset = fn
+	}
+}
+
+// WithRecordRing tells the file reader to allocate the buffers of the
+// records returned by RecordAt, RecordAtContext and Record from a ring of k
+// reused buffer sets, bounding the memory held by records to that of k
+// records. A record holds its slot of the ring until it, and its slices,
+// are released: only then is the memory of the slot reused by a later
+// record. Reading a record fails when all k slots are held by unreleased
+// records, and accessing a released record panics. Arrays obtained from a
+// record are not guarded and must not be used once it was released.
+// Records read by other methods, such as Table, are not allocated from the
+// ring. Lazy column loading and per-record arenas do not apply to records
+// allocated from the ring, and the ring cannot be combined with
+// WithReadAhead. The default, 0, does not use a ring.
+func WithRecordRing(k int) Option {
+	return func(cfg *config) {
+		cfg.ring = k
+	}
+}
+
+// WithBufferAllocatorSelector specifies a function selecting the allocator
+// of each buffer loaded by readers, from its role and the field of the array
+// it belongs to, such as to place validity bitmaps and values in memory with
+// different lifetimes. Children of nested fields are passed their own field.
+// Returning nil selects the default allocator. The selector does not apply
+// to compressed buffers decoded ahead with WithParallelDecompress.
+func WithBufferAllocatorSelector(fn func(role BufferRole, field arrow.Field) memory.Allocator) Option {
+	return func(cfg *config) {
+		cfg.bufferMem = fn
+	}
+}
+
+// WithSchemaMetadataTransform specifies a function readers call with the
+// custom metadata of the schema they read, such as to strip or replace stale
+// or sensitive keys. The returned metadata replaces it in the schema exposed
+// by the reader and in the schema of all the records it produces.
+func WithSchemaMetadataTransform(fn func(md arrow.Metadata) arrow.Metadata) Option {
+	return func(cfg *config) {
+		cfg.schemaMeta = fn
+	}
+}
+
+// WithFieldMetadataTransform is like WithSchemaMetadataTransform for the
+// custom metadata of fields. The function is called for every field of the
+// schema, including the children of struct and list fields, with the path of
+// child indices of the field, as taken by FileReader.FieldMetadataByPath.
+// The children of map fields are left as they are.
+// The function is called once extension types are resolved: removing the
+// extension keys of a field does not change its type.
+func WithFieldMetadataTransform(fn func(path []int, field arrow.Field) arrow.Metadata) Option {
+	return func(cfg *config) {
+		cfg.fieldMeta = fn
+	}
+}
+
+// WithExternalBodyReader tells FileReader to read the bodies of record
+// batches from the readers returned by fn rather than from the file, which
+// then only holds their metadata, for layouts keeping metadata and bodies
+// in separate files. fn is given the index of a record, as taken by
+// RecordAt, and returns a reader holding its body at offset 0, along with
+// the length of the body, which must match the one declared by the block of
+// the record in the footer.
+func WithExternalBodyReader(fn func(blockIndex int) (io.ReaderAt, int64, error)) Option {
+	return func(cfg *config) {
+		cfg.extBody = fn
+	}
+}
+
+// WithLeakCheck tells FileReader to keep track of the records it hands out
+// and of the memory allocated for them, so that Close reports an error
+// naming how many records and bytes have not been released. Records owned
+// by the reader, such as the one returned by Record, are not reported.
+// This is meant for debugging, as tracking adds a cost to every allocation.
+func WithLeakCheck() Option {
+	return func(cfg *config) {
+		cfg.leakCheck = true
+	}
+}
+
+// WithStrictValidation tells the reader to perform additional, possibly
+// costly, checks on the data it loads, such as verifying that maps declared
+// with sorted keys do have their keys sorted, and that offsets and buffer
+// sizes are consistent with the array lengths.
+func WithStrictValidation() Option {
+	return func(cfg *config) {
+		cfg.strict = true
+	}
+}
+
 // WithCompressConcurrency specifies a number of goroutines to spin up for
 // concurrent compression of the body buffers when writing compress IPC records.
 // If n <= 1 then compression will be done serially without goroutine
@@ -134,11 +640,60 @@ func WithCompressConcurrency(n int) Option {
 	}
 }
 
+// WithParallelDecompress specifies a number of goroutines readers spin up to
+// decompress the body buffers of each compressed record concurrently, before
+// its arrays are loaded. If n <= 1, the default, buffers are decompressed
+// serially as they are loaded. Allocators given to the reader must then be
+// safe for concurrent use. It has no effect on columns loaded on demand with
+// WithLazyColumns.
+func WithParallelDecompress(n int) Option {
+	return func(cfg *config) {
+		cfg.decompressNP = n
+	}
+}
+
+// WithByteSwap specifies whether readers should byte-swap the buffers of a
+// file or stream whose endianness does not match the host's. When false (the
+// default), reading such a file or stream returns an error.
+func WithByteSwap(v bool) Option {
+	return func(cfg *config) {
+		cfg.byteSwap = v
+	}
+}
+
+// withHostEndianness overrides the endianness readers assume for the host,
+// such as to read native data as if written on a host of the other
+// endianness.
+func withHostEndianness(e flatbuf.Endianness) Option {
+	return func(cfg *config) {
+		cfg.host = e
+	}
+}
+
+// WithAllowVersions specifies metadata versions the file reader should
+// accept in addition to the supported ones (V4 and V5). Files with other
+// metadata versions may not decode correctly.
+func WithAllowVersions(versions ...MetadataVersion) Option {
+	return func(cfg *config) {
+		cfg.versions = append(cfg.versions, versions...)
+	}
+}
+
+// WithSortedBlocks tells the file reader to index record batches by their
+// physical position in the file rather than by their position in the footer.
+// This is useful for files whose footer lists blocks out of order.
+func WithSortedBlocks() Option {
+	return func(cfg *config) {
+		cfg.sortBlocks = true
+	}
+}
+
 var (
 	_ arrio.Reader = (*Reader)(nil)
 	_ arrio.Writer = (*Writer)(nil)
 	_ arrio.Reader = (*FileReader)(nil)
 	_ arrio.Writer = (*FileWriter)(nil)
+	_ arrio.Reader = (*RecordReader)(nil)
 
 	_ arrio.ReaderAt = (*FileReader)(nil)
 )
@@ -0,0 +1,57 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package ipc
+
+import (
+	"context"
+	"iter"
+
+	"github.com/apache/arrow/go/v8/arrow"
+)
+
+// All returns an iterator over the records of the file, in the order of
+// RecordAt, reading them with ctx. Each record is released once the loop
+// body it is yielded to returns: callers must call Retain() to keep it.
+// Iteration stops after yielding the first error, with a nil record, such
+// as the context's error when ctx is done.
+func (f *FileReader) All(ctx context.Context) iter.Seq2[arrow.Record, error] {
+	return func(yield func(arrow.Record, error) bool) {
+		for i := 0; i < f.NumRecords(); i++ {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			rec, err := f.RecordAtContext(ctx, i)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yieldRecord(yield, rec) {
+				return
+			}
+		}
+	}
+}
+
+// yieldRecord yields rec, releasing it when yield returns or panics.
+func yieldRecord(yield func(arrow.Record, error) bool, rec arrow.Record) bool {
+	defer rec.Release()
+	return yield(rec, nil)
+}
@@ -0,0 +1,100 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+// +build go1.23
+
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderAll(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 7, 1, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	t.Run("full", func(t *testing.T) {
+		i := 0
+		for rec, err := range r.All(context.Background()) {
+			require.NoError(t, err)
+			assert.Truef(t, array.RecordEqual(recs[i], rec), "record %d", i)
+			i++
+		}
+		assert.Equal(t, len(recs), i)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		before := r.Stats().Records
+		i := 0
+		for _, err := range r.All(context.Background()) {
+			require.NoError(t, err)
+			if i++; i == 2 {
+				break
+			}
+		}
+		assert.Equal(t, 2, i)
+		assert.Equal(t, before+2, r.Stats().Records, "no record should be read after break")
+	})
+
+	t.Run("retain", func(t *testing.T) {
+		var kept []arrow.Record
+		for rec, err := range r.All(context.Background()) {
+			require.NoError(t, err)
+			rec.Retain()
+			kept = append(kept, rec)
+		}
+		defer releaseRecords(kept)
+
+		require.Len(t, kept, len(recs))
+		for i, rec := range kept {
+			assert.Truef(t, array.RecordEqual(recs[i], rec), "record %d", i)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		n := 0
+		for rec, err := range r.All(ctx) {
+			if n++; n == 1 {
+				require.NoError(t, err)
+				require.NotNil(t, rec)
+				cancel()
+				continue
+			}
+			assert.Nil(t, rec)
+			assert.ErrorIs(t, err, context.Canceled)
+		}
+		assert.Equal(t, 2, n)
+	})
+}
@@ -0,0 +1,233 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/internal/debug"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// lazyRecord is a record whose columns are loaded from the body of its
+// message on first access. The layout of the body, and the memory limit,
+// are checked when the record is created: loading a column may then only
+// fail to decompress a buffer, or fail the checks of WithStrictValidation
+// and the read-time conversions of the options.
+//
+// Loading columns is not safe for concurrent use.
+type lazyRecord struct {
+	refCount int64
+
+	schema *arrow.Schema // schema of the record
+	fields []arrow.Field // fields to load, before read-time conversions
+	rows   int64
+	cols   []arrow.Array // loaded columns, nil until first accessed
+
+	msg   *Message
+	md    flatbuf.RecordBatch
+	mem   memory.Allocator
+	opts  recordOptions
+	codec Decompressor // decompressor of the body, nil if uncompressed
+	used  int64        // bytes allocated for the loaded columns, against the memory limit
+
+	// index of the first field node and buffer of each column.
+	nodes   []int
+	buffers []int
+}
+
+func newLazyRecord(schema *arrow.Schema, msg *Message, mem memory.Allocator, opts recordOptions) (*lazyRecord, error) {
+	rec := &lazyRecord{
+		refCount: 1,
+		msg:      msg,
+		mem:      mem,
+		opts:     opts,
+	}
+	initFB(&rec.md, msg.msg.Header)
+	rec.rows = rec.md.Length()
+
+	opts, err := opts.forMessage(msg.msg, &rec.md)
+	if err != nil {
+		return nil, err
+	}
+	rec.opts = opts
+
+	if got, want := rec.md.NodesLength(), numFieldNodes(schema); got != want {
+		return nil, xerrors.Errorf("arrow/ipc: record batch has %d field nodes, schema requires %d", got, want)
+	}
+
+	if opts.utc {
+		schema = utcSchema(schema)
+	}
+	out, err := convertedSchema(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.schema = out
+	rec.fields = schema.Fields()
+	rec.cols = make([]arrow.Array, len(rec.fields))
+	rec.nodes = make([]int, len(rec.fields))
+	rec.buffers = make([]int, len(rec.fields))
+	node, buffer := 0, 0
+	for i, field := range rec.fields {
+		rec.nodes[i], rec.buffers[i] = node, buffer
+		node += numTypeNodes(field.Type)
+		buffer += numTypeBuffers(field.Type)
+	}
+	if got := rec.md.BuffersLength(); got < buffer {
+		return nil, xerrors.Errorf("arrow/ipc: record batch has %d buffers, schema requires %d", got, buffer)
+	}
+
+	rec.codec, err = bodyDecompressor(&rec.md, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLazyBody(&rec.md, msg.body.Bytes(), rec.codec != nil, opts.memLimit); err != nil {
+		if rec.codec != nil {
+			rec.codec.Close()
+		}
+		return nil, err
+	}
+
+	msg.Retain()
+	return rec, nil
+}
+
+// checkLazyBody checks that the buffers described by md lie within body and
+// that, once decoded, they fit within the memory limit, if > 0. The decoded
+// size of compressed buffers is taken from their uncompressed size prefix.
+func checkLazyBody(md *flatbuf.RecordBatch, body []byte, compressed bool, limit int64) error {
+	var (
+		buf  flatbuf.Buffer
+		size int64
+	)
+	for i := 0; i < md.BuffersLength(); i++ {
+		md.Buffers(&buf, i)
+		off, n := buf.Offset(), buf.Length()
+		if off < 0 || n < 0 || off > int64(len(body)) || n > int64(len(body))-off {
+			return xerrors.Errorf("arrow/ipc: buffer %d [offset=%d, length=%d] lies outside of the body (length=%d)", i, off, n, len(body))
+		}
+		if compressed && n > 0 {
+			if n < int64(arrow.Int64SizeBytes) {
+				return xerrors.Errorf("arrow/ipc: compressed buffer %d too small (size=%d)", i, n)
+			}
+			switch v := int64(binary.LittleEndian.Uint64(body[off:])); {
+			case v == -1:
+				n -= int64(arrow.Int64SizeBytes)
+			case v < 0:
+				return xerrors.Errorf("arrow/ipc: invalid uncompressed size %d of buffer %d", v, i)
+			default:
+				n = v
+			}
+		}
+		size += n
+		if limit > 0 && size > limit {
+			return xerrors.Errorf("%w (limit=%d, requested=%d)", errRecordMemoryLimit, limit, size)
+		}
+	}
+	return nil
+}
+
+// load loads the i-th column.
+func (rec *lazyRecord) load(i int) (arrow.Array, error) {
+	mem := rec.opts.columnAllocator(i, rec.mem)
+	ctx := newArrayLoaderContext(&rec.md, bytes.NewReader(rec.msg.body.Bytes()), rec.codec, mem, rec.opts)
+	ctx.ifield = rec.nodes[i]
+	ctx.ibuffer = rec.buffers[i]
+	ctx.src.used = rec.used
+
+	field := rec.fields[i]
+	arr, err := ctx.loadField(field)
+	if err != nil {
+		return nil, err
+	}
+	rec.used = ctx.src.used
+	return convertField(mem, field, arr, rec.opts)
+}
+
+func (rec *lazyRecord) Retain() {
+	atomic.AddInt64(&rec.refCount, 1)
+}
+
+func (rec *lazyRecord) Release() {
+	debug.Assert(atomic.LoadInt64(&rec.refCount) > 0, "too many releases")
+
+	if atomic.AddInt64(&rec.refCount, -1) == 0 {
+		for i, col := range rec.cols {
+			if col != nil {
+				col.Release()
+			}
+			rec.cols[i] = nil
+		}
+		rec.msg.Release()
+		rec.msg = nil
+		if rec.codec != nil {
+			rec.codec.Close()
+			rec.codec = nil
+		}
+	}
+}
+
+func (rec *lazyRecord) Schema() *arrow.Schema   { return rec.schema }
+func (rec *lazyRecord) NumRows() int64          { return rec.rows }
+func (rec *lazyRecord) NumCols() int64          { return int64(len(rec.cols)) }
+func (rec *lazyRecord) ColumnName(i int) string { return rec.schema.Field(i).Name }
+
+// Column returns the i-th column, loading it if needed.
+// Column panics if the column cannot be loaded, see lazyRecord.
+func (rec *lazyRecord) Column(i int) arrow.Array {
+	if rec.cols[i] == nil {
+		arr, err := rec.load(i)
+		if err != nil {
+			panic(err)
+		}
+		rec.cols[i] = arr
+	}
+	return rec.cols[i]
+}
+
+// Columns returns all the columns, loading them if needed.
+// Columns panics if a column cannot be loaded, see lazyRecord.
+func (rec *lazyRecord) Columns() []arrow.Array {
+	for i := range rec.cols {
+		rec.Column(i)
+	}
+	return rec.cols
+}
+
+func (rec *lazyRecord) NewSlice(i, j int64) arrow.Record {
+	full := array.NewRecord(rec.schema, rec.Columns(), rec.rows)
+	defer full.Release()
+	return full.NewSlice(i, j)
+}
+
+func (rec *lazyRecord) MarshalJSON() ([]byte, error) {
+	full := array.NewRecord(rec.schema, rec.Columns(), rec.rows)
+	defer full.Release()
+	return full.MarshalJSON()
+}
+
+var (
+	_ arrow.Record = (*lazyRecord)(nil)
+)
@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderLazyColumns(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 4)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	var buffers []int
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithLazyColumns(),
+		WithBufferTraceHook(func(bt BufferTrace) { buffers = append(buffers, bt.Index) }))
+	require.NoError(t, err)
+	defer r.Close()
+
+	rec, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer rec.Release()
+
+	assert.Empty(t, buffers)
+	assert.Equal(t, int64(4), rec.NumRows())
+	assert.Equal(t, int64(2), rec.NumCols())
+	assert.Equal(t, "str", rec.ColumnName(1))
+	assert.Empty(t, buffers)
+
+	// the i64 column (buffers 0 and 1) is never read.
+	assert.True(t, array.ArrayEqual(recs[0].Column(1), rec.Column(1)))
+	assert.Equal(t, []int{3, 4}, buffers)
+	rec.Column(1)
+	assert.Equal(t, []int{3, 4}, buffers)
+
+	assert.True(t, array.RecordEqual(recs[0], rec))
+	assert.Equal(t, []int{3, 4, 0, 1}, buffers)
+}
+
+func TestFileReaderLazyColumnsCorruptBody(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 100)
+	defer releaseRecords(recs)
+
+	for _, tc := range []struct {
+		name   string
+		opts   []Option
+		buffer int
+		mutate func(buf *flatbuf.Buffer)
+		err    string
+	}{
+		{
+			name:   "offset",
+			buffer: 4,
+			mutate: func(buf *flatbuf.Buffer) { buf.MutateOffset(1 << 20) },
+			err:    "buffer 4 [offset=1048576, length=",
+		},
+		{
+			name:   "negative-offset",
+			buffer: 3,
+			mutate: func(buf *flatbuf.Buffer) { buf.MutateOffset(-8) },
+			err:    "buffer 3 [offset=-8, length=",
+		},
+		{
+			name:   "length",
+			buffer: 1,
+			mutate: func(buf *flatbuf.Buffer) { buf.MutateLength(buf.Length() + 1<<20) },
+			err:    "lies outside of the body",
+		},
+		{
+			name:   "compressed-prefix",
+			opts:   []Option{WithLZ4()},
+			buffer: 1,
+			mutate: func(buf *flatbuf.Buffer) { buf.MutateLength(4) },
+			err:    "compressed buffer 1 too small (size=4)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := writeTestFile(t, recs, tc.opts...)
+			mutateRecordMessage(t, raw, 0, func(msg *flatbuf.Message) {
+				var (
+					md  flatbuf.RecordBatch
+					buf flatbuf.Buffer
+				)
+				initFB(&md, msg.Header)
+				require.True(t, md.Buffers(&buf, tc.buffer))
+				tc.mutate(&buf)
+			})
+
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithLazyColumns())
+			require.NoError(t, err)
+			defer r.Close()
+
+			// the corrupt body is reported by RecordAt, not by a panic
+			// once the column is accessed.
+			rec, err := r.RecordAt(0)
+			assert.Nil(t, rec)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/internal/debug"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// leakTracker counts the records handed out by a reader, and the bytes
+// allocated for them, that have not been released.
+type leakTracker struct {
+	mem     *retainedAllocator
+	records int64 // number of unreleased records, accessed atomically
+}
+
+func newLeakTracker(mem memory.Allocator) *leakTracker {
+	return &leakTracker{mem: newRetainedAllocator(mem, 0)}
+}
+
+// track returns rec, counted as unreleased until its last release.
+func (t *leakTracker) track(rec arrow.Record) arrow.Record {
+	atomic.AddInt64(&t.records, 1)
+	return &trackedRecord{Record: rec, refCount: 1, t: t}
+}
+
+// check returns an error if any record or byte has not been released.
+func (t *leakTracker) check() error {
+	n, size := atomic.LoadInt64(&t.records), t.mem.Len()
+	if n == 0 && size == 0 {
+		return nil
+	}
+	return xerrors.Errorf("arrow/ipc: %d records and %d bytes read from the file were not released", n, size)
+}
+
+// trackedRecord is a record counted by a leakTracker.
+type trackedRecord struct {
+	arrow.Record
+	refCount int64
+	t        *leakTracker
+}
+
+func (rec *trackedRecord) Retain() {
+	atomic.AddInt64(&rec.refCount, 1)
+}
+
+func (rec *trackedRecord) Release() {
+	debug.Assert(atomic.LoadInt64(&rec.refCount) > 0, "too many releases")
+
+	if atomic.AddInt64(&rec.refCount, -1) == 0 {
+		rec.Record.Release()
+		rec.Record = nil
+		atomic.AddInt64(&rec.t.records, -1)
+	}
+}
+
+var (
+	_ arrow.Record = (*trackedRecord)(nil)
+)
@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderLeakCheck(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5, 7)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"eager", nil},
+		{"lazy", []Option{WithLazyColumns()}},
+		{"arena", []Option{WithPerRecordArena()}},
+		{"read-ahead", []Option{WithReadAhead(2)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]Option{WithAllocator(mem), WithLeakCheck()}, tc.opts...)
+
+			t.Run("released", func(t *testing.T) {
+				r, err := NewFileReaderFromBytes(raw, opts...)
+				require.NoError(t, err)
+
+				rec, err := r.RecordAt(0)
+				require.NoError(t, err)
+				rec.Retain()
+				rec.Release()
+				rec.Release()
+
+				// records owned by the reader are not leaks.
+				_, err = r.Record(1)
+				require.NoError(t, err)
+				_, err = r.Read()
+				require.NoError(t, err)
+				assert.NoError(t, r.Close())
+			})
+
+			t.Run("leaked", func(t *testing.T) {
+				r, err := NewFileReaderFromBytes(raw, opts...)
+				require.NoError(t, err)
+
+				leaked, err := r.RecordAt(2)
+				require.NoError(t, err)
+				leaked.Column(0)
+				rec, err := r.RecordAt(1)
+				require.NoError(t, err)
+				rec.Release()
+
+				err = r.Close()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "1 records")
+
+				leaked.Release()
+			})
+		})
+	}
+}
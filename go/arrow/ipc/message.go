@@ -188,6 +188,11 @@ func (r *messageReader) Message() (*Message, error) {
 	var buf = make([]byte, 4)
 	_, err := io.ReadFull(r.r, buf)
 	if err != nil {
+		if err == io.EOF {
+			// the EOS marker is optional: a stream may simply end
+			// on a message boundary.
+			return nil, io.EOF
+		}
 		return nil, xerrors.Errorf("arrow/ipc: could not read continuation indicator: %w", err)
 	}
 	var (
@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"io"
 	"sort"
+	"strconv"
 
 	"github.com/apache/arrow/go/v8/arrow"
 	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
@@ -40,6 +41,13 @@ const (
 	ExtensionTypeKeyName     = "ARROW:extension:name"
 	ExtensionMetadataKeyName = "ARROW:extension:metadata"
 
+	// constants for the metadata keys describing the dictionary encoding of
+	// the placeholder fields returned by ReadSchemaOnly. They are specific to
+	// this package, the ARROW: prefix being reserved by the format.
+	DictionaryIDKeyName        = "arrow/ipc:dictionary:id"
+	DictionaryIndexTypeKeyName = "arrow/ipc:dictionary:index_type"
+	DictionaryOrderedKeyName   = "arrow/ipc:dictionary:ordered"
+
 	// ARROW-109: We set this number arbitrarily to help catch user mistakes. For
 	// deeply nested schemas, it is expected the user will indicate explicitly the
 	// maximum allowed recursion depth
@@ -78,39 +86,64 @@ func fileBlocksToFB(b *flatbuffers.Builder, blocks []fileBlock, start startVecFu
 }
 
 func (blk fileBlock) NewMessage() (*Message, error) {
-	var (
-		err error
-		buf []byte
-		r   = blk.section()
-	)
+	r := blk.section()
+	meta, err := blk.readMeta(r)
+	if err != nil {
+		return nil, err
+	}
 
-	buf = make([]byte, blk.Meta)
+	buf := make([]byte, blk.Body)
 	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not read message body: %w", err)
+	}
+	body := memory.NewBufferBytes(buf)
+
+	return NewMessage(meta, body), nil
+}
+
+// readMeta reads the metadata of the block's message from r, positioned at
+// the start of the block.
+func (blk fileBlock) readMeta(r io.Reader) (*memory.Buffer, error) {
+	buf := make([]byte, blk.Meta)
+	_, err := io.ReadFull(r, buf)
 	if err != nil {
 		return nil, xerrors.Errorf("arrow/ipc: could not read message metadata: %w", err)
 	}
 
-	prefix := 0
-	switch binary.LittleEndian.Uint32(buf) {
-	case 0:
+	prefix, size, err := messageFraming(buf)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewBufferBytes(buf[prefix : prefix+size]), nil
+}
+
+// messageFraming returns the length of the prefix of the encapsulated
+// message metadata buf, and the length of its flatbuffer Message, as
+// declared by the prefix. Both the current framing, a continuation marker
+// followed by the length, and the legacy one, the length alone, are
+// detected from the leading bytes.
+func messageFraming(buf []byte) (prefix, size int, err error) {
+	if len(buf) < 4 {
+		return 0, 0, xerrors.Errorf("arrow/ipc: message metadata too short for its length prefix (size=%d)", len(buf))
+	}
+
+	switch v := binary.LittleEndian.Uint32(buf); v {
 	case kIPCContToken:
-		prefix = 8
+		if len(buf) < 8 {
+			return 0, 0, xerrors.Errorf("arrow/ipc: message metadata too short for its length prefix (size=%d)", len(buf))
+		}
+		prefix, size = 8, int(binary.LittleEndian.Uint32(buf[4:]))
 	default:
 		// ARROW-6314: backwards compatibility for reading old IPC
 		// messages produced prior to version 0.15.0
-		prefix = 4
+		prefix, size = 4, int(v)
 	}
 
-	meta := memory.NewBufferBytes(buf[prefix:]) // drop buf-size already known from blk.Meta
-
-	buf = make([]byte, blk.Body)
-	_, err = io.ReadFull(r, buf)
-	if err != nil {
-		return nil, xerrors.Errorf("arrow/ipc: could not read message body: %w", err)
+	if size == 0 || size > len(buf)-prefix {
+		return 0, 0, xerrors.Errorf("arrow/ipc: invalid message metadata length %d (size=%d)", size, len(buf))
 	}
-	body := memory.NewBufferBytes(buf)
-
-	return NewMessage(meta, body), nil
+	return prefix, size, nil
 }
 
 func (blk fileBlock) section() io.Reader {
@@ -194,9 +227,41 @@ func fieldFromFB(field *flatbuf.Field, memo *dictMemo) (arrow.Field, error) {
 			return o, xerrors.Errorf("arrow/ipc: could not convert field type: %w", err)
 		}
 	default:
-		panic("not implemented") // FIXME(sbinet)
+		if memo.placeholders {
+			return placeholderFromFB(field, encoding)
+		}
+		// FIXME(sbinet): implement dictionary-encoded fields.
+		return o, xerrors.Errorf("arrow/ipc: dictionary-encoded field %q not supported", o.Name)
+	}
+
+	return o, nil
+}
+
+// placeholderFromFB returns the dictionary-encoded field with the type of its
+// dictionary values, its encoding being described by its metadata under the
+// DictionaryIDKeyName, DictionaryIndexTypeKeyName and DictionaryOrderedKeyName
+// keys, as arrow has no dictionary data type to carry it.
+func placeholderFromFB(field *flatbuf.Field, encoding *flatbuf.DictionaryEncoding) (arrow.Field, error) {
+	o, err := fieldFromFBDict(field)
+	if err != nil {
+		return o, err
+	}
+	md, err := metadataFromFB(field)
+	if err != nil {
+		return o, err
+	}
+
+	var index arrow.DataType = arrow.PrimitiveTypes.Int32
+	if fb := encoding.IndexType(nil); fb != nil {
+		index, err = intFromFB(*fb)
+		if err != nil {
+			return o, xerrors.Errorf("arrow/ipc: invalid index type of dictionary-encoded field %q: %w", o.Name, err)
+		}
 	}
 
+	keys := append(md.Keys(), DictionaryIDKeyName, DictionaryIndexTypeKeyName, DictionaryOrderedKeyName)
+	vals := append(md.Values(), strconv.FormatInt(encoding.Id(), 10), index.Name(), strconv.FormatBool(encoding.IsOrdered()))
+	o.Metadata = arrow.NewMetadata(keys, vals)
 	return o, nil
 }
 
@@ -667,6 +732,8 @@ func concreteTypeFromFB(typ flatbuf.Type, data flatbuffers.Table, children []arr
 
 		var dt flatbuf.Map
 		dt.Init(data.Bytes, data.Pos)
+		// the names of the entries and their fields vary between producers:
+		// only their position is meaningful.
 		ret := arrow.MapOf(pairType.Field(0).Type, pairType.Field(1).Type)
 		ret.KeysSorted = dt.KeysSorted()
 		return ret, nil
@@ -915,7 +982,7 @@ func schemaToFB(b *flatbuffers.Builder, schema *arrow.Schema, memo *dictMemo) fl
 	metaFB := metadataToFB(b, schema.Metadata(), flatbuf.SchemaStartCustomMetadataVector)
 
 	flatbuf.SchemaStart(b)
-	flatbuf.SchemaAddEndianness(b, flatbuf.EndiannessLittle)
+	flatbuf.SchemaAddEndianness(b, nativeEndianness)
 	flatbuf.SchemaAddFields(b, fieldsFB)
 	flatbuf.SchemaAddCustomMetadata(b, metaFB)
 	offset := flatbuf.SchemaEnd(b)
@@ -18,6 +18,7 @@ package ipc
 
 import (
 	"bytes"
+	"encoding/binary"
 	"reflect"
 	"testing"
 
@@ -28,6 +29,8 @@ import (
 	"github.com/apache/arrow/go/v8/arrow/memory"
 	flatbuffers "github.com/google/flatbuffers/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 )
 
 func TestRWSchema(t *testing.T) {
@@ -220,3 +223,206 @@ func TestUnrecognizedExtensionType(t *testing.T) {
 
 	assert.Truef(t, array.RecordEqual(rec, batchNoExt), "expected: %s\ngot: %s\n", batchNoExt, rec)
 }
+
+func TestExtensionTypeResolver(t *testing.T) {
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	extArr := exampleUUID(pool)
+	defer extArr.Release()
+
+	lb := array.NewListBuilder(pool, extArr.DataType())
+	defer lb.Release()
+	lb.Append(true)
+	lb.AppendNull()
+	lb.Append(true)
+	lb.Append(true)
+	vb := lb.ValueBuilder().(*array.ExtensionBuilder).Builder.(*array.FixedSizeBinaryBuilder)
+	vb.AppendValues([][]byte{[]byte("abcdefghijklmno3"), []byte("abcdefghijklmno4")}, nil)
+	listArr := lb.NewArray()
+	defer listArr.Release()
+
+	batch := array.NewRecord(
+		arrow.NewSchema([]arrow.Field{
+			{Name: "f0", Type: extArr.DataType(), Nullable: true},
+			{Name: "f1", Type: listArr.DataType(), Nullable: true},
+		}, nil),
+		[]arrow.Array{extArr, listArr}, 4)
+	defer batch.Release()
+
+	raw := writeTestFile(t, []arrow.Record{batch})
+
+	var calls []string
+	resolve := func(name string, storage arrow.DataType, metadata string) (arrow.ExtensionType, error) {
+		calls = append(calls, name+":"+metadata)
+		if name != "uuid" {
+			return nil, nil
+		}
+		return types.NewUUIDType().Deserialize(storage, metadata)
+	}
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(pool), WithExtensionTypeResolver(resolve))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, []string{"uuid:uuid-serialized", "uuid:uuid-serialized"}, calls)
+	assert.True(t, r.Schema().Equal(batch.Schema()), "got=%v, want=%v", r.Schema(), batch.Schema())
+
+	rec, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer rec.Release()
+	assert.Truef(t, array.RecordEqual(batch, rec), "expected: %s\ngot: %s\n", batch, rec)
+	assert.IsType(t, (*types.UUIDArray)(nil), rec.Column(0))
+
+	// a nil type keeps the storage type, with the extension metadata.
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(pool),
+		WithExtensionTypeResolver(func(string, arrow.DataType, string) (arrow.ExtensionType, error) { return nil, nil }))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, arrow.FIXED_SIZE_BINARY, r.Schema().Field(0).Type.ID())
+	assert.True(t, r.Schema().Field(0).HasMetadata())
+
+	for _, fn := range []func(string, arrow.DataType, string) (arrow.ExtensionType, error){
+		func(string, arrow.DataType, string) (arrow.ExtensionType, error) { return nil, xerrors.New("unknown") },
+		func(string, arrow.DataType, string) (arrow.ExtensionType, error) {
+			return types.NewParametric1Type(1), nil
+		},
+	} {
+		_, err = NewFileReader(bytes.NewReader(raw), WithAllocator(pool), WithExtensionTypeResolver(fn))
+		assert.Error(t, err)
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WithAllocator(pool), WithSchema(batch.Schema()))
+	require.NoError(t, w.Write(batch))
+	require.NoError(t, w.Close())
+
+	sr, err := NewReader(&buf, WithAllocator(pool), WithExtensionTypeResolver(resolve))
+	require.NoError(t, err)
+	defer sr.Release()
+	require.True(t, sr.Next())
+	assert.Truef(t, array.RecordEqual(batch, sr.Record()), "expected: %s\ngot: %s\n", batch, sr.Record())
+}
+
+func TestExtensionTypeResolverMap(t *testing.T) {
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	itemType := arrow.StructOf(arrow.Field{Name: "u", Type: types.NewUUIDType(), Nullable: true})
+	mb := array.NewMapBuilder(pool, arrow.BinaryTypes.String, itemType, true)
+	defer mb.Release()
+	kb := mb.KeyBuilder().(*array.StringBuilder)
+	ib := mb.ItemBuilder().(*array.StructBuilder)
+	ub := ib.FieldBuilder(0).(*array.ExtensionBuilder).Builder.(*array.FixedSizeBinaryBuilder)
+	mb.Append(true)
+	kb.AppendValues([]string{"a", "b"}, nil)
+	ib.AppendValues([]bool{true, true})
+	ub.AppendValues([][]byte{[]byte("abcdefghijklmno0"), nil}, []bool{true, false})
+	mb.AppendNull()
+	mapArr := mb.NewArray()
+	defer mapArr.Release()
+
+	batch := array.NewRecord(
+		arrow.NewSchema([]arrow.Field{{Name: "m", Type: mapArr.DataType(), Nullable: true}}, nil),
+		[]arrow.Array{mapArr}, 2)
+	defer batch.Release()
+
+	raw := writeTestFile(t, []arrow.Record{batch})
+
+	var calls []string
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(pool),
+		WithExtensionTypeResolver(func(name string, storage arrow.DataType, metadata string) (arrow.ExtensionType, error) {
+			calls = append(calls, name)
+			return types.NewUUIDType().Deserialize(storage, metadata)
+		}))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, []string{"uuid"}, calls)
+	assert.True(t, r.Schema().Equal(batch.Schema()), "got=%v, want=%v", r.Schema(), batch.Schema())
+
+	rec, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer rec.Release()
+	assert.Truef(t, array.RecordEqual(batch, rec), "expected: %s\ngot: %s\n", batch, rec)
+}
+
+func TestMapTypeFromFB(t *testing.T) {
+	b := flatbuffers.NewBuilder(64)
+	flatbuf.MapStart(b)
+	flatbuf.MapAddKeysSorted(b, true)
+	b.Finish(flatbuf.MapEnd(b))
+
+	var tbl flatbuffers.Table
+	tbl.Bytes = b.FinishedBytes()
+	tbl.Pos = flatbuffers.GetUOffsetT(tbl.Bytes)
+
+	entries := func(fields ...arrow.Field) []arrow.Field {
+		return []arrow.Field{{Name: "my_ents", Type: arrow.StructOf(fields...)}}
+	}
+	var (
+		key   = arrow.Field{Name: "src", Type: arrow.BinaryTypes.String}
+		value = arrow.Field{Name: "dests", Type: arrow.PrimitiveTypes.Int32, Nullable: true}
+	)
+
+	dt, err := concreteTypeFromFB(flatbuf.TypeMap, tbl, entries(key, value))
+	require.NoError(t, err)
+	want := arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32)
+	want.KeysSorted = true
+	assert.True(t, arrow.TypeEqual(want, dt), "got=%v, want=%v", dt, want)
+
+	for _, tc := range []struct {
+		name     string
+		children []arrow.Field
+	}{
+		{"no-entries", nil},
+		{"one-field", entries(key)},
+		{"three-fields", entries(key, value, arrow.Field{Name: "extra", Type: arrow.PrimitiveTypes.Int8})},
+		{"not-struct", []arrow.Field{{Name: "my_ents", Type: arrow.PrimitiveTypes.Int32}}},
+		{"nullable-entries", []arrow.Field{{Name: "my_ents", Type: arrow.StructOf(key, value), Nullable: true}}},
+		{"nullable-key", entries(arrow.Field{Name: "src", Type: arrow.BinaryTypes.String, Nullable: true}, value)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := concreteTypeFromFB(flatbuf.TypeMap, tbl, tc.children)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMessageFraming(t *testing.T) {
+	framed := func(words ...uint32) []byte {
+		buf := make([]byte, 4*len(words))
+		for i, w := range words {
+			binary.LittleEndian.PutUint32(buf[4*i:], w)
+		}
+		return buf
+	}
+
+	for _, tc := range []struct {
+		name         string
+		buf          []byte
+		prefix, size int
+		err          string
+	}{
+		{name: "continuation", buf: framed(kIPCContToken, 8, 1, 2), prefix: 8, size: 8},
+		{name: "continuation-padded", buf: framed(kIPCContToken, 4, 1, 2), prefix: 8, size: 4},
+		{name: "legacy", buf: framed(12, 1, 2, 3), prefix: 4, size: 12},
+		{name: "legacy-padded", buf: framed(8, 1, 2, 3), prefix: 4, size: 8},
+		{name: "short", buf: []byte{1, 2}, err: "too short"},
+		{name: "short-continuation", buf: framed(kIPCContToken), err: "too short"},
+		{name: "end-of-stream", buf: framed(0, 0, 0, 0), err: "invalid message metadata length 0"},
+		{name: "continuation-zero", buf: framed(kIPCContToken, 0, 1, 2), err: "invalid message metadata length 0"},
+		{name: "continuation-overflow", buf: framed(kIPCContToken, 12, 1, 2), err: "invalid message metadata length 12"},
+		{name: "legacy-overflow", buf: framed(16, 1, 2, 3), err: "invalid message metadata length 16"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix, size, err := messageFraming(tc.buf)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.prefix, prefix)
+			assert.Equal(t, tc.size, size)
+		})
+	}
+}
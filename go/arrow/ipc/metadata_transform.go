@@ -0,0 +1,65 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"github.com/apache/arrow/go/v8/arrow"
+)
+
+// transformMetadata returns schema with its custom metadata replaced by the
+// one returned by schemaMeta, and the custom metadata of its fields by the
+// one returned by fieldMeta, when not nil.
+func transformMetadata(schema *arrow.Schema, schemaMeta func(arrow.Metadata) arrow.Metadata, fieldMeta func([]int, arrow.Field) arrow.Metadata) *arrow.Schema {
+	if schemaMeta == nil && fieldMeta == nil {
+		return schema
+	}
+
+	md := schema.Metadata()
+	if schemaMeta != nil {
+		md = schemaMeta(md)
+	}
+	fields := schema.Fields()
+	if fieldMeta != nil {
+		fields = transformFields(nil, fields, fieldMeta)
+	}
+	return arrow.NewSchema(fields, &md)
+}
+
+// transformFields returns a copy of fields, the children of the field at
+// path, with their custom metadata transformed by fn.
+func transformFields(path []int, fields []arrow.Field, fn func([]int, arrow.Field) arrow.Metadata) []arrow.Field {
+	out := make([]arrow.Field, len(fields))
+	for i, field := range fields {
+		out[i] = transformField(append(path[:len(path):len(path)], i), field, fn)
+	}
+	return out
+}
+
+func transformField(path []int, field arrow.Field, fn func([]int, arrow.Field) arrow.Metadata) arrow.Field {
+	switch dt := field.Type.(type) {
+	case *arrow.ListType:
+		elem := transformField(append(path[:len(path):len(path)], 0), dt.ElemField(), fn)
+		field.Type = arrow.ListOfField(elem)
+	case *arrow.FixedSizeListType:
+		elem := transformField(append(path[:len(path):len(path)], 0), dt.ElemField(), fn)
+		field.Type = arrow.FixedSizeListOfField(dt.Len(), elem)
+	case *arrow.StructType:
+		field.Type = arrow.StructOf(transformFields(path, dt.Fields(), fn)...)
+	}
+	field.Metadata = fn(path, field)
+	return field
+}
@@ -0,0 +1,129 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataTransform(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	elem := arrow.Field{
+		Name: "item", Type: arrow.PrimitiveTypes.Int32, Nullable: true,
+		Metadata: arrow.NewMetadata([]string{"secret"}, []string{"s3cr3t"}),
+	}
+	schemaMD := arrow.NewMetadata([]string{"secret", "owner", "version"}, []string{"s3cr3t", "alice", "1"})
+	schema := arrow.NewSchema([]arrow.Field{
+		{
+			Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true,
+			Metadata: arrow.NewMetadata([]string{"owner", "unit"}, []string{"bob", "m"}),
+		},
+		{Name: "list", Type: arrow.ListOfField(elem), Nullable: true},
+	}, &schemaMD)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	lb := b.Field(1).(*array.ListBuilder)
+	for i := 0; i < 3; i++ {
+		lb.Append(true)
+		lb.ValueBuilder().(*array.Int32Builder).AppendValues([]int32{int32(i), -int32(i)}, nil)
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	// strip drops the secret key and renames owner to team.
+	strip := func(md arrow.Metadata) arrow.Metadata {
+		var keys, vals []string
+		for i, k := range md.Keys() {
+			switch k {
+			case "secret":
+				continue
+			case "owner":
+				k = "team"
+			}
+			keys = append(keys, k)
+			vals = append(vals, md.Values()[i])
+		}
+		return arrow.NewMetadata(keys, vals)
+	}
+
+	var paths [][]int
+	opts := []Option{
+		WithAllocator(mem),
+		WithSchemaMetadataTransform(strip),
+		WithFieldMetadataTransform(func(path []int, field arrow.Field) arrow.Metadata {
+			paths = append(paths, path)
+			return strip(field.Metadata)
+		}),
+	}
+
+	check := func(t *testing.T, got *arrow.Schema) {
+		t.Helper()
+		assert.Equal(t, arrow.NewMetadata([]string{"team", "version"}, []string{"alice", "1"}), got.Metadata())
+		assert.Equal(t, arrow.NewMetadata([]string{"team", "unit"}, []string{"bob", "m"}), got.Field(0).Metadata)
+		assert.False(t, got.Field(1).HasMetadata())
+		assert.False(t, got.Field(1).Type.(*arrow.ListType).ElemField().HasMetadata())
+		assert.ElementsMatch(t, [][]int{{0}, {1}, {1, 0}}, paths)
+		paths = nil
+	}
+
+	t.Run("file", func(t *testing.T) {
+		raw := writeTestFile(t, []arrow.Record{rec})
+		r, err := NewFileReader(bytes.NewReader(raw), opts...)
+		require.NoError(t, err)
+		defer r.Close()
+
+		check(t, r.Schema())
+		md, err := r.FieldMetadataByPath([]int{1, 0})
+		require.NoError(t, err)
+		assert.Zero(t, md.Len())
+
+		got, err := r.RecordAt(0)
+		require.NoError(t, err)
+		defer got.Release()
+		assert.True(t, got.Schema().Equal(r.Schema()))
+		assert.Equal(t, r.Schema().Metadata(), got.Schema().Metadata())
+		for i := range rec.Columns() {
+			assert.Truef(t, array.ArrayApproxEqual(rec.Column(i), got.Column(i)), "column %d differs", i)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, WithSchema(schema))
+		require.NoError(t, w.Write(rec))
+		require.NoError(t, w.Close())
+
+		r, err := NewReader(&buf, opts...)
+		require.NoError(t, err)
+		defer r.Release()
+
+		check(t, r.Schema())
+		require.True(t, r.Next())
+		assert.Equal(t, r.Schema().Metadata(), r.Record().Schema().Metadata())
+	})
+}
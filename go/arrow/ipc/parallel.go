@@ -0,0 +1,132 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// decodedBuffer is a buffer of a record decompressed before its arrays are
+// loaded.
+type decodedBuffer struct {
+	buf     *memory.Buffer
+	decoded bool // whether the buffer was decoded by the codec
+}
+
+// predecompress decompresses the non-empty buffers of the compressed record
+// of src with n goroutines, each with its own decompressor, for buffer to
+// serve them. Buffers of the i-th top-level field of schema are allocated
+// with opts.columnAllocator(i, mem). The memory limit of src is checked
+// against the uncompressed size prefixes of all the buffers before any of
+// them is allocated.
+func (src *ipcSource) predecompress(schema *arrow.Schema, mem memory.Allocator, n int, opts recordOptions) error {
+	var (
+		buf    flatbuf.Buffer
+		prefix [arrow.Int64SizeBytes]byte
+		allocs = make([]memory.Allocator, src.meta.BuffersLength())
+		jobs   = make(chan int, len(allocs))
+	)
+
+	j := 0
+	for i, field := range schema.Fields() {
+		alloc := opts.columnAllocator(i, mem)
+		for end := j + numTypeBuffers(field.Type); j < end && j < len(allocs); j++ {
+			allocs[j] = alloc
+		}
+	}
+
+	for j := range allocs {
+		src.meta.Buffers(&buf, j)
+		if buf.Length() == 0 || allocs[j] == nil {
+			continue
+		}
+		if buf.Length() < int64(len(prefix)) {
+			return xerrors.Errorf("arrow/ipc: compressed buffer too small (size=%d)", buf.Length())
+		}
+		if _, err := src.r.ReadAt(prefix[:], buf.Offset()); err != nil {
+			return err
+		}
+		size := int64(binary.LittleEndian.Uint64(prefix[:]))
+		if size == -1 {
+			size = buf.Length() - int64(len(prefix))
+		}
+		if err := src.reserve(size); err != nil {
+			return err
+		}
+		jobs <- j
+	}
+	close(jobs)
+
+	src.decoded = make([]decodedBuffer, len(allocs))
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, n)
+	)
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			codec, err := bodyDecompressor(src.meta, opts)
+			if err != nil {
+				errs[w] = err
+				return
+			}
+			defer codec.Close()
+
+			// memory was reserved above: the worker sources have no limit.
+			worker := ipcSource{meta: src.meta, r: src.r, codec: codec}
+			for j := range jobs {
+				var buf flatbuf.Buffer
+				src.meta.Buffers(&buf, j)
+				worker.mem = allocs[j]
+				raw := memory.NewResizableBuffer(worker.mem)
+				decoded, err := worker.decompress(raw, buf.Offset(), buf.Length())
+				if err != nil {
+					raw.Release()
+					errs[w] = err
+					return
+				}
+				src.decoded[j] = decodedBuffer{buf: raw, decoded: decoded}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			src.releaseDecoded()
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseDecoded releases the decompressed buffers that were not served.
+func (src *ipcSource) releaseDecoded() {
+	for i, b := range src.decoded {
+		if b.buf != nil {
+			b.buf.Release()
+			src.decoded[i].buf = nil
+		}
+	}
+}
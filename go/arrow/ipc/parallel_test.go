@@ -0,0 +1,189 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelDecompress(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 100)
+	defer releaseRecords(recs)
+	mixed := makeCompressibilityRecord(t, mem, 1024)
+	defer mixed.Release()
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"lz4", []Option{WithLZ4()}},
+		{"zstd", []Option{WithZstd()}},
+		{"stored", []Option{WithZstd(), WithCompressionThreshold(0)}},
+		{"uncompressed", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, recs := range [][]arrow.Record{recs, {mixed}} {
+				raw := writeTestFile(t, recs, tc.opts...)
+
+				read := func(n int) []BufferTrace {
+					var traces []BufferTrace
+					r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithParallelDecompress(n), WithBufferTraceHook(func(bt BufferTrace) {
+						traces = append(traces, bt)
+					}))
+					require.NoError(t, err)
+					defer r.Close()
+
+					for i, want := range recs {
+						got, err := r.RecordAt(i)
+						require.NoError(t, err)
+						assert.Truef(t, array.RecordEqual(want, got), "record %d", i)
+						got.Release()
+					}
+					return traces
+				}
+				assert.Equal(t, read(0), read(4), "buffers should be loaded the same way")
+			}
+		})
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, WithSchema(mixed.Schema()), WithAllocator(mem), WithZstd())
+		require.NoError(t, w.Write(mixed))
+		require.NoError(t, w.Close())
+
+		r, err := NewReader(&buf, WithAllocator(mem), WithParallelDecompress(4))
+		require.NoError(t, err)
+		defer r.Release()
+		require.True(t, r.Next(), "%v", r.Err())
+		assert.True(t, array.RecordEqual(mixed, r.Record()))
+	})
+}
+
+func TestParallelDecompressErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec := makeCompressibilityRecord(t, mem, 1024)
+	defer rec.Release()
+	raw := writeTestFile(t, []arrow.Record{rec}, WithZstd())
+
+	t.Run("memory-limit", func(t *testing.T) {
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithParallelDecompress(4), WithRecordMemoryLimit(1024))
+		require.NoError(t, err)
+		defer r.Close()
+
+		got, err := r.RecordAt(0)
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, errRecordMemoryLimit)
+	})
+
+	t.Run("size-prefix", func(t *testing.T) {
+		corrupted := append([]byte{}, raw...)
+		r, err := NewFileReader(bytes.NewReader(corrupted))
+		require.NoError(t, err)
+		blk, err := r.block(0)
+		require.NoError(t, err)
+		r.Close()
+
+		// declare one more byte for the first compressed buffer.
+		mutateRecordMeta(t, corrupted, 0, func(md *flatbuf.RecordBatch) {
+			var buf flatbuf.Buffer
+			for j := 0; j < md.BuffersLength(); j++ {
+				md.Buffers(&buf, j)
+				if buf.Length() == 0 {
+					continue
+				}
+				prefix := corrupted[blk.Offset+int64(blk.Meta)+buf.Offset():]
+				binary.LittleEndian.PutUint64(prefix, binary.LittleEndian.Uint64(prefix)+1)
+				return
+			}
+		})
+
+		r, err = NewFileReader(bytes.NewReader(corrupted), WithAllocator(mem), WithParallelDecompress(4))
+		require.NoError(t, err)
+		defer r.Close()
+
+		got, err := r.RecordAt(0)
+		assert.Nil(t, got)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid decompressed buffer size")
+	})
+}
+
+func BenchmarkParallelDecompress(b *testing.B) {
+	mem := memory.NewGoAllocator()
+
+	// 50 columns without nulls: 50 compressed value buffers.
+	const (
+		ncols = 50
+		nrows = 1 << 15
+	)
+	var (
+		rnd    = rand.New(rand.NewSource(1))
+		fields = make([]arrow.Field, ncols)
+		cols   = make([]arrow.Array, ncols)
+	)
+	for i := range cols {
+		bldr := array.NewInt64Builder(mem)
+		for j := 0; j < nrows; j++ {
+			bldr.Append(rnd.Int63n(1000))
+		}
+		fields[i] = arrow.Field{Name: fmt.Sprintf("col%d", i), Type: arrow.PrimitiveTypes.Int64}
+		cols[i] = bldr.NewArray()
+		defer cols[i].Release()
+		bldr.Release()
+	}
+	rec := array.NewRecord(arrow.NewSchema(fields, nil), cols, nrows)
+	defer rec.Release()
+	raw := writeTestFile(b, []arrow.Record{rec}, WithZstd())
+
+	for _, n := range []int{0, 2, 4, 8} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithParallelDecompress(n))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer r.Close()
+
+			b.ReportAllocs()
+			b.SetBytes(int64(ncols * nrows * arrow.Int64SizeBytes))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rec, err := r.RecordAt(0)
+				if err != nil {
+					b.Fatal(err)
+				}
+				rec.Release()
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/internal/debug"
+	"golang.org/x/xerrors"
+)
+
+// RecordReader iterates over the records of a file transformed by a
+// function, as returned by FileReader.Pipe.
+type RecordReader struct {
+	refCount int64
+
+	ctx context.Context
+	f   *FileReader
+	fn  func(arrow.Record) (arrow.Record, error)
+
+	irec int // index of the next record of f to transform
+	rec  arrow.Record
+	err  error
+}
+
+// Pipe returns a reader yielding the records of the file transformed by fn,
+// such as row filters or column projections. Records are read and
+// transformed one at a time, as the returned reader is advanced.
+//
+// fn is given each record of the file in turn, which is released once fn
+// returns: fn must Retain it to return it unchanged. Records for which fn
+// returns a nil record are skipped. Reading stops at the first error
+// returned by fn, or with the context's error if ctx is done.
+func (f *FileReader) Pipe(ctx context.Context, fn func(arrow.Record) (arrow.Record, error)) (*RecordReader, error) {
+	if fn == nil {
+		return nil, xerrors.Errorf("arrow/ipc: nil transform function")
+	}
+	return &RecordReader{refCount: 1, ctx: ctx, f: f, fn: fn}, nil
+}
+
+// Err returns the last error encountered while reading or transforming
+// the records.
+func (r *RecordReader) Err() error { return r.err }
+
+// Schema returns the schema of the current record or, before the first
+// call to Next, the schema of the records of the file.
+func (r *RecordReader) Schema() *arrow.Schema {
+	if r.rec != nil {
+		return r.rec.Schema()
+	}
+	return r.f.Schema()
+}
+
+// Retain increases the reference count by 1.
+// Retain may be called simultaneously from multiple goroutines.
+func (r *RecordReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+// Release decreases the reference count by 1.
+// When the reference count goes to zero, the current record is released.
+// Release may be called simultaneously from multiple goroutines.
+func (r *RecordReader) Release() {
+	debug.Assert(atomic.LoadInt64(&r.refCount) > 0, "too many releases")
+
+	if atomic.AddInt64(&r.refCount, -1) == 0 {
+		if r.rec != nil {
+			r.rec.Release()
+			r.rec = nil
+		}
+	}
+}
+
+// Next returns whether a transformed record could be produced.
+func (r *RecordReader) Next() bool {
+	if r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+
+	for r.err == nil && r.irec < r.f.NumRecords() {
+		i := r.irec
+		r.irec++
+
+		in, err := r.f.RecordAtContext(r.ctx, i)
+		if err != nil {
+			r.err = err
+			return false
+		}
+		out, err := r.fn(in)
+		in.Release()
+		if err != nil {
+			r.err = xerrors.Errorf("arrow/ipc: could not transform record %d: %w", i, err)
+			return false
+		}
+		if out != nil {
+			r.rec = out
+			return true
+		}
+	}
+	return false
+}
+
+// Record returns the current transformed record.
+// It is valid until the next call to Next.
+func (r *RecordReader) Record() arrow.Record {
+	return r.rec
+}
+
+// Read returns the next transformed record and an error, if any.
+// When no records are left, it returns (nil, io.EOF).
+// The returned record is valid until the next call to Read.
+func (r *RecordReader) Read() (arrow.Record, error) {
+	if !r.Next() {
+		if r.err == nil {
+			return nil, io.EOF
+		}
+		return nil, r.err
+	}
+	return r.rec, nil
+}
+
+var (
+	_ array.RecordReader = (*RecordReader)(nil)
+)
@@ -0,0 +1,150 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/arrio"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestFileReaderPipeFilter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 2, 0, 4)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	p, err := r.Pipe(context.Background(), func(rec arrow.Record) (arrow.Record, error) {
+		if rec.NumRows() == 0 {
+			return nil, nil
+		}
+		rec.Retain()
+		return rec, nil
+	})
+	require.NoError(t, err)
+	defer p.Release()
+
+	var rows []int64
+	for p.Next() {
+		rows = append(rows, p.Record().NumRows())
+	}
+	require.NoError(t, p.Err())
+	assert.Equal(t, []int64{3, 2, 4}, rows)
+
+	rec, err := p.Read()
+	assert.Nil(t, rec)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestFileReaderPipeProject(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 2)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	schema := arrow.NewSchema([]arrow.Field{testSchema.Field(1)}, nil)
+	p, err := r.Pipe(context.Background(), func(rec arrow.Record) (arrow.Record, error) {
+		return array.NewRecord(schema, []arrow.Array{rec.Column(1)}, rec.NumRows()), nil
+	})
+	require.NoError(t, err)
+	defer p.Release()
+
+	// the pipe composes with other record readers and writers.
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WithSchema(schema), WithAllocator(mem))
+	n, err := arrio.Copy(w, p)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(recs)), n)
+	require.NoError(t, w.Close())
+
+	out, err := NewReader(bytes.NewReader(buf.Bytes()), WithAllocator(mem))
+	require.NoError(t, err)
+	defer out.Release()
+
+	assert.True(t, out.Schema().Equal(schema))
+	for i, want := range recs {
+		require.True(t, out.Next())
+		assert.Truef(t, array.ArrayEqual(want.Column(1), out.Record().Column(0)), "records[%d] differ", i)
+	}
+	assert.False(t, out.Next())
+	require.NoError(t, out.Err())
+}
+
+func TestFileReaderPipeErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 1, 2, 3)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, err = r.Pipe(context.Background(), nil)
+	assert.Error(t, err)
+
+	errBoom := xerrors.New("boom")
+	p, err := r.Pipe(context.Background(), func(rec arrow.Record) (arrow.Record, error) {
+		if rec.NumRows() == 2 {
+			return nil, errBoom
+		}
+		rec.Retain()
+		return rec, nil
+	})
+	require.NoError(t, err)
+	defer p.Release()
+
+	require.True(t, p.Next())
+	assert.False(t, p.Next())
+	assert.True(t, xerrors.Is(p.Err(), errBoom))
+	assert.Contains(t, p.Err().Error(), "could not transform record 1")
+	assert.False(t, p.Next(), "reading should stop at the first error")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p, err = r.Pipe(ctx, func(rec arrow.Record) (arrow.Record, error) {
+		rec.Retain()
+		return rec, nil
+	})
+	require.NoError(t, err)
+	defer p.Release()
+	_, err = p.Read()
+	assert.ErrorIs(t, err, context.Canceled)
+}
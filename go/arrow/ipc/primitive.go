@@ -0,0 +1,97 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package ipc
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"golang.org/x/xerrors"
+)
+
+// Primitive is the set of Go types the values of a column can be viewed as
+// with PrimitiveSlice.
+type Primitive interface {
+	~int8 | ~int16 | ~int32 | ~int64 |
+		~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// primitiveKinds maps the fixed-width types to the kind of the Go values
+// they are stored as.
+var primitiveKinds = map[arrow.Type]reflect.Kind{
+	arrow.INT8:      reflect.Int8,
+	arrow.INT16:     reflect.Int16,
+	arrow.INT32:     reflect.Int32,
+	arrow.INT64:     reflect.Int64,
+	arrow.UINT8:     reflect.Uint8,
+	arrow.UINT16:    reflect.Uint16,
+	arrow.UINT32:    reflect.Uint32,
+	arrow.UINT64:    reflect.Uint64,
+	arrow.FLOAT32:   reflect.Float32,
+	arrow.FLOAT64:   reflect.Float64,
+	arrow.DATE32:    reflect.Int32,
+	arrow.DATE64:    reflect.Int64,
+	arrow.TIME32:    reflect.Int32,
+	arrow.TIME64:    reflect.Int64,
+	arrow.TIMESTAMP: reflect.Int64,
+	arrow.DURATION:  reflect.Int64,
+}
+
+// PrimitiveSlice returns the values of the col-th column of rec as a []T,
+// without copying them. T must be the Go type the values of the column are
+// stored as, such as int32 for int32 and date32 columns, or a type defined
+// on it. The column must have no nulls: see ColumnValidity to read the
+// validity bitmap of a column.
+//
+// The slice shares the memory of the column: it is only valid as long as
+// rec is, and must not be modified.
+//
+// PrimitiveSlice requires Go 1.21 or later: older toolchains compile the
+// package with the language version of the module, which predates generics.
+func PrimitiveSlice[T Primitive](rec arrow.Record, col int) ([]T, error) {
+	if col < 0 || col >= int(rec.NumCols()) {
+		return nil, xerrors.Errorf("arrow/ipc: column index %d out of bounds [0, %d)", col, rec.NumCols())
+	}
+
+	var (
+		arr  = rec.Column(col)
+		dt   = arr.DataType()
+		want = reflect.TypeOf(T(0))
+	)
+	if kind, ok := primitiveKinds[dt.ID()]; !ok || kind != want.Kind() {
+		return nil, xerrors.Errorf("arrow/ipc: cannot view column %q of type %v as []%v", rec.ColumnName(col), dt, want)
+	}
+	if n := arr.NullN(); n != 0 {
+		return nil, xerrors.Errorf("arrow/ipc: cannot view column %q with %d nulls as []%v", rec.ColumnName(col), n, want)
+	}
+
+	data := arr.Data()
+	if arr.Len() == 0 || data.Buffers()[1] == nil {
+		return []T{}, nil
+	}
+	buf := data.Buffers()[1]
+	if need := (data.Offset() + arr.Len()) * int(unsafe.Sizeof(T(0))); buf.Len() < need {
+		return nil, xerrors.Errorf("arrow/ipc: values buffer of column %q too short (got=%d, want>=%d)", rec.ColumnName(col), buf.Len(), need)
+	}
+	values := unsafe.Slice((*T)(unsafe.Pointer(&buf.Bytes()[0])), data.Offset()+arr.Len())
+	return values[data.Offset():], nil
+}
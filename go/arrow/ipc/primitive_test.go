@@ -0,0 +1,160 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrimitiveSlice(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "f32", Type: arrow.PrimitiveTypes.Float32},
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "date", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "str", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 2, 3, 4, 5}, nil)
+	b.Field(1).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5}, []bool{true, true, true, true, false})
+	b.Field(2).(*array.Float32Builder).AppendValues([]float32{1.5, 2.5, 3.5, 4.5, 5.5}, nil)
+	b.Field(3).(*array.Float64Builder).AppendValues([]float64{-1, -2, -3, -4, -5}, nil)
+	b.Field(4).(*array.Date32Builder).AppendValues([]arrow.Date32{10, 20, 30, 40, 50}, nil)
+	b.Field(5).(*array.StringBuilder).AppendValues([]string{"a", "b", "c", "d", "e"}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	raw := writeTestFile(t, []arrow.Record{rec})
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	i32, err := PrimitiveSlice[int32](got, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 3, 4, 5}, i32)
+	assert.Equal(t, &i32[0], &got.Column(0).(*array.Int32).Int32Values()[0], "values should not be copied")
+
+	f32, err := PrimitiveSlice[float32](got, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1.5, 2.5, 3.5, 4.5, 5.5}, f32)
+
+	f64, err := PrimitiveSlice[float64](got, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{-1, -2, -3, -4, -5}, f64)
+
+	dates, err := PrimitiveSlice[arrow.Date32](got, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []arrow.Date32{10, 20, 30, 40, 50}, dates)
+
+	slice := got.NewSlice(1, 4)
+	defer slice.Release()
+
+	i32, err = PrimitiveSlice[int32](slice, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{2, 3, 4}, i32)
+
+	i64, err := PrimitiveSlice[int64](slice, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3, 4}, i64)
+
+	empty := got.NewSlice(2, 2)
+	defer empty.Release()
+
+	i32, err = PrimitiveSlice[int32](empty, 0)
+	require.NoError(t, err)
+	assert.Empty(t, i32)
+
+	short := shortRecord(got, 0)
+	defer short.Release()
+
+	for _, tc := range []struct {
+		name string
+		fn   func() error
+		msg  string
+	}{
+		{"nulls", func() error { _, err := PrimitiveSlice[int64](got, 1); return err }, "with 1 nulls"},
+		{"mismatch", func() error { _, err := PrimitiveSlice[int64](got, 0); return err }, "of type int32 as []int64"},
+		{"unsigned", func() error { _, err := PrimitiveSlice[uint32](got, 0); return err }, "as []uint32"},
+		{"non-primitive", func() error { _, err := PrimitiveSlice[int32](got, 5); return err }, "of type utf8"},
+		{"out of bounds", func() error { _, err := PrimitiveSlice[int32](got, 6); return err }, "out of bounds"},
+		{"short buffer", func() error { _, err := PrimitiveSlice[int32](short, 0); return err }, "values buffer of column \"i32\" too short (got=16, want>=20)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fn()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.msg)
+		})
+	}
+}
+
+// shortArray is an array whose values buffer is shorter than its length
+// requires, as a malformed array implementation could return.
+type shortArray struct {
+	arrow.Array
+	data arrow.ArrayData
+}
+
+func (a shortArray) Data() arrow.ArrayData { return a.data }
+
+func (a shortArray) Retain() {
+	a.data.Retain()
+	a.Array.Retain()
+}
+
+func (a shortArray) Release() {
+	a.data.Release()
+	a.Array.Release()
+}
+
+// shortRecord returns a record holding the col-th column of rec, whose values
+// buffer lacks its last value.
+func shortRecord(rec arrow.Record, col int) arrow.Record {
+	arr := rec.Column(col)
+	bufs := arr.Data().Buffers()
+	width := arr.DataType().(arrow.FixedWidthDataType).BitWidth() / 8
+	vals := memory.NewBufferBytes(bufs[1].Bytes()[:(arr.Data().Offset()+arr.Len()-1)*width])
+
+	arr.Retain()
+	short := shortArray{
+		Array: arr,
+		data:  array.NewData(arr.DataType(), arr.Len(), []*memory.Buffer{bufs[0], vals}, nil, arr.NullN(), arr.Data().Offset()),
+	}
+	defer short.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{rec.Schema().Field(col)}, nil)
+	return array.NewRecord(schema, []arrow.Array{short}, rec.NumRows())
+}
@@ -0,0 +1,125 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+
+	"github.com/apache/arrow/go/v8/arrow"
+)
+
+// prefetch is a record being read ahead by a background goroutine.
+type prefetch struct {
+	done chan struct{} // closed once rec and err are set
+	rec  arrow.Record
+	err  error
+}
+
+// readAhead holds the records read ahead of FileReader.Read.
+type readAhead struct {
+	n     int               // number of records to read ahead
+	cache map[int]*prefetch // records being, or already, read ahead, by index
+
+	ctx    context.Context // context of the reads ahead, canceled on Close
+	cancel context.CancelFunc
+}
+
+func newReadAhead(n int) readAhead {
+	ctx, cancel := context.WithCancel(context.Background())
+	return readAhead{n: n, cache: make(map[int]*prefetch), ctx: ctx, cancel: cancel}
+}
+
+// readPrefetched returns the i-th record, from the read-ahead cache if it
+// was read ahead, and starts reading ahead the records following it which
+// pass the record filter, if any.
+// As with Record, the returned record is valid until the next call.
+func (f *FileReader) readPrefetched(i int) (arrow.Record, error) {
+	if f.record != nil {
+		f.record.Release()
+		f.record = nil
+	}
+
+	var (
+		rec arrow.Record
+		err error
+	)
+	if p, ok := f.ahead.cache[i]; ok {
+		<-p.done
+		delete(f.ahead.cache, i)
+		rec, err = p.rec, p.err
+	} else {
+		rec, err = f.RecordAt(i)
+	}
+
+	for j, n := i+1, 0; n < f.ahead.n && j < f.NumRecords(); j++ {
+		if f.filter != nil {
+			// do not read ahead the bodies of records Read will skip.
+			skip, err := f.skipRecord(j)
+			if err != nil {
+				// reported once Read reaches the record.
+				break
+			}
+			if skip {
+				continue
+			}
+		}
+		n++
+		if _, ok := f.ahead.cache[j]; ok {
+			continue
+		}
+		p := &prefetch{done: make(chan struct{})}
+		f.ahead.cache[j] = p
+		go func(j int) {
+			defer close(p.done)
+			p.rec, p.err = f.RecordAtContext(f.ahead.ctx, j)
+		}(j)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	f.record = rec
+	return rec, nil
+}
+
+// releaseReadAhead cancels the reads ahead, waits for them to complete and
+// releases the records read.
+func (f *FileReader) releaseReadAhead() {
+	if f.ahead.cancel != nil {
+		f.ahead.cancel()
+	}
+	for i, p := range f.ahead.cache {
+		<-p.done
+		if p.rec != nil {
+			p.rec.Release()
+		}
+		delete(f.ahead.cache, i)
+	}
+}
+
+// dropPrefetched releases the i-th record if it was read ahead.
+func (f *FileReader) dropPrefetched(i int) {
+	p, ok := f.ahead.cache[i]
+	if !ok {
+		return
+	}
+	<-p.done
+	if p.rec != nil {
+		p.rec.Release()
+	}
+	delete(f.ahead.cache, i)
+}
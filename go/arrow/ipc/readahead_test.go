@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderReadAhead(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 1, 2, 3, 4, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	scan := func(n int) int32 {
+		sr := &slowReaderAt{Reader: bytes.NewReader(raw), delay: 5 * time.Millisecond}
+		r, err := NewFileReader(sr, WithAllocator(mem), WithReadAhead(n))
+		require.NoError(t, err)
+		defer r.Close()
+
+		for i := range recs {
+			atomic.StoreInt32(&sr.reading, 1)
+			rec, err := r.Read()
+			atomic.StoreInt32(&sr.reading, 0)
+			require.NoError(t, err)
+			assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+
+			// let the records being read ahead arrive, as would happen
+			// while the caller processes rec.
+			for _, p := range r.ahead.cache {
+				<-p.done
+			}
+		}
+		_, err = r.Read()
+		assert.Equal(t, io.EOF, err)
+		return atomic.LoadInt32(&sr.blocking)
+	}
+
+	blocking := scan(0)
+	require.NotZero(t, blocking)
+	assert.Equal(t, blocking/int32(len(recs)), scan(2), "only the first record should block")
+
+	t.Run("close early", func(t *testing.T) {
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithReadAhead(3))
+		require.NoError(t, err)
+
+		rec, err := r.Read()
+		require.NoError(t, err)
+		assert.True(t, array.RecordEqual(recs[0], rec))
+		assert.Len(t, r.ahead.cache, 3)
+		require.NoError(t, r.Close())
+		assert.Empty(t, r.ahead.cache)
+	})
+}
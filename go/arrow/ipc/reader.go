@@ -32,9 +32,13 @@ import (
 // Reader reads records from an io.Reader.
 // Reader expects a schema (plus any dictionaries) as the first messages
 // in the stream, followed by records.
+// Reading dictionaries is not supported yet: streams whose schema declares
+// dictionary-encoded fields, or which carry dictionary batches, are rejected
+// with an error.
 type Reader struct {
-	r      MessageReader
-	schema *arrow.Schema
+	r         MessageReader
+	schema    *arrow.Schema // schema declared by the stream
+	recSchema *arrow.Schema // schema of the records, after read-time conversions
 
 	refCount int64
 	rec      arrow.Record
@@ -43,7 +47,19 @@ type Reader struct {
 	types dictTypeMap
 	memo  dictMemo
 
-	mem memory.Allocator
+	mem  memory.Allocator
+	opts recordOptions        // settings used to decode record batches
+	dups DuplicateFieldPolicy // handling of duplicate field names
+
+	byteSwap bool               // whether to byte-swap buffers of a non-native stream
+	host     flatbuf.Endianness // endianness of the host
+
+	// resolves the unregistered extension types of the schema, if not nil
+	resolveExt func(string, arrow.DataType, string) (arrow.ExtensionType, error)
+
+	// transform the metadata of the schema and of its fields, if not nil
+	schemaMeta func(arrow.Metadata) arrow.Metadata
+	fieldMeta  func([]int, arrow.Field) arrow.Metadata
 
 	done bool
 }
@@ -63,6 +79,14 @@ func NewReaderFromMessageReader(r MessageReader, opts ...Option) (*Reader, error
 		types:    make(dictTypeMap),
 		memo:     newMemo(),
 		mem:      cfg.alloc,
+		opts:     newRecordOptions(cfg),
+		dups:     cfg.dups,
+		byteSwap: cfg.byteSwap,
+		host:     cfg.host,
+
+		resolveExt: cfg.resolveExt,
+		schemaMeta: cfg.schemaMeta,
+		fieldMeta:  cfg.fieldMeta,
 	}
 
 	err := rr.readSchema(cfg.schema)
@@ -82,7 +106,7 @@ func NewReader(r io.Reader, opts ...Option) (*Reader, error) {
 // underlying stream.
 func (r *Reader) Err() error { return r.err }
 
-func (r *Reader) Schema() *arrow.Schema { return r.schema }
+func (r *Reader) Schema() *arrow.Schema { return r.recSchema }
 
 func (r *Reader) readSchema(schema *arrow.Schema) error {
 	msg, err := r.r.Message()
@@ -98,6 +122,11 @@ func (r *Reader) readSchema(schema *arrow.Schema) error {
 	var schemaFB flatbuf.Schema
 	initFB(&schemaFB, msg.msg.Header)
 
+	r.opts.swap, err = checkEndianness(schemaFB.Endianness(), r.host, r.byteSwap)
+	if err != nil {
+		return err
+	}
+
 	r.types, err = dictTypesFromFB(&schemaFB)
 	if err != nil {
 		return xerrors.Errorf("arrow/ipc: could read dictionary types from message schema: %w", err)
@@ -105,8 +134,9 @@ func (r *Reader) readSchema(schema *arrow.Schema) error {
 
 	// TODO(sbinet): in the future, we may want to reconcile IDs in the stream with
 	// those found in the schema.
-	for range r.types {
-		panic("not implemented") // FIXME(sbinet): ReadNextDictionary
+	if len(r.types) != 0 {
+		// FIXME(sbinet): ReadNextDictionary
+		return xerrors.Errorf("arrow/ipc: dictionary-encoded fields are not supported (%d dictionaries in schema)", len(r.types))
 	}
 
 	r.schema, err = schemaFromFB(&schemaFB, &r.memo)
@@ -114,11 +144,28 @@ func (r *Reader) readSchema(schema *arrow.Schema) error {
 		return xerrors.Errorf("arrow/ipc: could not decode schema from message schema: %w", err)
 	}
 
+	r.schema, err = resolveExtensions(r.schema, r.resolveExt)
+	if err != nil {
+		return err
+	}
+
 	// check the provided schema match the one read from stream.
 	if schema != nil && !schema.Equal(r.schema) {
 		return errInconsistentSchema
 	}
 
+	r.schema, err = dedupSchema(r.schema, r.dups)
+	if err != nil {
+		return err
+	}
+
+	r.schema = transformMetadata(r.schema, r.schemaMeta, r.fieldMeta)
+
+	r.recSchema, err = recordSchema(r.schema, r.opts)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -171,12 +218,21 @@ func (r *Reader) next() bool {
 		return false
 	}
 
-	if got, want := msg.Type(), MessageRecordBatch; got != want {
-		r.err = xerrors.Errorf("arrow/ipc: invalid message type (got=%v, want=%v", got, want)
+	switch got := msg.Type(); got {
+	case MessageRecordBatch:
+	case MessageDictionaryBatch:
+		r.err = xerrors.Errorf("arrow/ipc: dictionary batches are not supported")
+		return false
+	default:
+		r.err = xerrors.Errorf("arrow/ipc: invalid message type (got=%v, want=%v)", got, MessageRecordBatch)
 		return false
 	}
 
-	r.rec = newRecord(r.schema, msg.meta, bytes.NewReader(msg.body.Bytes()), r.mem)
+	r.rec, r.err = newRecord(r.schema, msg.meta, bytes.NewReader(msg.body.Bytes()), r.mem, r.opts)
+	if r.err != nil {
+		return false
+	}
+	r.rec = projectRecord(r.rec, r.mem, r.opts)
 	return true
 }
 
@@ -0,0 +1,100 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// retainedAllocator is a memory.Allocator keeping track of the bytes it has
+// allocated and not freed yet, that is, the bytes of the buffers of the
+// records handed out by a reader that have not been released.
+type retainedAllocator struct {
+	mem memory.Allocator
+	max int64 // number of retained bytes above which reads wait
+
+	mu    sync.Mutex
+	n     int64         // number of retained bytes
+	freed chan struct{} // closed, and replaced, whenever memory is freed
+}
+
+func newRetainedAllocator(mem memory.Allocator, max int64) *retainedAllocator {
+	return &retainedAllocator{mem: mem, max: max, freed: make(chan struct{})}
+}
+
+func (a *retainedAllocator) Allocate(size int) []byte {
+	b := a.mem.Allocate(size)
+	a.add(int64(len(b)))
+	return b
+}
+
+func (a *retainedAllocator) Reallocate(size int, b []byte) []byte {
+	n := len(b)
+	b = a.mem.Reallocate(size, b)
+	a.add(int64(len(b) - n))
+	return b
+}
+
+func (a *retainedAllocator) Free(b []byte) {
+	n := len(b)
+	a.mem.Free(b)
+	a.add(int64(-n))
+}
+
+func (a *retainedAllocator) add(n int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.n += n
+	if n < 0 {
+		close(a.freed)
+		a.freed = make(chan struct{})
+	}
+}
+
+// Len returns the number of retained bytes.
+func (a *retainedAllocator) Len() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.n
+}
+
+// wait waits until fewer than max bytes are retained, or ctx is done.
+// It fails at once if ctx can never be done, such as the background context
+// of reads without a context, lest it block forever.
+func (a *retainedAllocator) wait(ctx context.Context) error {
+	for {
+		a.mu.Lock()
+		n, freed := a.n, a.freed
+		a.mu.Unlock()
+		if n < a.max {
+			return nil
+		}
+		if ctx.Done() == nil {
+			return xerrors.Errorf("%w (limit=%d, retained=%d)", errMaxRetainedBytes, a.max, n)
+		}
+
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
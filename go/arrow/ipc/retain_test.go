@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderMaxRetainedBytes(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 4, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithMaxRetainedBytes(1))
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Zero(t, r.CurrentRetainedBytes())
+	before := mem.CurrentAlloc()
+	rec0, err := r.RecordAt(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(mem.CurrentAlloc()-before), r.CurrentRetainedBytes())
+	assert.NotZero(t, r.CurrentRetainedBytes())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	rec, err := r.RecordAtContext(ctx, 1)
+	assert.Nil(t, rec)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// reads without a context fail rather than block forever.
+	rec, err = r.RecordAt(1)
+	assert.Nil(t, rec)
+	assert.ErrorIs(t, err, errMaxRetainedBytes)
+	rec, err = r.Record(1)
+	assert.Nil(t, rec)
+	assert.ErrorIs(t, err, errMaxRetainedBytes)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan arrow.Record)
+	go func() {
+		rec, err := r.RecordAtContext(ctx, 1)
+		assert.NoError(t, err)
+		done <- rec
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("record read while the limit is reached")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	rec0.Release()
+	rec1 := <-done
+	assert.True(t, array.RecordEqual(recs[1], rec1))
+	rec1.Release()
+	assert.Zero(t, r.CurrentRetainedBytes())
+
+	// sequential reads release the previous record before reading the next.
+	for i := range recs {
+		rec, err := r.Read()
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+	}
+	_, err = r.Read()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,200 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/internal/debug"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// recordRing allocates the buffers of successive records from a ring of
+// slots. A slot is held by its record, and its slices, until they are all
+// released: only then is its memory reused by a later record.
+type recordRing struct {
+	slots []ringSlot
+	reads uint64 // number of records allocated so far, accessed atomically
+}
+
+func newRecordRing(k int) *recordRing {
+	ring := &recordRing{slots: make([]ringSlot, k)}
+	for i := range ring.slots {
+		ring.slots[i].mem = memory.NewGoAllocator()
+	}
+	return ring
+}
+
+// next acquires the first free slot following the slot of the previous
+// record, reset to hold size bytes, and returns it along with the generation
+// of the record. It returns an error if all the slots are held.
+func (ring *recordRing) next(size int) (*ringSlot, uint64, error) {
+	n := atomic.AddUint64(&ring.reads, 1) - 1
+	for i := range ring.slots {
+		slot := &ring.slots[(n+uint64(i))%uint64(len(ring.slots))]
+		if atomic.CompareAndSwapInt64(&slot.refs, 0, 1) {
+			return slot, slot.reuse(size), nil
+		}
+	}
+	return nil, 0, xerrors.Errorf("arrow/ipc: all %d slots of the record ring are held by unreleased records", len(ring.slots))
+}
+
+// ringSlot is a memory.Allocator carving allocations out of a slab which
+// is reused, and overwritten, by each new generation of the slot.
+type ringSlot struct {
+	refs int64 // number of records holding the slot, accessed atomically
+	mu   sync.Mutex
+	mem  memory.Allocator // allocator for the slab, and allocations beyond it
+	gen  uint64           // generation of the slot, accessed atomically
+	slab []byte
+	off  int
+}
+
+// reuse starts a new generation of the slot, whose slab holds at least size
+// bytes, and returns it.
+func (s *ringSlot) reuse(size int) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.slab) < size {
+		s.slab = s.mem.Allocate(size)
+	}
+	s.off = 0
+	return atomic.AddUint64(&s.gen, 1)
+}
+
+// Allocate returns a zeroed 64-byte aligned slice of size bytes, allocated
+// from the slab when it has room for it.
+func (s *ringSlot) Allocate(size int) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := int(paddedLength(int64(size), arenaAlign))
+	if s.off+n > len(s.slab) {
+		return s.mem.Allocate(size)
+	}
+	buf := s.slab[s.off : s.off+size : s.off+size]
+	s.off += n
+	for i := range buf {
+		buf[i] = 0
+	}
+	return buf
+}
+
+// Reallocate resizes b to size bytes.
+func (s *ringSlot) Reallocate(size int, b []byte) []byte {
+	if size <= len(b) {
+		return b[:size]
+	}
+	buf := s.Allocate(size)
+	copy(buf, b)
+	return buf
+}
+
+// Free is a no-op. The slab is reused by the next generation of the slot.
+func (s *ringSlot) Free(b []byte) {}
+
+func (s *ringSlot) retain() {
+	atomic.AddInt64(&s.refs, 1)
+}
+
+// release drops a hold on the slot. Once the last one is dropped, the
+// generation of the slot is bumped, so that the records of the previous
+// generation are stale, and the slot may be reused.
+func (s *ringSlot) release() {
+	debug.Assert(atomic.LoadInt64(&s.refs) > 0, "too many releases")
+	if atomic.LoadInt64(&s.refs) == 1 {
+		atomic.AddUint64(&s.gen, 1)
+	}
+	atomic.AddInt64(&s.refs, -1)
+}
+
+// ringRecord is a record whose buffers were allocated from a slot of a
+// record ring, which it holds until it is released. Accessing its data once
+// the slot was released panics.
+type ringRecord struct {
+	arrow.Record
+	refCount int64
+	slot     *ringSlot
+	gen      uint64
+}
+
+func newRingRecord(rec arrow.Record, slot *ringSlot, gen uint64) *ringRecord {
+	return &ringRecord{Record: rec, refCount: 1, slot: slot, gen: gen}
+}
+
+func (rec *ringRecord) Retain() {
+	atomic.AddInt64(&rec.refCount, 1)
+}
+
+func (rec *ringRecord) Release() {
+	debug.Assert(atomic.LoadInt64(&rec.refCount) > 0, "too many releases")
+
+	if atomic.AddInt64(&rec.refCount, -1) == 0 {
+		rec.Record.Release()
+		rec.slot.release()
+	}
+}
+
+func (rec *ringRecord) check() {
+	if now := atomic.LoadUint64(&rec.slot.gen); now != rec.gen {
+		panic(xerrors.Errorf("arrow/ipc: stale record: its buffers were reused by a later read (generation=%d, current=%d)", rec.gen, now))
+	}
+}
+
+func (rec *ringRecord) NumRows() int64 {
+	rec.check()
+	return rec.Record.NumRows()
+}
+
+func (rec *ringRecord) NumCols() int64 {
+	rec.check()
+	return rec.Record.NumCols()
+}
+
+func (rec *ringRecord) Columns() []arrow.Array {
+	rec.check()
+	return rec.Record.Columns()
+}
+
+func (rec *ringRecord) Column(i int) arrow.Array {
+	rec.check()
+	return rec.Record.Column(i)
+}
+
+func (rec *ringRecord) ColumnName(i int) string {
+	rec.check()
+	return rec.Record.ColumnName(i)
+}
+
+func (rec *ringRecord) NewSlice(i, j int64) arrow.Record {
+	rec.check()
+	rec.slot.retain()
+	return newRingRecord(rec.Record.NewSlice(i, j), rec.slot, rec.gen)
+}
+
+func (rec *ringRecord) MarshalJSON() ([]byte, error) {
+	rec.check()
+	return rec.Record.MarshalJSON()
+}
+
+var (
+	_ memory.Allocator = (*ringSlot)(nil)
+	_ arrow.Record     = (*ringRecord)(nil)
+)
@@ -0,0 +1,151 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderRecordRing(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 5, 5, 5, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReaderFromBytes(raw, WithAllocator(mem), WithRecordRing(2), WithLazyColumns())
+	require.NoError(t, err)
+	defer r.Close()
+
+	values := func(rec arrow.Record) *byte {
+		return &rec.Column(0).Data().Buffers()[1].Bytes()[0]
+	}
+
+	rec0, err := r.RecordAt(0)
+	require.NoError(t, err)
+	assert.True(t, array.RecordEqual(recs[0], rec0))
+	rec1, err := r.RecordAt(1)
+	require.NoError(t, err)
+	defer rec1.Release()
+	assert.True(t, array.RecordEqual(recs[1], rec1))
+
+	// both slots are held: the records are left untouched.
+	_, err = r.RecordAt(2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "all 2 slots of the record ring are held")
+	assert.True(t, array.RecordEqual(recs[0], rec0))
+	assert.True(t, array.RecordEqual(recs[1], rec1))
+
+	// releasing a record frees its slot for the next one.
+	v0 := values(rec0)
+	rec0.Release()
+	assert.Panics(t, func() { rec0.Column(0) }, "released records are stale")
+	assert.Panics(t, func() { rec0.NumRows() }, "released records are stale")
+
+	rec2, err := r.RecordAtContext(context.Background(), 2)
+	require.NoError(t, err)
+	assert.True(t, array.RecordEqual(recs[2], rec2))
+	assert.Equal(t, v0, values(rec2))
+
+	// slices hold the slot of their record.
+	slice := rec2.NewSlice(1, 3)
+	rec2.Release()
+	assert.EqualValues(t, 2, slice.NumRows())
+	_, err = r.RecordAt(3)
+	require.Error(t, err)
+	slice.Release()
+	assert.Panics(t, func() { slice.Column(0) }, "released slices are stale")
+
+	rec3, err := r.RecordAt(3)
+	require.NoError(t, err)
+	defer rec3.Release()
+	assert.True(t, array.RecordEqual(recs[3], rec3))
+
+	// bulk reads do not use the ring.
+	tbl, err := r.Table(context.Background())
+	require.NoError(t, err)
+	defer tbl.Release()
+	assert.EqualValues(t, 20, tbl.NumRows())
+	assert.True(t, array.RecordEqual(recs[1], rec1))
+}
+
+func TestFileReaderRecordRingSequential(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 5, 5, 5, 5)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	r, err := NewFileReaderFromBytes(raw, WithAllocator(mem), WithRecordRing(1))
+	require.NoError(t, err)
+	defer r.Close()
+
+	// Record releases the previous record, freeing its slot.
+	for i := range recs {
+		rec, err := r.Record(i)
+		require.NoError(t, err)
+		assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+	}
+
+	_, err = NewFileReaderFromBytes(raw, WithAllocator(mem), WithRecordRing(2), WithReadAhead(4))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WithRecordRing cannot be combined with WithReadAhead")
+}
+
+func TestFileReaderRecordRingConcurrent(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 50, 60, 70, 80, 90, 100, 110, 120)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	const workers = 4
+	r, err := NewFileReaderFromBytes(raw, WithAllocator(mem), WithRecordRing(workers))
+	require.NoError(t, err)
+	defer r.Close()
+
+	// each worker holds a single record at a time, so that there always is
+	// a free slot: no record may be overwritten while it is checked.
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for n := 0; n < 20; n++ {
+				i := (w + n) % len(recs)
+				rec, err := r.RecordAt(i)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+				rec.Release()
+			}
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,241 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"golang.org/x/xerrors"
+)
+
+// Keys of the field custom metadata holding column statistics. Values are
+// formatted as by the strconv package for numbers and booleans, and stored
+// as is for strings. They are specific to this package, the ARROW: prefix
+// being reserved by the format.
+const (
+	StatMinKey       = "arrow/ipc:stat:min"        // minimum non-null value of the column
+	StatMaxKey       = "arrow/ipc:stat:max"        // maximum non-null value of the column
+	StatNullCountKey = "arrow/ipc:stat:null_count" // number of nulls in the column
+)
+
+// RecordStatKey returns the key of the custom metadata of a record batch
+// message holding the statistic stat, one of StatMinKey, StatMaxKey and
+// StatNullCountKey, of the values of the column named column in the batch.
+// The column name is escaped as by url.QueryEscape, so that names holding
+// colons give distinct keys.
+func RecordStatKey(stat, column string) string {
+	return stat + ":" + url.QueryEscape(column)
+}
+
+// ColumnStats holds the statistics of a column of a file, as stamped by
+// the producer in the custom metadata of its field.
+type ColumnStats struct {
+	// Min and Max hold values of the Go type of the column values, such as
+	// int32 for an Int32 column or string for a String column, or nil if
+	// absent.
+	Min, Max interface{}
+	// NullCount is the number of nulls in the column, or -1 if absent.
+	NullCount int64
+}
+
+// ColumnStats returns the statistics of the col-th column of the records,
+// as described by Schema, and whether any was found. Columns of types other
+// than booleans, integers, floating-point numbers and strings have no
+// statistics, as have columns with malformed statistics and target fields
+// absent from the file. Values are typed as the column stored in the file,
+// before any read-time conversion.
+func (f *FileReader) ColumnStats(col int) (ColumnStats, bool) {
+	fields := f.recSchema.Fields()
+	if col < 0 || col >= len(fields) {
+		return ColumnStats{}, false
+	}
+	if f.opts.target != nil {
+		// records are projected onto the target schema by field name.
+		idx := f.schema.FieldIndices(fields[col].Name)
+		if len(idx) != 1 {
+			return ColumnStats{}, false
+		}
+		col = idx[0]
+	}
+	return columnStats(f.schema.Field(col))
+}
+
+func columnStats(field arrow.Field) (ColumnStats, bool) {
+	var (
+		md    = field.Metadata
+		stats = ColumnStats{NullCount: -1}
+		found bool
+	)
+	for _, stat := range []struct {
+		key string
+		dst *interface{}
+	}{
+		{StatMinKey, &stats.Min},
+		{StatMaxKey, &stats.Max},
+	} {
+		i := md.FindKey(stat.key)
+		if i < 0 {
+			continue
+		}
+		v, ok := parseStat(field.Type, md.Values()[i])
+		if !ok {
+			return ColumnStats{}, false
+		}
+		*stat.dst = v
+		found = true
+	}
+
+	if i := md.FindKey(StatNullCountKey); i >= 0 {
+		n, err := strconv.ParseInt(md.Values()[i], 10, 64)
+		if err != nil || n < 0 {
+			return ColumnStats{}, false
+		}
+		stats.NullCount = n
+		found = true
+	}
+
+	if !found {
+		return ColumnStats{}, false
+	}
+	return stats, true
+}
+
+// parseStat parses the statistic s of a column of type dt.
+func parseStat(dt arrow.DataType, s string) (interface{}, bool) {
+	var (
+		v   interface{}
+		err error
+	)
+	switch dt.ID() {
+	case arrow.BOOL:
+		v, err = strconv.ParseBool(s)
+	case arrow.INT8:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, 8)
+		v = int8(i)
+	case arrow.INT16:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, 16)
+		v = int16(i)
+	case arrow.INT32:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, 32)
+		v = int32(i)
+	case arrow.INT64:
+		v, err = strconv.ParseInt(s, 10, 64)
+	case arrow.UINT8:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, 8)
+		v = uint8(u)
+	case arrow.UINT16:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, 16)
+		v = uint16(u)
+	case arrow.UINT32:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, 32)
+		v = uint32(u)
+	case arrow.UINT64:
+		v, err = strconv.ParseUint(s, 10, 64)
+	case arrow.FLOAT32:
+		var f float64
+		f, err = strconv.ParseFloat(s, 32)
+		v = float32(f)
+	case arrow.FLOAT64:
+		v, err = strconv.ParseFloat(s, 64)
+	case arrow.STRING:
+		v = s
+	default:
+		return nil, false
+	}
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// recordStats returns the statistics, by column name, found in the custom
+// metadata md of a record batch message following schema, or nil if none.
+func recordStats(md arrow.Metadata, schema *arrow.Schema) map[string]ColumnStats {
+	if md.Len() == 0 {
+		return nil
+	}
+
+	var out map[string]ColumnStats
+	for _, field := range schema.Fields() {
+		var keys, vals []string
+		for _, stat := range []string{StatMinKey, StatMaxKey, StatNullCountKey} {
+			if i := md.FindKey(RecordStatKey(stat, field.Name)); i >= 0 {
+				keys = append(keys, stat)
+				vals = append(vals, md.Values()[i])
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		field.Metadata = arrow.NewMetadata(keys, vals)
+		stats, ok := columnStats(field)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]ColumnStats)
+		}
+		out[field.Name] = stats
+	}
+	return out
+}
+
+// skipRecord reports whether the i-th record is rejected by the record
+// filter, reading only the metadata of its message.
+func (f *FileReader) skipRecord(i int) (bool, error) {
+	blk, err := f.block(i)
+	if err != nil {
+		return false, err
+	}
+	meta, err := blk.readMeta(blk.section())
+	if err != nil {
+		return false, xerrors.Errorf("arrow/ipc: could not read record %d: %w", i, err)
+	}
+
+	md, err := metadataFromFB(flatbuf.GetRootAsMessage(meta.Bytes(), 0))
+	if err != nil {
+		return false, xerrors.Errorf("arrow/ipc: could not read metadata of record %d: %w", i, err)
+	}
+	stats := recordStats(md, f.schema)
+	if stats == nil {
+		return false, nil
+	}
+	return !f.filter(stats), nil
+}
+
+// skipRecords advances the current record index past the records rejected
+// by the record filter.
+func (f *FileReader) skipRecords() error {
+	for ; f.irec < f.NumRecords(); f.irec++ {
+		skip, err := f.skipRecord(f.irec)
+		if err != nil || !skip {
+			return err
+		}
+		f.dropPrefetched(f.irec)
+	}
+	return nil
+}
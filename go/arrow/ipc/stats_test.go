@@ -0,0 +1,281 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderColumnStats(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	stats := func(kvs ...string) arrow.Metadata {
+		var keys, vals []string
+		for i := 0; i < len(kvs); i += 2 {
+			keys = append(keys, kvs[i])
+			vals = append(vals, kvs[i+1])
+		}
+		return arrow.NewMetadata(keys, vals)
+	}
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32, Nullable: true,
+			Metadata: stats(StatMinKey, "-3", StatMaxKey, "42", StatNullCountKey, "2")},
+		{Name: "u8", Type: arrow.PrimitiveTypes.Uint8,
+			Metadata: stats(StatMaxKey, "255")},
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64,
+			Metadata: stats(StatMinKey, "-1.5", StatMaxKey, "2.25")},
+		{Name: "f32", Type: arrow.PrimitiveTypes.Float32,
+			Metadata: stats(StatMinKey, "0.5")},
+		{Name: "str", Type: arrow.BinaryTypes.String,
+			Metadata: stats(StatMinKey, "apple", StatMaxKey, "pear", StatNullCountKey, "0")},
+		{Name: "none", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "other", Type: arrow.PrimitiveTypes.Int64,
+			Metadata: stats("k", "v")},
+		{Name: "bad", Type: arrow.PrimitiveTypes.Int8,
+			Metadata: stats(StatMinKey, "128")},
+		{Name: "list", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64),
+			Metadata: stats(StatMinKey, "1")},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	r, err := NewFileReader(bytes.NewReader(writeTestFile(t, []arrow.Record{rec})), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	for _, tc := range []struct {
+		col  int
+		want ColumnStats
+		ok   bool
+	}{
+		{0, ColumnStats{Min: int32(-3), Max: int32(42), NullCount: 2}, true},
+		{1, ColumnStats{Max: uint8(255), NullCount: -1}, true},
+		{2, ColumnStats{Min: -1.5, Max: 2.25, NullCount: -1}, true},
+		{3, ColumnStats{Min: float32(0.5), NullCount: -1}, true},
+		{4, ColumnStats{Min: "apple", Max: "pear", NullCount: 0}, true},
+		{col: 5},
+		{col: 6},
+		{col: 7},
+		{col: 8},
+		{col: 9},
+		{col: -1},
+	} {
+		got, ok := r.ColumnStats(tc.col)
+		assert.Equalf(t, tc.ok, ok, "column %d", tc.col)
+		assert.Equalf(t, tc.want, got, "column %d", tc.col)
+	}
+
+	// indices refer to the columns of the projected records.
+	target := arrow.NewSchema([]arrow.Field{
+		{Name: "str", Type: arrow.BinaryTypes.String},
+		{Name: "missing", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	}, nil)
+	r, err = NewFileReader(bytes.NewReader(writeTestFile(t, []arrow.Record{rec})), WithAllocator(mem), WithTargetSchema(target, true))
+	require.NoError(t, err)
+	defer r.Close()
+
+	for _, tc := range []struct {
+		col  int
+		want ColumnStats
+		ok   bool
+	}{
+		{0, ColumnStats{Min: "apple", Max: "pear", NullCount: 0}, true},
+		{col: 1},
+		{2, ColumnStats{Min: int32(-3), Max: int32(42), NullCount: 2}, true},
+		{col: 3},
+	} {
+		got, ok := r.ColumnStats(tc.col)
+		assert.Equalf(t, tc.ok, ok, "target column %d", tc.col)
+		assert.Equalf(t, tc.want, got, "target column %d", tc.col)
+	}
+}
+
+// withRecordMetadata returns a copy of the file raw where the message of
+// the i-th record batch holds the custom metadata fn(i).
+func withRecordMetadata(t *testing.T, raw []byte, fn func(i int) arrow.Metadata) []byte {
+	t.Helper()
+
+	r, err := NewFileReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	defer r.Close()
+
+	var (
+		blks = make([]fileBlock, r.NumRecords())
+		out  []byte
+		end  int64
+	)
+	for i := range blks {
+		blk, md, err := r.recordMeta(i)
+		require.NoError(t, err)
+		if i == 0 {
+			out = append(out, raw[:blk.Offset]...)
+		}
+
+		nodes := make([]fieldMetadata, md.NodesLength())
+		for j := range nodes {
+			var node flatbuf.FieldNode
+			require.True(t, md.Nodes(&node, j))
+			nodes[j] = fieldMetadata{Len: node.Length(), Nulls: node.NullCount()}
+		}
+		bufs := make([]bufferMetadata, md.BuffersLength())
+		for j := range bufs {
+			var buf flatbuf.Buffer
+			require.True(t, md.Buffers(&buf, j))
+			bufs[j] = bufferMetadata{Offset: buf.Offset(), Len: buf.Length()}
+		}
+		codec := flatbuf.CompressionType(-1)
+		if c := md.Compression(nil); c != nil {
+			codec = c.Codec()
+		}
+
+		b := flatbuffers.NewBuilder(1024)
+		recFB := recordToFB(b, md.Length(), blk.Body, nodes, bufs, codec)
+		metaFB := metadataToFB(b, fn(i), flatbuf.MessageStartCustomMetadataVector)
+		flatbuf.MessageStart(b)
+		flatbuf.MessageAddVersion(b, flatbuf.MetadataVersion(currentMetadataVersion))
+		flatbuf.MessageAddHeaderType(b, flatbuf.MessageHeaderRecordBatch)
+		flatbuf.MessageAddHeader(b, recFB)
+		flatbuf.MessageAddBodyLength(b, blk.Body)
+		flatbuf.MessageAddCustomMetadata(b, metaFB)
+		b.Finish(flatbuf.MessageEnd(b))
+
+		var meta bytes.Buffer
+		n, err := writeMessage(memory.NewBufferBytes(b.FinishedBytes()), kArrowIPCAlignment, &meta)
+		require.NoError(t, err)
+
+		body := blk.Offset + int64(blk.Meta)
+		blks[i] = fileBlock{Offset: int64(len(out)), Meta: int32(n), Body: blk.Body}
+		out = append(out, meta.Bytes()...)
+		out = append(out, raw[body:body+blk.Body]...)
+		end = body + blk.Body
+	}
+	out = append(out, raw[end:]...)
+
+	return rewriteFooter(t, out, r.Version(), func([]fileBlock) []fileBlock { return blks })
+}
+
+func TestFileReaderRecordFilter(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 10, 10, 10, 10)
+	defer releaseRecords(recs)
+
+	// record i holds values of i64 in [10*i, 10*i+9].
+	raw := withRecordMetadata(t, writeTestFile(t, recs), func(i int) arrow.Metadata {
+		return arrow.NewMetadata(
+			[]string{RecordStatKey(StatMinKey, "i64"), RecordStatKey(StatMaxKey, "i64"), "other"},
+			[]string{strconv.Itoa(10 * i), strconv.Itoa(10*i + 9), "x"},
+		)
+	})
+
+	between := func(lo, hi int64) func(map[string]ColumnStats) bool {
+		return func(stats map[string]ColumnStats) bool {
+			s := stats["i64"]
+			return s.Max.(int64) >= lo && s.Min.(int64) <= hi
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"sequential", nil},
+		{"read-ahead", []Option{WithReadAhead(2)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ra := &readAtRecorder{Reader: bytes.NewReader(raw)}
+			opts := append([]Option{WithAllocator(mem), WithRecordFilter(between(23, 27))}, tc.opts...)
+			r, err := NewFileReader(ra, opts...)
+			require.NoError(t, err)
+
+			blks := r.RecordBlocks()
+			ra.reads = nil
+
+			rec, err := r.Read()
+			require.NoError(t, err)
+			assert.True(t, array.RecordEqual(recs[2], rec))
+			_, err = r.Read()
+			assert.Equal(t, io.EOF, err)
+
+			// records are still accessible by index.
+			rec, err = r.RecordAt(0)
+			require.NoError(t, err)
+			assert.True(t, array.RecordEqual(recs[0], rec))
+			rec.Release()
+			r.Close()
+
+			for i, blk := range blks {
+				if i == 0 || i == 2 {
+					continue
+				}
+				beg, end := blk.Offset+int64(blk.MetaDataLength), blk.Offset+int64(blk.MetaDataLength)+blk.BodyLength
+				for _, rd := range ra.reads {
+					if rd[0] < end && rd[0]+rd[1] > beg {
+						t.Errorf("body of skipped record %d read (offset=%d, length=%d)", i, rd[0], rd[1])
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("no stats", func(t *testing.T) {
+		r, err := NewFileReader(bytes.NewReader(writeTestFile(t, recs)), WithAllocator(mem), WithRecordFilter(between(23, 27)))
+		require.NoError(t, err)
+		defer r.Close()
+
+		for i := range recs {
+			rec, err := r.Read()
+			require.NoError(t, err)
+			assert.Truef(t, array.RecordEqual(recs[i], rec), "records[%d] differ", i)
+		}
+		_, err = r.Read()
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
+func TestRecordStatKey(t *testing.T) {
+	assert.Equal(t, "arrow/ipc:stat:min:i64", RecordStatKey(StatMinKey, "i64"))
+	assert.Equal(t, "arrow/ipc:stat:max:a%3Ab", RecordStatKey(StatMaxKey, "a:b"))
+	assert.Equal(t, "arrow/ipc:stat:null_count:a+b%25", RecordStatKey(StatNullCountKey, "a b%"))
+
+	keys := make(map[string]bool)
+	for _, stat := range []string{StatMinKey, StatMaxKey, StatNullCountKey} {
+		for _, col := range []string{"a", "a:b", "b", "min:a", "max:a", "", ":"} {
+			key := RecordStatKey(stat, col)
+			assert.Falsef(t, keys[key], "duplicate key %q", key)
+			keys[key] = true
+		}
+	}
+}
@@ -17,14 +17,18 @@
 package ipc_test
 
 import (
+	"bytes"
+	"encoding/binary"
 	"io"
 	"io/ioutil"
 	"os"
 	"strconv"
 	"testing"
 
+	"github.com/apache/arrow/go/v8/arrow/array"
 	"github.com/apache/arrow/go/v8/arrow/internal/arrdata"
 	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/ipc"
 	"github.com/apache/arrow/go/v8/arrow/memory"
 )
 
@@ -109,3 +113,130 @@ func TestStreamCompressed(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamFraming(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		rewrite func(t *testing.T, raw []byte) []byte
+	}{
+		{
+			name:    "continuation+eos",
+			rewrite: func(t *testing.T, raw []byte) []byte { return raw },
+		},
+		{
+			name: "no-eos",
+			rewrite: func(t *testing.T, raw []byte) []byte {
+				return raw[:len(raw)-8]
+			},
+		},
+		{
+			name:    "legacy-prefix",
+			rewrite: legacyStream,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			recs := arrdata.Records["primitives"]
+			schema := recs[0].Schema()
+
+			var buf bytes.Buffer
+			w := ipc.NewWriter(&buf, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+			for _, rec := range recs {
+				if err := w.Write(rec); err != nil {
+					t.Fatalf("could not write record: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("could not close writer: %v", err)
+			}
+
+			raw := tc.rewrite(t, buf.Bytes())
+
+			r, err := ipc.NewReader(bytes.NewReader(raw), ipc.WithAllocator(mem))
+			if err != nil {
+				t.Fatalf("could not create reader: %v", err)
+			}
+			defer r.Release()
+
+			if !r.Schema().Equal(schema) {
+				t.Fatalf("invalid schema:\ngot:\n%v\nwant:\n%v", r.Schema(), schema)
+			}
+
+			n := 0
+			for r.Next() {
+				if n >= len(recs) {
+					t.Fatalf("too many records")
+				}
+				if !array.RecordEqual(r.Record(), recs[n]) {
+					t.Fatalf("records[%d] differ", n)
+				}
+				n++
+			}
+			if err := r.Err(); err != nil {
+				t.Fatalf("could not read stream: %v", err)
+			}
+			if n != len(recs) {
+				t.Fatalf("invalid number of records: got=%d, want=%d", n, len(recs))
+			}
+		})
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := arrdata.Records["primitives"]
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(recs[0].Schema()), ipc.WithAllocator(mem))
+	for _, rec := range recs {
+		if err := w.Write(rec); err != nil {
+			t.Fatalf("could not write record: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not close writer: %v", err)
+	}
+
+	// cut the stream in the middle of the last record batch body.
+	raw := buf.Bytes()[:buf.Len()-8-16]
+
+	r, err := ipc.NewReader(bytes.NewReader(raw), ipc.WithAllocator(mem))
+	if err != nil {
+		t.Fatalf("could not create reader: %v", err)
+	}
+	defer r.Release()
+
+	for r.Next() {
+	}
+	if r.Err() == nil {
+		t.Fatalf("expected an error reading a truncated stream")
+	}
+}
+
+// legacyStream rewrites a stream so that messages are framed with the
+// pre-0.15 4-byte length prefix, without the continuation marker.
+func legacyStream(t *testing.T, raw []byte) []byte {
+	t.Helper()
+
+	var out []byte
+	for len(raw) > 0 {
+		if len(raw) < 8 || binary.LittleEndian.Uint32(raw) != 0xFFFFFFFF {
+			t.Fatalf("invalid message framing")
+		}
+		n := int(binary.LittleEndian.Uint32(raw[4:]))
+		if n == 0 {
+			// legacy streams end with a 4-byte zero.
+			out = append(out, 0, 0, 0, 0)
+			break
+		}
+		meta := raw[8 : 8+n]
+		body := int(flatbuf.GetRootAsMessage(meta, 0).BodyLength())
+		out = append(out, raw[4:8+n+body]...)
+		raw = raw[8+n+body:]
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"golang.org/x/xerrors"
+)
+
+// targetedSchema checks that records of the given schema can be projected
+// onto the target schema of opts, if any, and returns the schema of the
+// projected records.
+func targetedSchema(schema *arrow.Schema, opts recordOptions) (*arrow.Schema, error) {
+	if opts.target == nil {
+		return schema, nil
+	}
+
+	for _, field := range opts.target.Fields() {
+		idx := schema.FieldIndices(field.Name)
+		switch {
+		case len(idx) > 1:
+			return nil, xerrors.Errorf("arrow/ipc: ambiguous target field %q (%d fields)", field.Name, len(idx))
+		case len(idx) == 0 && !opts.fillMissing:
+			return nil, xerrors.Errorf("arrow/ipc: no field %q of the target schema", field.Name)
+		case len(idx) == 0 && !field.Nullable:
+			return nil, xerrors.Errorf("arrow/ipc: cannot fill missing non-nullable target field %q with nulls", field.Name)
+		case len(idx) == 1:
+			if dt := schema.Field(idx[0]).Type; !arrow.TypeEqual(dt, field.Type) {
+				return nil, xerrors.Errorf("arrow/ipc: incompatible type of target field %q (got=%v, want=%v)", field.Name, dt, field.Type)
+			}
+		}
+	}
+	return opts.target, nil
+}
+
+// projectRecord returns the columns of rec matching the fields of the
+// target schema of opts by name, in its order, with arrays of nulls
+// allocated with mem for the target fields absent from rec. The schema of
+// rec must have been checked by targetedSchema. projectRecord takes
+// ownership of rec.
+func projectRecord(rec arrow.Record, mem memory.Allocator, opts recordOptions) arrow.Record {
+	if opts.target == nil {
+		return rec
+	}
+	defer rec.Release()
+
+	cols := make([]arrow.Array, len(opts.target.Fields()))
+	for i, field := range opts.target.Fields() {
+		if idx := rec.Schema().FieldIndices(field.Name); len(idx) != 0 {
+			cols[i] = rec.Column(idx[0])
+			cols[i].Retain()
+		} else {
+			cols[i] = nullArray(mem, field.Type, int(rec.NumRows()))
+		}
+		defer cols[i].Release()
+	}
+	return array.NewRecord(opts.target, cols, rec.NumRows())
+}
+
+// nullArray returns an array of type dt holding n nulls.
+func nullArray(mem memory.Allocator, dt arrow.DataType, n int) arrow.Array {
+	b := array.NewBuilder(mem, dt)
+	defer b.Release()
+
+	b.Reserve(n)
+	for i := 0; i < n; i++ {
+		b.AppendNull()
+	}
+	return b.NewArray()
+}
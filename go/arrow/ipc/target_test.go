@@ -0,0 +1,129 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetSchema(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 0, 4)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	target := arrow.NewSchema([]arrow.Field{
+		{Name: "str", Type: arrow.BinaryTypes.String},
+		{Name: "f64", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+
+	check := func(t *testing.T, want, got arrow.Record) {
+		t.Helper()
+		assert.True(t, got.Schema().Equal(target), "schema: got=%v, want=%v", got.Schema(), target)
+		require.EqualValues(t, 2, got.NumCols())
+		require.Equal(t, want.NumRows(), got.NumRows())
+		assert.True(t, array.ArrayEqual(want.Column(1), got.Column(0)), "str")
+		assert.Equal(t, int(got.NumRows()), got.Column(1).NullN(), "missing column should be null")
+	}
+
+	t.Run("file", func(t *testing.T) {
+		for _, opts := range [][]Option{nil, {WithLazyColumns()}, {WithPerRecordArena()}} {
+			r, err := NewFileReader(bytes.NewReader(raw), append(opts, WithAllocator(mem), WithTargetSchema(target, true))...)
+			require.NoError(t, err)
+			assert.True(t, r.Schema().Equal(target))
+
+			for i, want := range recs {
+				got, err := r.RecordAt(i)
+				require.NoError(t, err)
+				check(t, want, got)
+				got.Release()
+			}
+
+			tbl, err := r.Table(context.Background())
+			require.NoError(t, err)
+			assert.True(t, tbl.Schema().Equal(target))
+			assert.EqualValues(t, 7, tbl.NumRows())
+			tbl.Release()
+			r.Close()
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, WithSchema(testSchema), WithAllocator(mem))
+		for _, rec := range recs {
+			require.NoError(t, w.Write(rec))
+		}
+		require.NoError(t, w.Close())
+
+		r, err := NewReader(&buf, WithAllocator(mem), WithTargetSchema(target, true))
+		require.NoError(t, err)
+		defer r.Release()
+		assert.True(t, r.Schema().Equal(target))
+
+		for _, want := range recs {
+			require.True(t, r.Next(), "%v", r.Err())
+			check(t, want, r.Record())
+		}
+		assert.False(t, r.Next())
+		assert.NoError(t, r.Err())
+	})
+
+	t.Run("cast", func(t *testing.T) {
+		target := arrow.NewSchema([]arrow.Field{{Name: "i64", Type: arrow.BinaryTypes.Binary}}, nil)
+		_, err := NewFileReader(bytes.NewReader(raw), WithTargetSchema(target, false))
+		require.Error(t, err)
+
+		target = arrow.NewSchema([]arrow.Field{{Name: "str", Type: arrow.BinaryTypes.Binary}}, nil)
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithTargetSchema(target, false), WithFieldCast("str", arrow.BinaryTypes.Binary))
+		require.NoError(t, err)
+		defer r.Close()
+
+		got, err := r.RecordAt(0)
+		require.NoError(t, err)
+		defer got.Release()
+		assert.True(t, got.Schema().Equal(target))
+		assert.Equal(t, "s0", string(got.Column(0).(*array.Binary).Value(0)))
+	})
+
+	for _, tc := range []struct {
+		name   string
+		fields []arrow.Field
+		fill   bool
+		msg    string
+	}{
+		{"missing", target.Fields(), false, `no field "f64" of the target schema`},
+		{"non-nullable", []arrow.Field{{Name: "f64", Type: arrow.PrimitiveTypes.Float64}}, true, `cannot fill missing non-nullable target field "f64"`},
+		{"incompatible", []arrow.Field{{Name: "i64", Type: arrow.PrimitiveTypes.Int32, Nullable: true}}, true, `incompatible type of target field "i64"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewFileReader(bytes.NewReader(raw), WithTargetSchema(arrow.NewSchema(tc.fields, nil), tc.fill))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.msg)
+		})
+	}
+}
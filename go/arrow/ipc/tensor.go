@@ -0,0 +1,154 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/arrow/tensor"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"golang.org/x/xerrors"
+)
+
+// tensorTypes are the value types of the tensors supported by the tensor
+// package.
+var tensorTypes = map[arrow.Type]bool{
+	arrow.INT8: true, arrow.INT16: true, arrow.INT32: true, arrow.INT64: true,
+	arrow.UINT8: true, arrow.UINT16: true, arrow.UINT32: true, arrow.UINT64: true,
+	arrow.FLOAT32: true, arrow.FLOAT64: true,
+	arrow.DATE32: true, arrow.DATE64: true,
+}
+
+// Tensor returns the dense tensor stored in the i-th block of the record
+// batches of the file, as written by producers embedding tensor messages
+// alongside record batches. Sparse tensors are not supported. The data of
+// the tensor is copied into memory allocated by the reader's allocator: the
+// caller must call Release() on the returned tensor.
+func (f *FileReader) Tensor(i int) (tensor.Interface, error) {
+	if i < 0 || i >= f.NumRecords() {
+		return nil, xerrors.Errorf("arrow/ipc: tensor index %d out of bounds [0, %d)", i, f.NumRecords())
+	}
+
+	blk, err := f.block(i)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkBlock(blk, "tensor", i); err != nil {
+		return nil, err
+	}
+
+	msg, err := blk.NewMessage()
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not read tensor %d: %w", i, err)
+	}
+	defer msg.Release()
+
+	switch msg.Type() {
+	case MessageTensor:
+	case MessageSparseTensor:
+		return nil, xerrors.Errorf("arrow/ipc: message %d is a sparse tensor, which is not supported", i)
+	default:
+		return nil, xerrors.Errorf("arrow/ipc: message %d is not a Tensor (got=%v)", i, msg.Type())
+	}
+
+	var md flatbuf.Tensor
+	initFB(&md, msg.msg.Header)
+	t, err := tensorFromFB(&md, msg.body.Bytes(), f.mem)
+	if err != nil {
+		return nil, xerrors.Errorf("arrow/ipc: could not read tensor %d: %w", i, err)
+	}
+	return t, nil
+}
+
+// tensorFromFB decodes the tensor described by md, whose data is in body,
+// copying its data into memory allocated with mem.
+func tensorFromFB(md *flatbuf.Tensor, body []byte, mem memory.Allocator) (tensor.Interface, error) {
+	switch typ := md.TypeType(); typ {
+	case flatbuf.TypeInt, flatbuf.TypeFloatingPoint, flatbuf.TypeDate:
+	default:
+		return nil, xerrors.Errorf("arrow/ipc: invalid tensor value type %v", flatbuf.EnumNamesType[typ])
+	}
+	var tbl flatbuffers.Table
+	if !md.Type(&tbl) {
+		return nil, xerrors.Errorf("arrow/ipc: tensor has no value type")
+	}
+	dt, err := concreteTypeFromFB(md.TypeType(), tbl, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !tensorTypes[dt.ID()] {
+		return nil, xerrors.Errorf("arrow/ipc: invalid tensor value type %v", dt)
+	}
+	bw := int64(dt.(arrow.FixedWidthDataType).BitWidth() / 8)
+
+	var (
+		dim     flatbuf.TensorDim
+		shape   = make([]int64, md.ShapeLength())
+		names   = make([]string, md.ShapeLength())
+		strides []int64
+		n       = int64(1)
+	)
+	for j := range shape {
+		md.Shape(&dim, j)
+		if dim.Size() < 0 {
+			return nil, xerrors.Errorf("arrow/ipc: invalid negative size %d of tensor dimension %d", dim.Size(), j)
+		}
+		shape[j] = dim.Size()
+		names[j] = string(dim.Name())
+		n *= dim.Size()
+	}
+
+	// bytes spanned by the elements of the tensor.
+	extent := n * bw
+	if md.StridesLength() > 0 {
+		if md.StridesLength() != len(shape) {
+			return nil, xerrors.Errorf("arrow/ipc: tensor has %d strides for %d dimensions", md.StridesLength(), len(shape))
+		}
+		strides = make([]int64, len(shape))
+		extent = bw
+		for j := range strides {
+			strides[j] = md.Strides(j)
+			if strides[j] < 0 {
+				return nil, xerrors.Errorf("arrow/ipc: invalid negative stride %d of tensor dimension %d", strides[j], j)
+			}
+			if shape[j] > 0 {
+				extent += (shape[j] - 1) * strides[j]
+			}
+		}
+		if n == 0 {
+			extent = 0
+		}
+	}
+
+	var buf flatbuf.Buffer
+	md.Data(&buf)
+	if buf.Offset() < 0 || buf.Length() < extent || buf.Offset()+buf.Length() > int64(len(body)) {
+		return nil, xerrors.Errorf("arrow/ipc: invalid tensor data (offset=%d, length=%d, body=%d, want=%d)", buf.Offset(), buf.Length(), len(body), extent)
+	}
+
+	raw := memory.NewResizableBuffer(mem)
+	defer raw.Release()
+	raw.Resize(int(buf.Length()))
+	copy(raw.Bytes(), body[buf.Offset():])
+
+	// the data spans all the elements, strided or not.
+	data := array.NewData(dt, int((extent+bw-1)/bw), []*memory.Buffer{nil, raw}, nil, 0, 0)
+	defer data.Release()
+	return tensor.New(data, shape, strides, names), nil
+}
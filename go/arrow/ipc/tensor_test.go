@@ -0,0 +1,239 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/bitutil"
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/arrow/tensor"
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tensorDesc describes a tensor message of int32 or float64 values.
+type tensorDesc struct {
+	hdr     flatbuf.MessageHeader
+	float   bool
+	shape   []int64
+	names   []string
+	strides []int64
+	data    []byte
+}
+
+// appendTensor inserts the encapsulated tensor message described by desc
+// after the last record batch of the Arrow file raw, and lists it in the
+// footer after the record batches.
+func appendTensor(t *testing.T, raw []byte, desc tensorDesc) []byte {
+	t.Helper()
+
+	b := flatbuffers.NewBuilder(256)
+	var typ flatbuffers.UOffsetT
+	if desc.float {
+		flatbuf.FloatingPointStart(b)
+		flatbuf.FloatingPointAddPrecision(b, flatbuf.PrecisionDOUBLE)
+		typ = flatbuf.FloatingPointEnd(b)
+	} else {
+		flatbuf.IntStart(b)
+		flatbuf.IntAddBitWidth(b, 32)
+		flatbuf.IntAddIsSigned(b, true)
+		typ = flatbuf.IntEnd(b)
+	}
+
+	dims := make([]flatbuffers.UOffsetT, len(desc.shape))
+	for i, size := range desc.shape {
+		name := b.CreateString(desc.names[i])
+		flatbuf.TensorDimStart(b)
+		flatbuf.TensorDimAddSize(b, size)
+		flatbuf.TensorDimAddName(b, name)
+		dims[i] = flatbuf.TensorDimEnd(b)
+	}
+	flatbuf.TensorStartShapeVector(b, len(dims))
+	for i := len(dims) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(dims[i])
+	}
+	shape := b.EndVector(len(dims))
+
+	var strides flatbuffers.UOffsetT
+	if desc.strides != nil {
+		flatbuf.TensorStartStridesVector(b, len(desc.strides))
+		for i := len(desc.strides) - 1; i >= 0; i-- {
+			b.PrependInt64(desc.strides[i])
+		}
+		strides = b.EndVector(len(desc.strides))
+	}
+
+	flatbuf.TensorStart(b)
+	if desc.float {
+		flatbuf.TensorAddTypeType(b, flatbuf.TypeFloatingPoint)
+	} else {
+		flatbuf.TensorAddTypeType(b, flatbuf.TypeInt)
+	}
+	flatbuf.TensorAddType(b, typ)
+	flatbuf.TensorAddShape(b, shape)
+	if desc.strides != nil {
+		flatbuf.TensorAddStrides(b, strides)
+	}
+	flatbuf.TensorAddData(b, flatbuf.CreateBuffer(b, 0, int64(len(desc.data))))
+	hdr := flatbuf.TensorEnd(b)
+
+	body := make([]byte, bitutil.CeilByte64(int64(len(desc.data))))
+	copy(body, desc.data)
+
+	flatbuf.MessageStart(b)
+	flatbuf.MessageAddVersion(b, flatbuf.MetadataVersion(currentMetadataVersion))
+	flatbuf.MessageAddHeaderType(b, desc.hdr)
+	flatbuf.MessageAddHeader(b, hdr)
+	flatbuf.MessageAddBodyLength(b, int64(len(body)))
+	b.Finish(flatbuf.MessageEnd(b))
+	meta := b.FinishedBytes()
+
+	msg := make([]byte, 8+bitutil.CeilByte64(int64(len(meta))))
+	binary.LittleEndian.PutUint32(msg, kIPCContToken)
+	binary.LittleEndian.PutUint32(msg[4:], uint32(len(msg)-8))
+	copy(msg[8:], meta)
+
+	size := int64(binary.LittleEndian.Uint32(raw[len(raw)-len(Magic)-4:]))
+	start := int64(len(raw)) - size - int64(len(Magic)+4)
+	require.Zero(t, start%8)
+
+	out := append([]byte{}, raw[:start]...)
+	out = append(out, msg...)
+	out = append(out, body...)
+	out = append(out, raw[start:]...)
+	return rewriteFooter(t, out, currentMetadataVersion, func(blks []fileBlock) []fileBlock {
+		return append(blks, fileBlock{Offset: start, Meta: int32(len(msg)), Body: int64(len(body))})
+	})
+}
+
+func float64Bytes(vs ...float64) []byte {
+	out := make([]byte, 8*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint64(out[8*i:], math.Float64bits(v))
+	}
+	return out
+}
+
+func int32Bytes(vs ...int32) []byte {
+	out := make([]byte, 4*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint32(out[4*i:], uint32(v))
+	}
+	return out
+}
+
+func TestFileReaderTensor(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	open := func(t *testing.T, raw []byte) *FileReader {
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+		require.NoError(t, err)
+		require.Equal(t, 2, r.NumRecords())
+		return r
+	}
+
+	t.Run("dense", func(t *testing.T) {
+		r := open(t, appendTensor(t, raw, tensorDesc{
+			hdr:   flatbuf.MessageHeaderTensor,
+			float: true,
+			shape: []int64{2, 3},
+			names: []string{"rows", "cols"},
+			data:  float64Bytes(1, 2, 3, 4, 5, 6),
+		}))
+		defer r.Close()
+
+		tsr, err := r.Tensor(1)
+		require.NoError(t, err)
+		defer tsr.Release()
+
+		require.IsType(t, (*tensor.Float64)(nil), tsr)
+		assert.True(t, arrow.TypeEqual(arrow.PrimitiveTypes.Float64, tsr.DataType()))
+		assert.Equal(t, []int64{2, 3}, tsr.Shape())
+		assert.Equal(t, []string{"rows", "cols"}, tsr.DimNames())
+		assert.True(t, tsr.IsRowMajor())
+		assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, tsr.(*tensor.Float64).Float64Values())
+		assert.Equal(t, 6.0, tsr.(*tensor.Float64).Value([]int64{1, 2}))
+
+		_, err = r.RecordAt(1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "message 1 is a Tensor, not a Record")
+
+		_, err = r.Tensor(0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "message 0 is not a Tensor")
+
+		rec, err := r.RecordAt(0)
+		require.NoError(t, err)
+		rec.Release()
+	})
+
+	t.Run("strided", func(t *testing.T) {
+		// column-major 2x3 int32 tensor.
+		r := open(t, appendTensor(t, raw, tensorDesc{
+			hdr:     flatbuf.MessageHeaderTensor,
+			shape:   []int64{2, 3},
+			names:   []string{"", ""},
+			strides: []int64{4, 8},
+			data:    int32Bytes(1, 4, 2, 5, 3, 6),
+		}))
+		defer r.Close()
+
+		tsr, err := r.Tensor(1)
+		require.NoError(t, err)
+		defer tsr.Release()
+
+		require.IsType(t, (*tensor.Int32)(nil), tsr)
+		assert.True(t, tsr.IsColMajor())
+		for i, row := range [][]int32{{1, 2, 3}, {4, 5, 6}} {
+			for j, want := range row {
+				assert.Equalf(t, want, tsr.(*tensor.Int32).Value([]int64{int64(i), int64(j)}), "[%d, %d]", i, j)
+			}
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		desc tensorDesc
+		msg  string
+	}{
+		{"sparse", tensorDesc{hdr: flatbuf.MessageHeaderSparseTensor, data: int32Bytes(1)}, "sparse tensor, which is not supported"},
+		{"short", tensorDesc{hdr: flatbuf.MessageHeaderTensor, shape: []int64{4}, names: []string{""}, data: int32Bytes(1, 2)}, "invalid tensor data"},
+		{"strides", tensorDesc{hdr: flatbuf.MessageHeaderTensor, shape: []int64{2}, names: []string{""}, strides: []int64{4, 8}, data: int32Bytes(1, 2)}, "1 dimensions"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := open(t, appendTensor(t, raw, tc.desc))
+			defer r.Close()
+
+			tsr, err := r.Tensor(1)
+			assert.Nil(t, tsr)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.msg)
+		})
+	}
+}
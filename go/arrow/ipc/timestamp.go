@@ -0,0 +1,101 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"github.com/apache/arrow/go/v8/arrow"
+)
+
+// utcSchema returns schema with all its timestamp types, including nested
+// ones, declared in UTC.
+//
+// Arrow stores zoned timestamps as instants since the UNIX epoch in UTC: the
+// time zone only tells how to display them. Normalizing to UTC thus leaves
+// the values untouched.
+func utcSchema(schema *arrow.Schema) *arrow.Schema {
+	fields, changed := utcFields(schema.Fields())
+	if !changed {
+		return schema
+	}
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md)
+}
+
+func utcFields(fields []arrow.Field) ([]arrow.Field, bool) {
+	var out []arrow.Field
+	for i, field := range fields {
+		dt, ok := utcType(field.Type)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]arrow.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i].Type = dt
+	}
+	if out == nil {
+		return fields, false
+	}
+	return out, true
+}
+
+// utcType returns dt with its timestamp types declared in UTC, and whether
+// any was changed.
+func utcType(dt arrow.DataType) (arrow.DataType, bool) {
+	switch dt := dt.(type) {
+	case *arrow.TimestampType:
+		if dt.TimeZone == "" || dt.TimeZone == "UTC" {
+			return dt, false
+		}
+		return &arrow.TimestampType{Unit: dt.Unit, TimeZone: "UTC"}, true
+	case *arrow.ListType:
+		elem := dt.ElemField()
+		typ, ok := utcType(elem.Type)
+		if !ok {
+			return dt, false
+		}
+		elem.Type = typ
+		return arrow.ListOfField(elem), true
+	case *arrow.FixedSizeListType:
+		elem := dt.ElemField()
+		typ, ok := utcType(elem.Type)
+		if !ok {
+			return dt, false
+		}
+		elem.Type = typ
+		return arrow.FixedSizeListOfField(dt.Len(), elem), true
+	case *arrow.StructType:
+		fields, ok := utcFields(dt.Fields())
+		if !ok {
+			return dt, false
+		}
+		return arrow.StructOf(fields...), true
+	case *arrow.MapType:
+		key, kok := utcType(dt.KeyType())
+		item, iok := utcType(dt.ItemType())
+		if !kok && !iok {
+			return dt, false
+		}
+		m := arrow.MapOf(key, item)
+		m.SetItemNullable(dt.ItemField().Nullable)
+		m.KeysSorted = dt.KeysSorted
+		return m, true
+	default:
+		return dt, false
+	}
+}
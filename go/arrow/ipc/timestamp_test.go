@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampToUTC(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	var (
+		named  = &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "America/New_York"}
+		offset = &arrow.TimestampType{Unit: arrow.Second, TimeZone: "+05:30"}
+		utc    = &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+		naive  = &arrow.TimestampType{Unit: arrow.Nanosecond}
+	)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "named", Type: named},
+		{Name: "offset", Type: offset},
+		{Name: "utc", Type: utc},
+		{Name: "naive", Type: naive},
+		{Name: "list", Type: arrow.ListOf(offset)},
+		{Name: "struct", Type: arrow.StructOf(arrow.Field{Name: "ts", Type: named, Nullable: true})},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+
+	vs := []arrow.Timestamp{0, 1646092800000, -86400}
+	for i := 0; i < 4; i++ {
+		b.Field(i).(*array.TimestampBuilder).AppendValues(vs, nil)
+	}
+	lb := b.Field(4).(*array.ListBuilder)
+	lb.Append(true)
+	lb.ValueBuilder().(*array.TimestampBuilder).AppendValues(vs, nil)
+	lb.AppendNull()
+	lb.Append(true)
+	sb := b.Field(5).(*array.StructBuilder)
+	for _, v := range vs {
+		sb.Append(true)
+		sb.FieldBuilder(0).(*array.TimestampBuilder).Append(v)
+	}
+
+	rec := b.NewRecord()
+	defer rec.Release()
+	raw := writeTestFile(t, []arrow.Record{rec})
+
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem), WithTimestampToUTC())
+	require.NoError(t, err)
+	defer r.Close()
+
+	want := arrow.NewSchema([]arrow.Field{
+		{Name: "named", Type: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}},
+		{Name: "offset", Type: &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}},
+		{Name: "utc", Type: utc},
+		{Name: "naive", Type: naive},
+		{Name: "list", Type: arrow.ListOf(&arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"})},
+		{Name: "struct", Type: arrow.StructOf(arrow.Field{
+			Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}, Nullable: true,
+		})},
+	}, nil)
+	assert.True(t, want.Equal(r.Schema()), "got=%v\nwant=%v", r.Schema(), want)
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	assert.True(t, want.Equal(got.Schema()))
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, vs, got.Column(i).(*array.Timestamp).TimestampValues(), "field %q", schema.Field(i).Name)
+	}
+	list := got.Column(4).(*array.List)
+	assert.True(t, arrow.TypeEqual(want.Field(4).Type, list.DataType()))
+	assert.Equal(t, vs, list.ListValues().(*array.Timestamp).TimestampValues())
+	st := got.Column(5).(*array.Struct)
+	assert.Equal(t, vs, st.Field(0).(*array.Timestamp).TimestampValues())
+
+	// without the option, the declared zones are kept.
+	r, err = NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.True(t, schema.Equal(r.Schema()))
+}
@@ -0,0 +1,251 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"golang.org/x/xerrors"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	timestampType = reflect.TypeOf(arrow.Timestamp(0))
+	bytesType     = reflect.TypeOf([]byte(nil))
+)
+
+// valueSetter sets a Go value from the i-th value of an array.
+type valueSetter func(i int, v reflect.Value) error
+
+// UnmarshalRecord stores the rows of rec into dst, which must be a pointer
+// to a slice of structs. The slice is replaced by a slice holding one
+// element per row.
+//
+// Exported struct fields are filled from the column named after their
+// "arrow" struct tag, or after the field name if untagged. Fields tagged
+// "arrow:\"-\"" are ignored, as are columns without a matching field.
+//
+// Columns map to fields of the Go type of their values: bool, the integer
+// and floating-point types of matching size and signedness, string for
+// strings, []byte for binaries, time.Time (in UTC) or arrow.Timestamp for
+// timestamps and slices of the type of the values for lists. Null values
+// are only allowed for pointer fields, set to nil, and for slice fields,
+// set to a nil slice.
+func UnmarshalRecord(rec arrow.Record, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice || rv.Elem().Type().Elem().Kind() != reflect.Struct {
+		return xerrors.Errorf("arrow/ipc: destination must be a pointer to a slice of structs, got %T", dst)
+	}
+
+	var (
+		slice = rv.Elem()
+		typ   = slice.Type().Elem()
+	)
+
+	type binding struct {
+		index []int
+		set   valueSetter
+	}
+	var bindings []binding
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported.
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("arrow"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+
+		cols := rec.Schema().FieldIndices(name)
+		if len(cols) == 0 {
+			return xerrors.Errorf("arrow/ipc: no column %q for field %s", name, field.Name)
+		}
+		set, err := newValueSetter(rec.Column(cols[0]), field.Type)
+		if err != nil {
+			return xerrors.Errorf("arrow/ipc: cannot unmarshal column %q into field %s: %w", name, field.Name, err)
+		}
+		bindings = append(bindings, binding{index: field.Index, set: set})
+	}
+
+	n := int(rec.NumRows())
+	out := reflect.MakeSlice(slice.Type(), n, n)
+	for i := 0; i < n; i++ {
+		row := out.Index(i)
+		for _, b := range bindings {
+			if err := b.set(i, row.FieldByIndex(b.index)); err != nil {
+				return xerrors.Errorf("arrow/ipc: could not unmarshal row %d into field %s: %w", i, typ.FieldByIndex(b.index).Name, err)
+			}
+		}
+	}
+	slice.Set(out)
+	return nil
+}
+
+// newValueSetter returns a function setting values of type t from the
+// values of arr.
+func newValueSetter(arr arrow.Array, t reflect.Type) (valueSetter, error) {
+	if t.Kind() == reflect.Ptr {
+		set, err := newValueSetter(arr, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return func(i int, v reflect.Value) error {
+			if arr.IsNull(i) {
+				v.Set(reflect.Zero(t))
+				return nil
+			}
+			p := reflect.New(t.Elem())
+			if err := set(i, p.Elem()); err != nil {
+				return err
+			}
+			v.Set(p)
+			return nil
+		}, nil
+	}
+
+	set, err := newNonNullSetter(arr, t)
+	if err != nil {
+		return nil, err
+	}
+	return func(i int, v reflect.Value) error {
+		if arr.IsNull(i) {
+			if t.Kind() == reflect.Slice {
+				v.Set(reflect.Zero(t))
+				return nil
+			}
+			return xerrors.Errorf("null value for non-pointer type %v", t)
+		}
+		return set(i, v)
+	}, nil
+}
+
+// newNonNullSetter returns a function setting values of type t from the
+// non-null values of arr.
+func newNonNullSetter(arr arrow.Array, t reflect.Type) (valueSetter, error) {
+	mismatch := xerrors.Errorf("cannot unmarshal %v into Go value of type %v", arr.DataType(), t)
+
+	switch arr := arr.(type) {
+	case *array.Boolean:
+		if t.Kind() != reflect.Bool {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetBool(arr.Value(i)); return nil }, nil
+	case *array.Int8:
+		if t.Kind() != reflect.Int8 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetInt(int64(arr.Value(i))); return nil }, nil
+	case *array.Int16:
+		if t.Kind() != reflect.Int16 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetInt(int64(arr.Value(i))); return nil }, nil
+	case *array.Int32:
+		if t.Kind() != reflect.Int32 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetInt(int64(arr.Value(i))); return nil }, nil
+	case *array.Int64:
+		if t.Kind() != reflect.Int64 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetInt(arr.Value(i)); return nil }, nil
+	case *array.Uint8:
+		if t.Kind() != reflect.Uint8 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetUint(uint64(arr.Value(i))); return nil }, nil
+	case *array.Uint16:
+		if t.Kind() != reflect.Uint16 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetUint(uint64(arr.Value(i))); return nil }, nil
+	case *array.Uint32:
+		if t.Kind() != reflect.Uint32 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetUint(uint64(arr.Value(i))); return nil }, nil
+	case *array.Uint64:
+		if t.Kind() != reflect.Uint64 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetUint(arr.Value(i)); return nil }, nil
+	case *array.Float32:
+		if t.Kind() != reflect.Float32 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetFloat(float64(arr.Value(i))); return nil }, nil
+	case *array.Float64:
+		if t.Kind() != reflect.Float64 {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetFloat(arr.Value(i)); return nil }, nil
+	case *array.String:
+		if t.Kind() != reflect.String {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error { v.SetString(arr.Value(i)); return nil }, nil
+	case *array.Binary:
+		if t != bytesType {
+			return nil, mismatch
+		}
+		return func(i int, v reflect.Value) error {
+			v.SetBytes(append([]byte(nil), arr.Value(i)...))
+			return nil
+		}, nil
+	case *array.Timestamp:
+		unit := arr.DataType().(*arrow.TimestampType).Unit
+		switch t {
+		case timeType:
+			return func(i int, v reflect.Value) error {
+				v.Set(reflect.ValueOf(arr.Value(i).ToTime(unit)))
+				return nil
+			}, nil
+		case timestampType:
+			return func(i int, v reflect.Value) error { v.SetInt(int64(arr.Value(i))); return nil }, nil
+		}
+		return nil, mismatch
+	case *array.List:
+		if t.Kind() != reflect.Slice {
+			return nil, mismatch
+		}
+		set, err := newValueSetter(arr.ListValues(), t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		offsets := arr.Offsets()[arr.Data().Offset():]
+		return func(i int, v reflect.Value) error {
+			beg, end := int(offsets[i]), int(offsets[i+1])
+			out := reflect.MakeSlice(t, end-beg, end-beg)
+			for j := beg; j < end; j++ {
+				if err := set(j, out.Index(j-beg)); err != nil {
+					return err
+				}
+			}
+			v.Set(out)
+			return nil
+		}, nil
+	}
+	return nil, mismatch
+}
@@ -0,0 +1,171 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalRecord(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	tsType := &arrow.TimestampType{Unit: arrow.Millisecond}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "flag", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+		{Name: "at", Type: tsType},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "u16", Type: arrow.PrimitiveTypes.Uint16},
+		{Name: "ignored", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c"}, nil)
+	b.Field(2).(*array.Float64Builder).AppendValues([]float64{1.5, 0, 3.5}, []bool{true, false, true})
+	b.Field(3).(*array.BooleanBuilder).AppendValues([]bool{true, false, false}, []bool{true, true, false})
+	b.Field(4).(*array.TimestampBuilder).AppendValues([]arrow.Timestamp{0, 1500, 60000}, nil)
+	lb := b.Field(5).(*array.ListBuilder)
+	vb := lb.ValueBuilder().(*array.StringBuilder)
+	lb.Append(true)
+	vb.AppendValues([]string{"x", "y"}, nil)
+	lb.AppendNull()
+	lb.Append(true)
+	b.Field(6).(*array.Uint16Builder).AppendValues([]uint16{7, 8, 9}, nil)
+	b.Field(7).(*array.Int32Builder).AppendValues([]int32{0, 0, 0}, nil)
+
+	src := b.NewRecord()
+	defer src.Release()
+
+	// round-trip through a file, to unmarshal arrays as produced by loaders.
+	r, err := NewFileReader(bytes.NewReader(writeTestFile(t, []arrow.Record{src})), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+	rec, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer rec.Release()
+
+	type row struct {
+		ID    int64    `arrow:"id"`
+		Name  string   `arrow:"name"`
+		Score *float64 `arrow:"score"`
+		Flag  *bool    `arrow:"flag"`
+		At    time.Time
+		Tags  []string `arrow:"tags"`
+		U16   uint16   `arrow:"u16"`
+		Skip  int      `arrow:"-"`
+	}
+	// At is matched by its field name.
+	type atRow struct {
+		At arrow.Timestamp `arrow:"at"`
+	}
+
+	var rows []row
+	err = UnmarshalRecord(rec, &rows)
+	require.Error(t, err, "untagged field At has no matching column")
+	assert.Contains(t, err.Error(), `no column "At" for field At`)
+
+	type tagged struct {
+		ID    int64     `arrow:"id"`
+		Name  string    `arrow:"name"`
+		Score *float64  `arrow:"score"`
+		Flag  *bool     `arrow:"flag"`
+		At    time.Time `arrow:"at"`
+		Tags  []string  `arrow:"tags"`
+		U16   uint16    `arrow:"u16"`
+		Skip  int       `arrow:"-"`
+	}
+	f64 := func(v float64) *float64 { return &v }
+	boolp := func(v bool) *bool { return &v }
+
+	var got []tagged
+	require.NoError(t, UnmarshalRecord(rec, &got))
+	assert.Equal(t, []tagged{
+		{ID: 1, Name: "a", Score: f64(1.5), Flag: boolp(true), At: time.Unix(0, 0).UTC(), Tags: []string{"x", "y"}, U16: 7},
+		{ID: 2, Name: "b", Flag: boolp(false), At: time.Unix(1, 5e8).UTC(), U16: 8},
+		{ID: 3, Name: "c", Score: f64(3.5), At: time.Unix(60, 0).UTC(), Tags: []string{}, U16: 9},
+	}, got)
+
+	var ats []atRow
+	require.NoError(t, UnmarshalRecord(rec, &ats))
+	assert.Equal(t, []atRow{{0}, {1500}, {60000}}, ats)
+
+	sliced := rec.NewSlice(1, 3)
+	defer sliced.Release()
+	got = nil
+	require.NoError(t, UnmarshalRecord(sliced, &got))
+	require.Len(t, got, 2)
+	assert.Equal(t, int64(2), got[0].ID)
+	assert.Nil(t, got[0].Tags)
+	assert.Equal(t, []string{}, got[1].Tags)
+}
+
+func TestUnmarshalRecordErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i32", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	}, nil)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 0}, []bool{true, false})
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	var (
+		wide []struct {
+			V int64 `arrow:"i32"`
+		}
+		str []struct {
+			V string `arrow:"i32"`
+		}
+		required []struct {
+			V int32 `arrow:"i32"`
+		}
+		notSlice struct {
+			V int32 `arrow:"i32"`
+		}
+	)
+	for _, tc := range []struct {
+		dst  interface{}
+		want string
+	}{
+		{&wide, `cannot unmarshal column "i32" into field V: cannot unmarshal int32 into Go value of type int64`},
+		{&str, `cannot unmarshal int32 into Go value of type string`},
+		{&required, `could not unmarshal row 1 into field V: null value for non-pointer type int32`},
+		{&notSlice, `destination must be a pointer to a slice of structs`},
+		{wide, `destination must be a pointer to a slice of structs`},
+	} {
+		err := UnmarshalRecord(rec, tc.dst)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
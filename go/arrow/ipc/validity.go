@@ -0,0 +1,39 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"github.com/apache/arrow/go/v8/arrow"
+)
+
+// ColumnValidity returns the validity bitmap of the col-th column of rec,
+// without copying it, along with the bit offset of the column's first value
+// in the bitmap and its number of nulls. The bitmap is nil when the column
+// has no nulls, or when, as for null arrays, it has no validity buffer.
+// The bitmap is only valid as long as rec is.
+func ColumnValidity(rec arrow.Record, col int) (bitmap []byte, offset int, nulls int) {
+	arr := rec.Column(col)
+	data := arr.Data()
+	nulls = arr.NullN() // counts the nulls of sliced arrays if needed
+	if nulls == 0 {
+		return nil, data.Offset(), 0
+	}
+	if bufs := data.Buffers(); len(bufs) > 0 && bufs[0] != nil {
+		bitmap = bufs[0].Bytes()
+	}
+	return bitmap, data.Offset(), nulls
+}
@@ -0,0 +1,81 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/bitutil"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnValidity(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i64", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "str", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "null", Type: arrow.Null, Nullable: true},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5}, []bool{true, false, true, true, false})
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c", "d", "e"}, nil)
+	for i := 0; i < 5; i++ {
+		b.Field(2).AppendNull()
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	raw := writeTestFile(t, []arrow.Record{rec})
+	r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+	require.NoError(t, err)
+	defer r.Close()
+
+	got, err := r.RecordAt(0)
+	require.NoError(t, err)
+	defer got.Release()
+
+	for _, rec := range []arrow.Record{got, got.NewSlice(1, 4)} {
+		defer rec.Release()
+
+		bitmap, offset, nulls := ColumnValidity(rec, 0)
+		require.NotNil(t, bitmap)
+		assert.Equal(t, rec.Column(0).NullN(), nulls)
+		assert.Equal(t, rec.Column(0).Data().Offset(), offset)
+		assert.Equal(t, &bitmap[0], &rec.Column(0).NullBitmapBytes()[0], "bitmap should not be copied")
+		for i := 0; i < int(rec.NumRows()); i++ {
+			assert.Equalf(t, rec.Column(0).IsValid(i), bitutil.BitIsSet(bitmap, offset+i), "row %d", i)
+		}
+
+		bitmap, offset, nulls = ColumnValidity(rec, 1)
+		assert.Nil(t, bitmap)
+		assert.Equal(t, rec.Column(1).Data().Offset(), offset)
+		assert.Zero(t, nulls)
+
+		bitmap, _, nulls = ColumnValidity(rec, 2)
+		assert.Nil(t, bitmap)
+		assert.Equal(t, int(rec.NumRows()), nulls)
+	}
+}
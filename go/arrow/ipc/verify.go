@@ -0,0 +1,105 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"golang.org/x/xerrors"
+)
+
+// VerifyBlocks checks that every dictionary and record batch block listed in
+// the footer can be reached, as a fast integrity check before decoding a
+// file which may be truncated or whose footer may over-report its blocks.
+//
+// Only the metadata of each message is read: a block must lie between the
+// leading magic bytes and the footer, start with a valid length prefix
+// consistent with its metadata length, and hold a message of the expected
+// type whose body fits in the block. The first failing block is reported
+// with its index. Bodies are neither read nor decoded.
+func (f *FileReader) VerifyBlocks() error {
+	for i := 0; i < f.NumDictionaries(); i++ {
+		blk, err := f.dict(i)
+		if err != nil {
+			return err
+		}
+		if err := f.verifyBlock(blk, "dictionary", i, MessageDictionaryBatch); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < f.NumRecords(); i++ {
+		blk, err := f.block(i)
+		if err != nil {
+			return err
+		}
+		if err := f.verifyBlock(blk, "record", i, MessageRecordBatch, MessageTensor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyBlock checks the block of the i-th message of the given kind, which
+// must hold one of the given message types.
+func (f *FileReader) verifyBlock(blk fileBlock, kind string, i int, types ...MessageType) (err error) {
+	if err := f.checkBlock(blk, kind, i); err != nil {
+		return err
+	}
+
+	start := paddedLength(int64(len(Magic)), kArrowIPCAlignment)
+	if f.feather || f.relaxed {
+		start = int64(len(Magic))
+	}
+	body := blk.Body
+	if f.extBody != nil && kind == "record" {
+		// the body is read from the external reader.
+		body = 0
+	}
+	if blk.Offset < start || blk.Meta < 4 || blk.Body < 0 || blk.Offset+int64(blk.Meta)+body > f.footer.start {
+		return xerrors.Errorf("arrow/ipc: %s %d block [offset=%d, metadata=%d, body=%d] lies outside of the messages [%d, %d)",
+			kind, i, blk.Offset, blk.Meta, blk.Body, start, f.footer.start)
+	}
+
+	buf := make([]byte, blk.Meta)
+	if _, err := f.r.ReadAt(buf, blk.Offset); err != nil {
+		return xerrors.Errorf("arrow/ipc: could not read metadata of %s %d: %w", kind, i, err)
+	}
+
+	prefix, size, err := messageFraming(buf)
+	if err != nil {
+		return xerrors.Errorf("arrow/ipc: could not read metadata of %s %d: %w", kind, i, err)
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err = xerrors.Errorf("arrow/ipc: invalid metadata of %s %d: %v", kind, i, e)
+		}
+	}()
+
+	msg := flatbuf.GetRootAsMessage(buf[prefix:prefix+size], 0)
+	typ := MessageType(msg.HeaderType())
+	ok := false
+	for _, t := range types {
+		ok = ok || typ == t
+	}
+	if !ok {
+		return xerrors.Errorf("arrow/ipc: %s %d holds a message of type %v", kind, i, typ)
+	}
+	if n := msg.BodyLength(); n < 0 || n > blk.Body {
+		return xerrors.Errorf("arrow/ipc: body length %d of %s %d exceeds its block (body=%d)", n, kind, i, blk.Body)
+	}
+	return nil
+}
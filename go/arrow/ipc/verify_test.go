@@ -0,0 +1,110 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v8/arrow/internal/flatbuf"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderVerifyBlocks(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 3, 5, 7)
+	defer releaseRecords(recs)
+	raw := writeTestFile(t, recs)
+
+	verify := func(t *testing.T, raw []byte) error {
+		r, err := NewFileReader(bytes.NewReader(raw), WithAllocator(mem))
+		require.NoError(t, err)
+		defer r.Close()
+		return r.VerifyBlocks()
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, verify(t, raw))
+		assert.NoError(t, verify(t, appendTensor(t, raw, tensorDesc{
+			hdr:   flatbuf.MessageHeaderTensor,
+			shape: []int64{2},
+			names: []string{""},
+			data:  int32Bytes(1, 2),
+		})))
+	})
+
+	for _, tc := range []struct {
+		name string
+		fn   func([]fileBlock) []fileBlock
+		msg  string
+	}{
+		{
+			name: "over-reported",
+			fn: func(blks []fileBlock) []fileBlock {
+				// the footer claims blocks past the end of the messages,
+				// as left by a truncated copy.
+				last := blks[len(blks)-1]
+				next := last
+				next.Offset += int64(last.Meta) + last.Body
+				return append(blks, next, next)
+			},
+			msg: "record 3 block",
+		},
+		{
+			name: "body",
+			fn: func(blks []fileBlock) []fileBlock {
+				blks[1].Offset = blks[0].Offset + 8
+				return blks
+			},
+			msg: "record 1",
+		},
+		{
+			name: "metadata-length",
+			fn: func(blks []fileBlock) []fileBlock {
+				blks[2].Meta = 16
+				return blks
+			},
+			msg: "invalid message metadata length",
+		},
+		{
+			name: "body-length",
+			fn: func(blks []fileBlock) []fileBlock {
+				blks[0].Body = 0
+				return blks
+			},
+			msg: "body length",
+		},
+		{
+			name: "schema",
+			fn: func(blks []fileBlock) []fileBlock {
+				blks[0] = fileBlock{Offset: 8, Meta: int32(blks[0].Offset - 8)}
+				return blks
+			},
+			msg: "record 0 holds a message of type Schema",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verify(t, rewriteFooter(t, raw, currentMetadataVersion, tc.fn))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.msg)
+		})
+	}
+}
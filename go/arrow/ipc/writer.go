@@ -68,6 +68,8 @@ type Writer struct {
 	schema     *arrow.Schema
 	codec      flatbuf.CompressionType
 	compressNP int
+	zstdDict   []byte
+	threshold  float64
 }
 
 // NewWriterWithPayloadWriter constructs a writer with the provided payload writer
@@ -81,18 +83,23 @@ func NewWriterWithPayloadWriter(pw PayloadWriter, opts ...Option) *Writer {
 		schema:     cfg.schema,
 		codec:      cfg.codec,
 		compressNP: cfg.compressNP,
+		zstdDict:   cfg.zstdDict,
+		threshold:  cfg.threshold,
 	}
 }
 
 // NewWriter returns a writer that writes records to the provided output stream.
+// An unsupported compression codec is reported by Write.
 func NewWriter(w io.Writer, opts ...Option) *Writer {
 	cfg := newConfig(opts...)
 	return &Writer{
-		w:      w,
-		mem:    cfg.alloc,
-		pw:     &swriter{w: w},
-		schema: cfg.schema,
-		codec:  cfg.codec,
+		w:         w,
+		mem:       cfg.alloc,
+		pw:        &swriter{w: w},
+		schema:    cfg.schema,
+		codec:     cfg.codec,
+		zstdDict:  cfg.zstdDict,
+		threshold: cfg.threshold,
 	}
 }
 
@@ -118,6 +125,10 @@ func (w *Writer) Close() error {
 }
 
 func (w *Writer) Write(rec arrow.Record) error {
+	if err := checkCompression(w.codec); err != nil {
+		return err
+	}
+
 	if !w.started {
 		err := w.start()
 		if err != nil {
@@ -133,7 +144,7 @@ func (w *Writer) Write(rec arrow.Record) error {
 	const allow64b = true
 	var (
 		data = Payload{msg: MessageRecordBatch}
-		enc  = newRecordEncoder(w.mem, 0, kMaxNestingDepth, allow64b, w.codec, w.compressNP)
+		enc  = newRecordEncoder(w.mem, 0, kMaxNestingDepth, allow64b, w.codec, w.compressNP, w.zstdDict, w.threshold)
 	)
 	defer data.Release()
 
@@ -172,9 +183,11 @@ type recordEncoder struct {
 	allow64b   bool
 	codec      flatbuf.CompressionType
 	compressNP int
+	zstdDict   []byte
+	threshold  float64 // compression ratio above which buffers are stored uncompressed
 }
 
-func newRecordEncoder(mem memory.Allocator, startOffset, maxDepth int64, allow64b bool, codec flatbuf.CompressionType, compressNP int) *recordEncoder {
+func newRecordEncoder(mem memory.Allocator, startOffset, maxDepth int64, allow64b bool, codec flatbuf.CompressionType, compressNP int, zstdDict []byte, threshold float64) *recordEncoder {
 	return &recordEncoder{
 		mem:        mem,
 		start:      startOffset,
@@ -182,6 +195,8 @@ func newRecordEncoder(mem memory.Allocator, startOffset, maxDepth int64, allow64
 		allow64b:   allow64b,
 		codec:      codec,
 		compressNP: compressNP,
+		zstdDict:   zstdDict,
+		threshold:  threshold,
 	}
 }
 
@@ -202,12 +217,27 @@ func (w *recordEncoder) compressBodyBuffers(p *Payload) error {
 		if err := codec.Close(); err != nil {
 			return err
 		}
+		if float64(buf.Len()-arrow.Int64SizeBytes) >= w.threshold*float64(p.body[idx].Len()) {
+			// compression does not pay off: store the buffer uncompressed,
+			// flagged with an uncompressed size of -1.
+			buf.Reset()
+			if err := binary.Write(&buf, binary.LittleEndian, int64(-1)); err != nil {
+				return err
+			}
+			buf.Write(p.body[idx].Bytes())
+		}
+		// the uncompressed buffer is replaced: release it, lest buffers
+		// allocated by the writer leak for every compressed record.
+		p.body[idx].Release()
 		p.body[idx] = memory.NewBufferBytes(buf.Bytes())
 		return nil
 	}
 
 	if w.compressNP <= 1 {
-		codec := getCompressor(w.codec)
+		codec, err := getCompressor(w.codec, w.zstdDict)
+		if err != nil {
+			return err
+		}
 		for idx := range p.body {
 			if err := compress(idx, codec); err != nil {
 				return err
@@ -216,6 +246,15 @@ func (w *recordEncoder) compressBodyBuffers(p *Payload) error {
 		return nil
 	}
 
+	codecs := make([]compressor, w.compressNP)
+	for i := range codecs {
+		codec, err := getCompressor(w.codec, w.zstdDict)
+		if err != nil {
+			return err
+		}
+		codecs[i] = codec
+	}
+
 	var (
 		wg          sync.WaitGroup
 		ch          = make(chan int)
@@ -224,11 +263,10 @@ func (w *recordEncoder) compressBodyBuffers(p *Payload) error {
 	)
 	defer cancel()
 
-	for i := 0; i < w.compressNP; i++ {
+	for _, codec := range codecs {
 		wg.Add(1)
-		go func() {
+		go func(codec compressor) {
 			defer wg.Done()
-			codec := getCompressor(w.codec)
 			for {
 				select {
 				case idx, ok := <-ch:
@@ -247,7 +285,7 @@ func (w *recordEncoder) compressBodyBuffers(p *Payload) error {
 					return
 				}
 			}
-		}()
+		}(codec)
 	}
 
 	for idx := range p.body {
@@ -272,7 +310,9 @@ func (w *recordEncoder) Encode(p *Payload, rec arrow.Record) error {
 	}
 
 	if w.codec != -1 {
-		w.compressBodyBuffers(p)
+		if err := w.compressBodyBuffers(p); err != nil {
+			return xerrors.Errorf("arrow/ipc: could not compress body buffers: %w", err)
+		}
 	}
 
 	// position for the start of a buffer relative to the passed frame of reference.
@@ -410,7 +450,7 @@ func (w *recordEncoder) visit(p *Payload, arr arrow.Array) error {
 		case needTruncate(int64(data.Offset()), values, totalDataBytes):
 			// slice data buffer to include the range we need now.
 			var (
-				beg = int64(arr.ValueOffset(0))
+				beg = int64(arr.ValueOffsets()[0])
 				len = minI64(paddedLength(totalDataBytes, kArrowAlignment), int64(totalDataBytes))
 			)
 			values = memory.NewBufferBytes(data.Buffers()[2].Bytes()[beg : beg+len])
@@ -485,13 +525,16 @@ func (w *recordEncoder) visit(p *Payload, arr arrow.Array) error {
 		}()
 
 		if voffsets != nil {
-			values_offset = int64(arr.Offsets()[0])
-			values_length = int64(arr.Offsets()[arr.Len()]) - values_offset
+			// the offsets buffer is shared with the parent array: skip
+			// to the entries of this slice.
+			offsets := arr.Offsets()[arr.Data().Offset():]
+			values_offset = int64(offsets[0])
+			values_length = int64(offsets[arr.Len()]) - values_offset
 		}
 
 		if len(arr.Offsets()) != 0 || values_length < int64(values.Len()) {
 			// must also slice the values
-			values = array.NewSlice(values, values_offset, values_length)
+			values = array.NewSlice(values, values_offset, values_offset+values_length)
 			mustRelease = true
 		}
 		err = w.visit(p, values)
@@ -522,13 +565,16 @@ func (w *recordEncoder) visit(p *Payload, arr arrow.Array) error {
 		}()
 
 		if voffsets != nil {
-			values_offset = int64(arr.Offsets()[0])
-			values_length = int64(arr.Offsets()[arr.Len()]) - values_offset
+			// the offsets buffer is shared with the parent array: skip
+			// to the entries of this slice.
+			offsets := arr.Offsets()[arr.Data().Offset():]
+			values_offset = int64(offsets[0])
+			values_length = int64(offsets[arr.Len()]) - values_offset
 		}
 
 		if len(arr.Offsets()) != 0 || values_length < int64(values.Len()) {
 			// must also slice the values
-			values = array.NewSlice(values, values_offset, values_length)
+			values = array.NewSlice(values, values_offset, values_offset+values_length)
 			mustRelease = true
 		}
 		err = w.visit(p, values)
@@ -19,6 +19,7 @@ package ipc
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"testing"
 
 	"github.com/apache/arrow/go/v8/arrow"
@@ -121,3 +122,131 @@ func TestGetZeroBasedValueOffsets(t *testing.T) {
 	defer offsets.Release()
 	assert.Equal(t, 20, offsets.Len(), "trim trailing offsets after slice")
 }
+
+func TestSlicedRecordRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		dt   arrow.DataType
+		vs   string
+	}{
+		{"bool", arrow.FixedWidthTypes.Boolean, `[true, null, false, true, false, null, true, true, false, true]`},
+		{"int64", arrow.PrimitiveTypes.Int64, `[1, 2, null, 4, 5, 6, null, 8, 9, 10]`},
+		{"float32", arrow.PrimitiveTypes.Float32, `[1.5, null, 3, 4, 5, 6, 7, 8, null, 10]`},
+		{"string", arrow.BinaryTypes.String, `["a", "bb", null, "dddd", "", "ffffff", "g", null, "ii", "j"]`},
+		{"binary", arrow.BinaryTypes.Binary, `["YQ==", "YmI=", null, "Y2Nj", "", "ZA==", "ZWU=", null, "Zg==", "Zw=="]`},
+		{"list", arrow.ListOf(arrow.PrimitiveTypes.Int32), `[[1], [2, 3], null, [], [4, 5, 6], [7], null, [8, 9], [10], []]`},
+		{"list<string>", arrow.ListOf(arrow.BinaryTypes.String), `[["a"], ["b", "c"], null, [], ["d", null, "e"], ["f"], null, ["g"], [], ["h"]]`},
+		{"fixed_size_list", arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Int16), `[[1, 2], [3, 4], null, [5, 6], [7, null], [9, 10], [11, 12], null, [15, 16], [17, 18]]`},
+		{"struct", arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			arrow.Field{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+		), `[{"a": 1, "b": "x"}, null, {"a": null, "b": "yy"}, {"a": 4, "b": null}, {"a": 5, "b": "z"}, {"a": 6, "b": "w"}, null, {"a": 8, "b": "v"}, {"a": 9, "b": ""}, {"a": 10, "b": "u"}]`},
+		{"map", arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32), `[[{"key": "a", "value": 1}], [], null, [{"key": "b", "value": 2}, {"key": "c", "value": null}], [{"key": "d", "value": 4}], null, [], [{"key": "e", "value": 5}], [{"key": "f", "value": 6}, {"key": "g", "value": 7}], []]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			arr := columnFromJSON(t, mem, tc.dt, tc.vs)
+			defer arr.Release()
+
+			schema := arrow.NewSchema([]arrow.Field{{Name: "col", Type: tc.dt, Nullable: true}}, nil)
+			rec := array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+			defer rec.Release()
+
+			for _, bounds := range [][2]int64{{0, 10}, {1, 4}, {3, 10}, {5, 5}, {9, 10}} {
+				slice := rec.NewSlice(bounds[0], bounds[1])
+
+				r, err := NewFileReader(bytes.NewReader(writeTestFile(t, []arrow.Record{slice})), WithAllocator(mem))
+				require.NoError(t, err)
+				got, err := r.RecordAt(0)
+				require.NoError(t, err)
+
+				assert.EqualValues(t, slice.NumRows(), got.NumRows(), "bounds %v", bounds)
+				assert.Truef(t, array.ArrayEqual(slice.Column(0), got.Column(0)),
+					"bounds %v:\ngot=  %v\nwant= %v", bounds, got.Column(0), slice.Column(0))
+				assert.Equal(t, slice.Column(0).NullN(), got.Column(0).NullN(), "bounds %v", bounds)
+
+				got.Release()
+				r.Close()
+				slice.Release()
+			}
+		})
+	}
+}
+
+func TestWriterCompressReleasesBuffers(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"lz4", []Option{WithLZ4()}},
+		{"zstd", []Option{WithZstd()}},
+		{"zstd-parallel", []Option{WithZstd(), WithCompressConcurrency(2)}},
+		{"stored-uncompressed", []Option{WithLZ4(), WithCompressionThreshold(0)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			recs := makeTestRecords(t, mem, 100, 200)
+			defer releaseRecords(recs)
+
+			// the buffers replaced by their compressed form must be released.
+			var buf bytes.Buffer
+			w := NewWriter(&buf, append([]Option{WithSchema(testSchema), WithAllocator(mem)}, tc.opts...)...)
+			for _, rec := range recs {
+				require.NoError(t, w.Write(rec))
+			}
+			require.NoError(t, w.Close())
+		})
+	}
+}
+
+func TestWriterUnsupportedCompression(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 10)
+	defer releaseRecords(recs)
+
+	codec := CompressionType(42)
+
+	f, err := ioutil.TempFile(t.TempDir(), "go-arrow-file-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = NewFileWriter(f, WithSchema(testSchema), WithAllocator(mem), WithCompression(codec))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported compression codec CompressionType(42)")
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WithSchema(testSchema), WithAllocator(mem), WithCompression(codec))
+	err = w.Write(recs[0])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported compression codec CompressionType(42)")
+	require.NoError(t, w.Close())
+}
+
+func TestWriterGzipCompression(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	recs := makeTestRecords(t, mem, 10)
+	defer releaseRecords(recs)
+
+	f, err := ioutil.TempFile(t.TempDir(), "go-arrow-file-")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = NewFileWriter(f, WithSchema(testSchema), WithAllocator(mem), WithCompression(CompressionGzip))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GZIP compression is only supported for whole files")
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WithSchema(testSchema), WithAllocator(mem), WithCompression(CompressionGzip))
+	err = w.Write(recs[0])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GZIP compression is only supported for whole files")
+	require.NoError(t, w.Close())
+}